@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/config"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/telegram"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/logger"
+)
+
+const usage = "usage: bot [-skip-migrations] [deleteuser <telegram_user_id> | wrapped-broadcast [year] | migrate up|down|status|redo|version]\n" +
+	"deleteuser only clears the database: restart the running bot so it drops the user's dialog state and cached language"
+
+var migrateCommands = map[string]bool{
+	"up":      true,
+	"down":    true,
+	"status":  true,
+	"redo":    true,
+	"version": true,
+}
+
+// runMigrateCommand only needs DATABASE_URL, so it can run as a release step
+// without the bot token or Redis
+func runMigrateCommand(args []string, appLogger *logger.Logger) error {
+	if len(args) != 1 || !migrateCommands[args[0]] {
+		return errors.New(usage)
+	}
+
+	databaseURL, err := config.LoadDatabaseURL()
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Connect(databaseURL, appLogger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Migrate(args[0])
+}
+
+// runWrappedBroadcast sends the recaps by hand, e.g. for a past year or with
+// WRAPPED_BROADCAST off; users who already got the recap are skipped
+func runWrappedBroadcast(args []string, botToken string, animeService *service.AnimeService, appLogger *logger.Logger) error {
+	year := time.Now().Year()
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		year, err = strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid year %q: %w", args[0], err)
+		}
+	default:
+		return errors.New(usage)
+	}
+
+	bot, err := telegram.NewBot(botToken, animeService, appLogger)
+	if err != nil {
+		return err
+	}
+
+	sent, err := bot.BroadcastWrapped(year)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrapped %d sent to %d users\n", year, sent)
+	return nil
+}
+
+func runCommand(args []string, animeService *service.AnimeService) error {
+	switch args[0] {
+	case "deleteuser":
+		if len(args) != 2 {
+			return errors.New(usage)
+		}
+		userID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid user id %q: %w", args[1], err)
+		}
+
+		deleted, err := animeService.DeleteUserData(userID, service.DeletionSourceCLI)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			fmt.Println("user not found")
+			return nil
+		}
+		fmt.Println("user data deleted; restart the bot to drop its in-memory state for this user")
+		return nil
+	}
+
+	return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+}
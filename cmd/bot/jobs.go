@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/telegram"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/logger"
+)
+
+// runSimilarityJob refreshes "also liked" neighbours once at start-up and then
+// every interval until ctx is cancelled; each run only touches changed titles
+func runSimilarityJob(ctx context.Context, animeService *service.AnimeService, interval time.Duration, appLogger *logger.Logger) {
+	refresh := func() {
+		started := time.Now()
+		updated, err := animeService.RefreshSimilarity(ctx)
+		if err != nil {
+			appLogger.Error("Similarity refresh failed: %v", err)
+			return
+		}
+		if updated > 0 {
+			appLogger.Info("Similarity refreshed for %d anime in %s", updated, time.Since(started))
+		}
+	}
+
+	refresh()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// wrappedCheckInterval is how often the wrapped job looks at the calendar
+const wrappedCheckInterval = time.Hour
+
+// runWrappedJob sends the year recaps while stats.WrappedDue says so. Sent
+// recaps are recorded, so later checks only reach users who got active since
+func runWrappedJob(ctx context.Context, bot *telegram.Bot, appLogger *logger.Logger) {
+	broadcast := func() {
+		year, due := stats.WrappedDue(time.Now())
+		if !due {
+			return
+		}
+		if _, err := bot.BroadcastWrapped(year); err != nil {
+			appLogger.Error("Wrapped broadcast failed: %v", err)
+		}
+	}
+
+	broadcast()
+
+	ticker := time.NewTicker(wrappedCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			broadcast()
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/cache"
@@ -15,6 +18,10 @@ import (
 )
 
 func main() {
+	skipMigrations := flag.Bool("skip-migrations", false, "do not apply pending migrations on start-up")
+	flag.Parse()
+	args := flag.Args()
+
 	appLogger := logger.New()
 
 	if os.Getenv("RAILWAY_ENVIRONMENT_NAME") == "" {
@@ -28,6 +35,14 @@ func main() {
 	appLogger.Info("RAILWAY_SERVICE_ID: %s", os.Getenv("RAILWAY_SERVICE_ID"))
 	appLogger.Info("RAILWAY_PROJECT_ID: %s", os.Getenv("RAILWAY_PROJECT_ID"))
 
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrateCommand(args[1:], appLogger); err != nil {
+			appLogger.Error("Migrate failed: %v", err)
+			log.Fatal(err)
+		}
+		return
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		appLogger.Error("Failed to load config: %v", err)
@@ -46,14 +61,18 @@ func main() {
 	}
 	defer db.Close()
 
-	if err := db.RunMigrations("./migrations"); err != nil {
-		appLogger.Error("Failed to run migrations: %v", err)
-		log.Fatal(err)
-	}
+	if *skipMigrations {
+		appLogger.Info("Database connected, skipping migrations")
+	} else {
+		if err := db.Migrate("up"); err != nil {
+			appLogger.Error("Failed to run migrations: %v", err)
+			log.Fatal(err)
+		}
 
-	appLogger.Info("Database connected and migrations applied")
+		appLogger.Info("Database connected and migrations applied")
 
-	log.Println("Database connected and migrations applied")
+		log.Println("Database connected and migrations applied")
+	}
 
 	redisCache, err := cache.New(cfg.RedisURL, appLogger)
 	if err != nil {
@@ -66,12 +85,37 @@ func main() {
 	shikiClient := shikimori.NewClient(cfg.ShikimoriURL)
 	animeService := service.NewAnimeService(shikiClient, repo, redisCache)
 
+	if len(args) > 0 && args[0] == "wrapped-broadcast" {
+		if err := runWrappedBroadcast(args[1:], cfg.BotToken, animeService, appLogger); err != nil {
+			appLogger.Error("Wrapped broadcast failed: %v", err)
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(args) > 0 {
+		if err := runCommand(args, animeService); err != nil {
+			appLogger.Error("Command failed: %v", err)
+			log.Fatal(err)
+		}
+		return
+	}
+
 	bot, err := telegram.NewBot(cfg.BotToken, animeService, appLogger)
 	if err != nil {
 		appLogger.Error("Failed to create bot: %v", err)
 		log.Fatal("Failed to create bot:", err)
 	}
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.SimilarityInterval > 0 {
+		go runSimilarityJob(jobCtx, animeService, cfg.SimilarityInterval, appLogger)
+	}
+	if cfg.WrappedBroadcast {
+		go runWrappedJob(jobCtx, bot, appLogger)
+	}
+
 	log.Printf("Bot started successfully")
 
 	if err := bot.Start(); err != nil {
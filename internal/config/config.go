@@ -3,19 +3,36 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
+const defaultSimilarityInterval = time.Hour
+
 type Config struct {
 	DatabaseURL  string
 	BotToken     string
 	RedisURL     string
 	ShikimoriURL string
+	// SimilarityInterval is how often neighbour lists are refreshed; zero disables the job
+	SimilarityInterval time.Duration
+	// WrappedBroadcast sends the year recaps from inside the bot at year end
+	WrappedBroadcast bool
 }
 
-func Load() (*Config, error) {
+// LoadDatabaseURL is enough for commands that only touch the database, like migrate
+func LoadDatabaseURL() (string, error) {
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL is required")
+		return "", fmt.Errorf("DATABASE_URL is required")
+	}
+	return databaseURL, nil
+}
+
+func Load() (*Config, error) {
+	databaseURL, err := LoadDatabaseURL()
+	if err != nil {
+		return nil, err
 	}
 
 	botToken := os.Getenv("BOT_TOKEN")
@@ -33,10 +50,28 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("SHIKIMORI_URL is required")
 	}
 
+	similarityInterval := defaultSimilarityInterval
+	if value := os.Getenv("SIMILARITY_INTERVAL"); value != "" {
+		similarityInterval, err = time.ParseDuration(value)
+		if err != nil || similarityInterval < 0 {
+			return nil, fmt.Errorf("invalid SIMILARITY_INTERVAL: %q", value)
+		}
+	}
+
+	wrappedBroadcast := true
+	if value := os.Getenv("WRAPPED_BROADCAST"); value != "" {
+		wrappedBroadcast, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WRAPPED_BROADCAST: %q", value)
+		}
+	}
+
 	return &Config{
-		DatabaseURL:  databaseURL,
-		BotToken:     botToken,
-		RedisURL:     redisURL,
-		ShikimoriURL: shikimoriURL,
+		DatabaseURL:        databaseURL,
+		BotToken:           botToken,
+		RedisURL:           redisURL,
+		ShikimoriURL:       shikimoriURL,
+		SimilarityInterval: similarityInterval,
+		WrappedBroadcast:   wrappedBroadcast,
 	}, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad_AllEnvVarsSet(t *testing.T) {
@@ -231,3 +232,64 @@ func TestLoad_MultipleConsecutiveCalls(t *testing.T) {
 		t.Error("expected same config on consecutive loads")
 	}
 }
+
+func TestLoadDatabaseURL_OnlyDatabaseRequired(t *testing.T) {
+	os.Setenv("DATABASE_URL", "sqlite://bot.db")
+	defer os.Unsetenv("DATABASE_URL")
+
+	url, err := LoadDatabaseURL()
+	if err != nil || url != "sqlite://bot.db" {
+		t.Fatalf("expected database url, got %q (%v)", url, err)
+	}
+
+	os.Unsetenv("DATABASE_URL")
+	if _, err := LoadDatabaseURL(); err == nil {
+		t.Error("expected error without DATABASE_URL")
+	}
+}
+
+func TestLoad_SimilarityInterval(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/db")
+	t.Setenv("BOT_TOKEN", "token")
+	t.Setenv("REDIS_URL", "redis://url")
+	t.Setenv("SHIKIMORI_URL", "https://api")
+
+	cfg, err := Load()
+	if err != nil || cfg.SimilarityInterval != time.Hour {
+		t.Fatalf("expected default interval, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("SIMILARITY_INTERVAL", "0")
+	cfg, err = Load()
+	if err != nil || cfg.SimilarityInterval != 0 {
+		t.Fatalf("expected disabled job, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("SIMILARITY_INTERVAL", "soon")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for invalid interval")
+	}
+}
+
+func TestLoad_WrappedBroadcast(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/db")
+	t.Setenv("BOT_TOKEN", "token")
+	t.Setenv("REDIS_URL", "redis://url")
+	t.Setenv("SHIKIMORI_URL", "https://api")
+
+	cfg, err := Load()
+	if err != nil || !cfg.WrappedBroadcast {
+		t.Fatalf("expected broadcast on by default, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("WRAPPED_BROADCAST", "false")
+	cfg, err = Load()
+	if err != nil || cfg.WrappedBroadcast {
+		t.Fatalf("expected broadcast off, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("WRAPPED_BROADCAST", "sometimes")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for invalid flag")
+	}
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const AuditActionDeleteUser = "delete_user"
+
+func (r *Repository) DeleteUser(userID int64, source string) (bool, error) {
+	return r.DeleteUserContext(context.Background(), userID, source)
+}
+
+func (r *Repository) DeleteUserContext(ctx context.Context, userID int64, source string) (bool, error) {
+	deleted := false
+
+	// favorites, ratings, notes, rating history and collections go with the
+	// user row through ON DELETE CASCADE
+	err := r.inTx(ctx, func(tx *Repository) error {
+		var items int
+		countQuery := `
+			SELECT
+				(SELECT COUNT(*) FROM favorites WHERE user_id = $1) +
+				(SELECT COUNT(*) FROM ratings WHERE user_id = $1) +
+				(SELECT COUNT(*) FROM notes WHERE user_id = $1) +
+				(SELECT COUNT(*) FROM collections WHERE user_id = $1)
+		`
+		if err := sqlx.GetContext(ctx, tx.ext(), &items, countQuery, userID); err != nil {
+			return fmt.Errorf("failed to count user data: %w", err)
+		}
+
+		res, err := tx.ext().ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+		if err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		if affected == 0 {
+			return nil
+		}
+
+		auditQuery := `
+			INSERT INTO audit_log (action, source, items_deleted, created_at)
+			VALUES ($1, $2, $3, $4)
+		`
+		if _, err := tx.ext().ExecContext(ctx, auditQuery, AuditActionDeleteUser, source, items, time.Now()); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+
+		deleted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,233 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+type animeRow struct {
+	ID            int       `db:"id"`
+	Name          string    `db:"name"`
+	Russian       string    `db:"russian"`
+	Kind          string    `db:"kind"`
+	Score         string    `db:"score"`
+	Status        string    `db:"status"`
+	Episodes      int       `db:"episodes"`
+	Duration      int       `db:"duration"`
+	AiredOn       string    `db:"aired_on"`
+	ReleasedOn    string    `db:"released_on"`
+	Description   string    `db:"description"`
+	ImageOriginal string    `db:"image_original"`
+	ImagePreview  string    `db:"image_preview"`
+	ImageX96      string    `db:"image_x96"`
+	FetchedAt     time.Time `db:"fetched_at"`
+}
+
+func (row animeRow) toModel() models.Anime {
+	return models.Anime{
+		ID:          row.ID,
+		Name:        row.Name,
+		Russian:     row.Russian,
+		Kind:        row.Kind,
+		Score:       row.Score,
+		Status:      row.Status,
+		Episodes:    row.Episodes,
+		Duration:    row.Duration,
+		AiredOn:     row.AiredOn,
+		ReleasedOn:  row.ReleasedOn,
+		Description: row.Description,
+		Image: models.AnimeImage{
+			Original: row.ImageOriginal,
+			Preview:  row.ImagePreview,
+			X96:      row.ImageX96,
+		},
+	}
+}
+
+type genreRow struct {
+	ID        int    `db:"id"`
+	Name      string `db:"name"`
+	Russian   string `db:"russian"`
+	Kind      string `db:"kind"`
+	EntryType string `db:"entry_type"`
+}
+
+func (r *Repository) UpsertAnime(anime models.Anime, fetchedAt time.Time) error {
+	return r.UpsertAnimeContext(context.Background(), anime, fetchedAt)
+}
+
+func (r *Repository) UpsertAnimeContext(ctx context.Context, anime models.Anime, fetchedAt time.Time) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		return tx.upsertAnime(ctx, anime, fetchedAt)
+	})
+}
+
+func (r *Repository) upsertAnime(ctx context.Context, anime models.Anime, fetchedAt time.Time) error {
+	animeQuery := `
+		INSERT INTO animes (id, name, russian, kind, score, status, episodes, duration,
+			aired_on, released_on, description, image_original, image_preview, image_x96, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			russian = EXCLUDED.russian,
+			kind = EXCLUDED.kind,
+			score = EXCLUDED.score,
+			status = EXCLUDED.status,
+			episodes = EXCLUDED.episodes,
+			duration = EXCLUDED.duration,
+			aired_on = EXCLUDED.aired_on,
+			released_on = EXCLUDED.released_on,
+			description = EXCLUDED.description,
+			image_original = EXCLUDED.image_original,
+			image_preview = EXCLUDED.image_preview,
+			image_x96 = EXCLUDED.image_x96,
+			fetched_at = EXCLUDED.fetched_at
+	`
+	_, err := r.ext().ExecContext(ctx, animeQuery,
+		anime.ID,
+		anime.Name,
+		anime.Russian,
+		anime.Kind,
+		anime.Score,
+		anime.Status,
+		anime.Episodes,
+		anime.Duration,
+		anime.AiredOn,
+		anime.ReleasedOn,
+		anime.Description,
+		anime.Image.Original,
+		anime.Image.Preview,
+		anime.Image.X96,
+		fetchedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert anime: %w", err)
+	}
+
+	if _, err := r.ext().ExecContext(ctx, `DELETE FROM anime_genres WHERE anime_id = $1`, anime.ID); err != nil {
+		return fmt.Errorf("failed to clear anime genres: %w", err)
+	}
+
+	genreQuery := `
+		INSERT INTO genres (id, name, russian, kind, entry_type)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			russian = EXCLUDED.russian,
+			kind = EXCLUDED.kind,
+			entry_type = EXCLUDED.entry_type
+	`
+	linkQuery := `
+		INSERT INTO anime_genres (anime_id, genre_id, position)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (anime_id, genre_id) DO NOTHING
+	`
+	for i, genre := range anime.Genres {
+		if _, err := r.ext().ExecContext(ctx, genreQuery, genre.ID, genre.Name, genre.Russian, genre.Kind, genre.EntryType); err != nil {
+			return fmt.Errorf("failed to upsert genre: %w", err)
+		}
+		if _, err := r.ext().ExecContext(ctx, linkQuery, anime.ID, genre.ID, i); err != nil {
+			return fmt.Errorf("failed to link genre: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) GetCatalogAnime(id int) (*models.Anime, time.Time, error) {
+	return r.GetCatalogAnimeContext(context.Background(), id)
+}
+
+func (r *Repository) GetCatalogAnimeContext(ctx context.Context, id int) (*models.Anime, time.Time, error) {
+	var row animeRow
+	query := `
+		SELECT id, name, russian, kind, score, status, episodes, duration,
+			aired_on, released_on, description, image_original, image_preview, image_x96, fetched_at
+		FROM animes
+		WHERE id = $1
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &row, query, id)
+	// a missing snapshot is a regular miss, not an error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get catalog anime: %w", err)
+	}
+
+	var genres []genreRow
+	genresQuery := `
+		SELECT g.id, g.name, g.russian, g.kind, g.entry_type
+		FROM genres g
+		JOIN anime_genres ag ON ag.genre_id = g.id
+		WHERE ag.anime_id = $1
+		ORDER BY ag.position
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &genres, genresQuery, id); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get catalog genres: %w", err)
+	}
+
+	anime := row.toModel()
+	for _, g := range genres {
+		anime.Genres = append(anime.Genres, models.Genre{
+			ID:        g.ID,
+			Name:      g.Name,
+			Russian:   g.Russian,
+			Kind:      g.Kind,
+			EntryType: g.EntryType,
+		})
+	}
+
+	return &anime, row.FetchedAt, nil
+}
+
+func (r *Repository) CountFavoritesByGenre(userID int64) ([]models.GenreCount, error) {
+	return r.CountFavoritesByGenreContext(context.Background(), userID)
+}
+
+func (r *Repository) CountFavoritesByGenreContext(ctx context.Context, userID int64) ([]models.GenreCount, error) {
+	var counts []models.GenreCount
+	query := `
+		SELECT g.id AS genre_id, g.name, g.russian, COUNT(*) AS count
+		FROM favorites f
+		JOIN anime_genres ag ON ag.anime_id = f.anime_id
+		JOIN genres g ON g.id = ag.genre_id
+		WHERE f.user_id = $1
+		GROUP BY g.id, g.name, g.russian
+		ORDER BY count DESC, g.name
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &counts, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count favorites by genre: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *Repository) GetFavoritesByKind(userID int64, kind string) ([]models.Favorite, error) {
+	return r.GetFavoritesByKindContext(context.Background(), userID, kind)
+}
+
+func (r *Repository) GetFavoritesByKindContext(ctx context.Context, userID int64, kind string) ([]models.Favorite, error) {
+	var favorites []models.Favorite
+	query := `
+		SELECT f.id, f.user_id, f.anime_id, f.title, f.poster_url, f.added_at
+		FROM favorites f
+		JOIN animes a ON a.id = f.anime_id
+		WHERE f.user_id = $1 AND a.kind = $2
+		ORDER BY f.added_at DESC
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &favorites, query, userID, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites by kind: %w", err)
+	}
+	return favorites, nil
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestUpsertAnime_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+	anime := models.Anime{
+		ID:      1,
+		Name:    "Death Note",
+		Russian: "Тетрадь смерти",
+		Kind:    "tv",
+		Genres: []models.Genre{
+			{ID: 7, Name: "Mystery", Russian: "Детектив"},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO animes`)).
+		WithArgs(anime.ID, anime.Name, anime.Russian, anime.Kind, "", "", 0, 0, "", "", "", "", "", "", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM anime_genres WHERE anime_id = $1`)).
+		WithArgs(anime.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO genres`)).
+		WithArgs(7, "Mystery", "Детектив", "", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO anime_genres`)).
+		WithArgs(anime.ID, 7, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	if err := repo.UpsertAnime(anime, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpsertAnime_RollbackOnGenreError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	anime := models.Anime{ID: 1, Genres: []models.Genre{{ID: 7}}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO animes`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM anime_genres`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO genres`)).
+		WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+
+	if err := repo.UpsertAnime(anime, time.Now()); err == nil {
+		t.Fatal("expected error but got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetCatalogAnime_Found(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	fetchedAt := time.Now().Add(-time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "russian", "kind", "score", "status", "episodes", "duration",
+		"aired_on", "released_on", "description", "image_original", "image_preview", "image_x96", "fetched_at"}).
+		AddRow(1, "Death Note", "Тетрадь смерти", "tv", "8.6", "released", 37, 23,
+			"2006-10-04", "2007-06-27", "desc", "/orig.jpg", "/prev.jpg", "/x96.jpg", fetchedAt)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM animes`)).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	genreRows := sqlmock.NewRows([]string{"id", "name", "russian", "kind", "entry_type"}).
+		AddRow(7, "Mystery", "Детектив", "genre", "Anime")
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM genres g`)).
+		WithArgs(1).
+		WillReturnRows(genreRows)
+
+	anime, gotFetchedAt, err := repo.GetCatalogAnime(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if anime == nil || anime.Russian != "Тетрадь смерти" || anime.Duration != 23 {
+		t.Fatalf("unexpected anime: %+v", anime)
+	}
+	if anime.Image.Original != "/orig.jpg" || anime.Image.X96 != "/x96.jpg" {
+		t.Errorf("expected image paths, got %+v", anime.Image)
+	}
+	if len(anime.Genres) != 1 || anime.Genres[0].Russian != "Детектив" {
+		t.Errorf("unexpected genres: %+v", anime.Genres)
+	}
+	if !gotFetchedAt.Equal(fetchedAt) {
+		t.Errorf("expected fetched_at %v, got %v", fetchedAt, gotFetchedAt)
+	}
+}
+
+func TestGetCatalogAnime_Missing(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM animes`)).
+		WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	anime, _, err := repo.GetCatalogAnime(42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if anime != nil {
+		t.Errorf("expected nil for missing anime, got %+v", anime)
+	}
+}
+
+func TestCountFavoritesByGenre(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+
+	rows := sqlmock.NewRows([]string{"genre_id", "name", "russian", "count"}).
+		AddRow(7, "Mystery", "Детектив", 3).
+		AddRow(2, "Drama", "Драма", 1)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM favorites f`)).
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	counts, err := repo.CountFavoritesByGenre(userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(counts) != 2 || counts[0].Count != 3 {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const chatWatchlistColumns = `w.chat_id, w.anime_id, w.title, w.added_by,
+	COALESCE(u.username, '') AS added_by_name, w.added_at`
+
+// AddToChatWatchlist reports false when the anime is already on the chat's list
+func (r *Repository) AddToChatWatchlist(item models.ChatWatchlistItem) (bool, error) {
+	return r.AddToChatWatchlistContext(context.Background(), item)
+}
+
+func (r *Repository) AddToChatWatchlistContext(ctx context.Context, item models.ChatWatchlistItem) (bool, error) {
+	query := `
+		INSERT INTO chat_watchlist (chat_id, anime_id, title, added_by, added_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chat_id, anime_id) DO NOTHING
+	`
+
+	res, err := r.ext().ExecContext(ctx, query, item.ChatID, item.AnimeID, item.Title, item.AddedBy, item.AddedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to add to chat watchlist: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add to chat watchlist: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) RemoveFromChatWatchlist(chatID int64, animeID int) error {
+	return r.RemoveFromChatWatchlistContext(context.Background(), chatID, animeID)
+}
+
+func (r *Repository) RemoveFromChatWatchlistContext(ctx context.Context, chatID int64, animeID int) error {
+	query := `DELETE FROM chat_watchlist WHERE chat_id = $1 AND anime_id = $2`
+
+	if _, err := r.ext().ExecContext(ctx, query, chatID, animeID); err != nil {
+		return fmt.Errorf("failed to remove from chat watchlist: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetChatWatchlist(chatID int64) ([]models.ChatWatchlistItem, error) {
+	return r.GetChatWatchlistContext(context.Background(), chatID)
+}
+
+func (r *Repository) GetChatWatchlistContext(ctx context.Context, chatID int64) ([]models.ChatWatchlistItem, error) {
+	var items []models.ChatWatchlistItem
+	query := `
+		SELECT ` + chatWatchlistColumns + `
+		FROM chat_watchlist w
+		LEFT JOIN users u ON u.id = w.added_by
+		WHERE w.chat_id = $1
+		ORDER BY w.added_at, w.anime_id
+	`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &items, query, chatID); err != nil {
+		return nil, fmt.Errorf("failed to get chat watchlist: %w", err)
+	}
+	return items, nil
+}
+
+func (r *Repository) GetChatWatchlistItem(chatID int64, animeID int) (*models.ChatWatchlistItem, error) {
+	return r.GetChatWatchlistItemContext(context.Background(), chatID, animeID)
+}
+
+func (r *Repository) GetChatWatchlistItemContext(ctx context.Context, chatID int64, animeID int) (*models.ChatWatchlistItem, error) {
+	var item models.ChatWatchlistItem
+	query := `
+		SELECT ` + chatWatchlistColumns + `
+		FROM chat_watchlist w
+		LEFT JOIN users u ON u.id = w.added_by
+		WHERE w.chat_id = $1 AND w.anime_id = $2
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &item, query, chatID, animeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat watchlist item: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *Repository) MoveChatWatchlist(fromChatID int64, toChatID int64) error {
+	return r.MoveChatWatchlistContext(context.Background(), fromChatID, toChatID)
+}
+
+// MoveChatWatchlistContext follows a group that was upgraded to a supergroup
+// and got a new chat id; its known members move along with the list
+func (r *Repository) MoveChatWatchlistContext(ctx context.Context, fromChatID int64, toChatID int64) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		query := `UPDATE chat_watchlist SET chat_id = $2 WHERE chat_id = $1`
+		if _, err := tx.ext().ExecContext(ctx, query, fromChatID, toChatID); err != nil {
+			return fmt.Errorf("failed to move chat watchlist: %w", err)
+		}
+
+		query = `UPDATE chat_members SET chat_id = $2 WHERE chat_id = $1`
+		if _, err := tx.ext().ExecContext(ctx, query, fromChatID, toChatID); err != nil {
+			return fmt.Errorf("failed to move chat members: %w", err)
+		}
+		return nil
+	})
+}
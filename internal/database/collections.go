@@ -0,0 +1,249 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var ErrCollectionExists = fmt.Errorf("collection with this name already exists: %w", ErrConflict)
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	return false
+}
+
+func (r *Repository) CreateCollection(collection models.Collection) (int, error) {
+	return r.CreateCollectionContext(context.Background(), collection)
+}
+
+func (r *Repository) CreateCollectionContext(ctx context.Context, collection models.Collection) (int, error) {
+	var id int
+	query := `
+		INSERT INTO collections (user_id, name, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &id, query, collection.UserID, collection.Name, collection.CreatedAt)
+	if isUniqueViolation(err) {
+		return 0, ErrCollectionExists
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to create collection: %w", err)
+	}
+	return id, nil
+}
+
+func (r *Repository) GetCollections(userID int64) ([]models.Collection, error) {
+	return r.GetCollectionsContext(context.Background(), userID)
+}
+
+func (r *Repository) GetCollectionsContext(ctx context.Context, userID int64) ([]models.Collection, error) {
+	var collections []models.Collection
+	query := `
+		SELECT c.id, c.user_id, c.name, c.created_at, COUNT(ci.anime_id) AS item_count
+		FROM collections c
+		LEFT JOIN collection_items ci ON ci.collection_id = c.id
+		WHERE c.user_id = $1
+		GROUP BY c.id, c.user_id, c.name, c.created_at
+		ORDER BY c.created_at, c.id
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &collections, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collections: %w", err)
+	}
+	return collections, nil
+}
+
+func (r *Repository) GetCollection(userID int64, collectionID int) (*models.Collection, error) {
+	return r.GetCollectionContext(context.Background(), userID, collectionID)
+}
+
+func (r *Repository) GetCollectionContext(ctx context.Context, userID int64, collectionID int) (*models.Collection, error) {
+	var collection models.Collection
+	query := `
+		SELECT c.id, c.user_id, c.name, c.created_at,
+			(SELECT COUNT(*) FROM collection_items ci WHERE ci.collection_id = c.id) AS item_count
+		FROM collections c
+		WHERE c.id = $1 AND c.user_id = $2
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &collection, query, collectionID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return &collection, nil
+}
+
+func (r *Repository) RenameCollection(userID int64, collectionID int, name string) error {
+	return r.RenameCollectionContext(context.Background(), userID, collectionID, name)
+}
+
+func (r *Repository) RenameCollectionContext(ctx context.Context, userID int64, collectionID int, name string) error {
+	query := `UPDATE collections SET name = $1 WHERE id = $2 AND user_id = $3`
+
+	_, err := r.ext().ExecContext(ctx, query, name, collectionID, userID)
+	if isUniqueViolation(err) {
+		return ErrCollectionExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rename collection: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteCollection(userID int64, collectionID int) error {
+	return r.DeleteCollectionContext(context.Background(), userID, collectionID)
+}
+
+func (r *Repository) DeleteCollectionContext(ctx context.Context, userID int64, collectionID int) error {
+	query := `DELETE FROM collections WHERE id = $1 AND user_id = $2`
+
+	_, err := r.ext().ExecContext(ctx, query, collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) AddToCollection(item models.CollectionItem) error {
+	return r.AddToCollectionContext(context.Background(), item)
+}
+
+func (r *Repository) AddToCollectionContext(ctx context.Context, item models.CollectionItem) error {
+	query := `
+		INSERT INTO collection_items (collection_id, anime_id, title, poster_url, added_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (collection_id, anime_id) DO NOTHING
+	`
+	_, err := r.ext().ExecContext(ctx, query,
+		item.CollectionID,
+		item.AnimeID,
+		item.Title,
+		item.PosterURL,
+		item.AddedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add to collection: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) RemoveFromCollection(collectionID int, animeID int) error {
+	return r.RemoveFromCollectionContext(context.Background(), collectionID, animeID)
+}
+
+func (r *Repository) RemoveFromCollectionContext(ctx context.Context, collectionID int, animeID int) error {
+	query := `DELETE FROM collection_items WHERE collection_id = $1 AND anime_id = $2`
+
+	_, err := r.ext().ExecContext(ctx, query, collectionID, animeID)
+	if err != nil {
+		return fmt.Errorf("failed to remove from collection: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetCollectionItems(collectionID int) ([]models.CollectionItem, error) {
+	return r.GetCollectionItemsContext(context.Background(), collectionID)
+}
+
+func (r *Repository) GetCollectionItemsContext(ctx context.Context, collectionID int) ([]models.CollectionItem, error) {
+	var items []models.CollectionItem
+	query := `
+		SELECT collection_id, anime_id, title, poster_url, added_at
+		FROM collection_items
+		WHERE collection_id = $1
+		ORDER BY added_at DESC
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &items, query, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection items: %w", err)
+	}
+	return items, nil
+}
+
+// CollectionCursor points at the last item of a page; pages run newest first,
+// with anime_id breaking ties between items added at the same moment
+type CollectionCursor struct {
+	AddedAt time.Time
+	AnimeID int
+}
+
+func CollectionCursorAfter(item models.CollectionItem) *CollectionCursor {
+	return &CollectionCursor{AddedAt: item.AddedAt, AnimeID: item.AnimeID}
+}
+
+func (r *Repository) GetCollectionItemsPage(collectionID int, after *CollectionCursor, limit int) ([]models.CollectionItem, error) {
+	return r.GetCollectionItemsPageContext(context.Background(), collectionID, after, limit)
+}
+
+func (r *Repository) GetCollectionItemsPageContext(ctx context.Context, collectionID int, after *CollectionCursor, limit int) ([]models.CollectionItem, error) {
+	where := "collection_id = $1"
+	args := []interface{}{collectionID}
+	if after != nil {
+		args = append(args, after.AddedAt, after.AnimeID)
+		where += " AND (added_at < $2 OR (added_at = $2 AND anime_id < $3))"
+	}
+
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+	args = append(args, limit)
+
+	var items []models.CollectionItem
+	query := fmt.Sprintf(`
+		SELECT collection_id, anime_id, title, poster_url, added_at
+		FROM collection_items
+		WHERE %s
+		ORDER BY added_at DESC, anime_id DESC
+		LIMIT $%d
+	`, where, len(args))
+
+	err := sqlx.SelectContext(ctx, r.ext(), &items, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection page: %w", err)
+	}
+	return items, nil
+}
+
+func (r *Repository) GetAnimeCollectionIDs(userID int64, animeID int) ([]int, error) {
+	return r.GetAnimeCollectionIDsContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) GetAnimeCollectionIDsContext(ctx context.Context, userID int64, animeID int) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT ci.collection_id
+		FROM collection_items ci
+		JOIN collections c ON c.id = ci.collection_id
+		WHERE c.user_id = $1 AND ci.anime_id = $2
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &ids, query, userID, animeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get anime collections: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestCreateCollection_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO collections`)).
+		WithArgs(int64(123), "comfort rewatch", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	id, err := repo.CreateCollection(models.Collection{UserID: 123, Name: "comfort rewatch", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if id != 7 {
+		t.Errorf("expected id 7, got %d", id)
+	}
+}
+
+func TestCreateCollection_Duplicate(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO collections`)).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err := repo.CreateCollection(models.Collection{UserID: 123, Name: "dup", CreatedAt: time.Now()})
+	if err != ErrCollectionExists {
+		t.Errorf("expected ErrCollectionExists, got %v", err)
+	}
+}
+
+func TestGetCollections_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "created_at", "item_count"}).
+		AddRow(1, int64(123), "watch with friends", now, 3).
+		AddRow(2, int64(123), "comfort rewatch", now, 0)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM collections c`)).
+		WithArgs(int64(123)).
+		WillReturnRows(rows)
+
+	collections, err := repo.GetCollections(123)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(collections) != 2 || collections[0].ItemCount != 3 {
+		t.Errorf("unexpected collections: %+v", collections)
+	}
+}
+
+func TestGetCollection_NotOwned(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM collections c`)).
+		WithArgs(1, int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "created_at", "item_count"}))
+
+	collection, err := repo.GetCollection(999, 1)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if collection != nil {
+		t.Errorf("expected nil for foreign collection, got %+v", collection)
+	}
+}
+
+func TestRenameCollection_Duplicate(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE collections SET name = $1 WHERE id = $2 AND user_id = $3`)).
+		WithArgs("dup", 1, int64(123)).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	if err := repo.RenameCollection(123, 1, "dup"); err != ErrCollectionExists {
+		t.Errorf("expected ErrCollectionExists, got %v", err)
+	}
+}
+
+func TestDeleteCollection_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM collections WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, int64(123)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.DeleteCollection(123, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAddToCollection_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+	item := models.CollectionItem{CollectionID: 1, AnimeID: 5, Title: "Naruto", AddedAt: now}
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO collection_items`)).
+		WithArgs(1, 5, "Naruto", "", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.AddToCollection(item); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetCollectionItems_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"collection_id", "anime_id", "title", "poster_url", "added_at"}).
+		AddRow(1, 5, "Naruto", "", now).
+		AddRow(1, 6, "Bleach", "", now)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM collection_items`)).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	items, err := repo.GetCollectionItems(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(items) != 2 || items[1].Title != "Bleach" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestGetAnimeCollectionIDs(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ci.collection_id`)).
+		WithArgs(int64(123), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"collection_id"}).AddRow(1).AddRow(3))
+
+	ids, err := repo.GetAnimeCollectionIDs(123, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ids) != 2 || ids[1] != 3 {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
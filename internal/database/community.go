@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// anime_rating_stats is maintained by triggers on ratings, so reads never aggregate
+const communityRatingColumns = `s.anime_id, s.votes, s.average,
+	s.score_1, s.score_2, s.score_3, s.score_4, s.score_5,
+	s.score_6, s.score_7, s.score_8, s.score_9, s.score_10,
+	COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), '') AS title`
+
+type communityRatingRow struct {
+	AnimeID int     `db:"anime_id"`
+	Title   string  `db:"title"`
+	Votes   int     `db:"votes"`
+	Average float64 `db:"average"`
+	Score1  int     `db:"score_1"`
+	Score2  int     `db:"score_2"`
+	Score3  int     `db:"score_3"`
+	Score4  int     `db:"score_4"`
+	Score5  int     `db:"score_5"`
+	Score6  int     `db:"score_6"`
+	Score7  int     `db:"score_7"`
+	Score8  int     `db:"score_8"`
+	Score9  int     `db:"score_9"`
+	Score10 int     `db:"score_10"`
+}
+
+func (row communityRatingRow) toModel() models.CommunityRating {
+	counts := []int{row.Score1, row.Score2, row.Score3, row.Score4, row.Score5,
+		row.Score6, row.Score7, row.Score8, row.Score9, row.Score10}
+
+	distribution := make(map[int]int)
+	for i, count := range counts {
+		if count > 0 {
+			distribution[i+1] = count
+		}
+	}
+
+	return models.CommunityRating{
+		AnimeID:      row.AnimeID,
+		Title:        row.Title,
+		Votes:        row.Votes,
+		Average:      row.Average,
+		Distribution: distribution,
+	}
+}
+
+func (r *Repository) GetCommunityRating(animeID int) (*models.CommunityRating, error) {
+	return r.GetCommunityRatingContext(context.Background(), animeID)
+}
+
+func (r *Repository) GetCommunityRatingContext(ctx context.Context, animeID int) (*models.CommunityRating, error) {
+	var row communityRatingRow
+	query := `
+		SELECT ` + communityRatingColumns + `
+		FROM anime_rating_stats s
+		LEFT JOIN animes a ON a.id = s.anime_id
+		WHERE s.anime_id = $1
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &row, query, animeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community rating: %w", err)
+	}
+
+	rating := row.toModel()
+	return &rating, nil
+}
+
+func (r *Repository) GetCommunityTop(minVotes int, limit int) ([]models.CommunityRating, error) {
+	return r.GetCommunityTopContext(context.Background(), minVotes, limit)
+}
+
+func (r *Repository) GetCommunityTopContext(ctx context.Context, minVotes int, limit int) ([]models.CommunityRating, error) {
+	var rows []communityRatingRow
+	query := `
+		SELECT ` + communityRatingColumns + `
+		FROM anime_rating_stats s
+		LEFT JOIN animes a ON a.id = s.anime_id
+		WHERE s.votes >= $1
+		ORDER BY s.average DESC, s.votes DESC, s.anime_id
+		LIMIT $2
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &rows, query, minVotes, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community top: %w", err)
+	}
+
+	top := make([]models.CommunityRating, 0, len(rows))
+	for _, row := range rows {
+		top = append(top, row.toModel())
+	}
+	return top, nil
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var communityColumns = []string{"anime_id", "votes", "average",
+	"score_1", "score_2", "score_3", "score_4", "score_5",
+	"score_6", "score_7", "score_8", "score_9", "score_10", "title"}
+
+func TestGetCommunityRating_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	rows := sqlmock.NewRows(communityColumns).
+		AddRow(1, 3, 8.0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 0, "Naruto")
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM anime_rating_stats s`)).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	rating, err := repo.GetCommunityRating(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rating.Votes != 3 || rating.Average != 8.0 || rating.Title != "Naruto" {
+		t.Errorf("unexpected rating: %+v", rating)
+	}
+	if len(rating.Distribution) != 3 || rating.Distribution[7] != 1 || rating.Distribution[9] != 1 {
+		t.Errorf("unexpected distribution: %v", rating.Distribution)
+	}
+}
+
+func TestGetCommunityRating_NotFound(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM anime_rating_stats s`)).
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.GetCommunityRating(1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetCommunityTop(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	rows := sqlmock.NewRows(communityColumns).
+		AddRow(1, 5, 9.2, 0, 0, 0, 0, 0, 0, 0, 1, 2, 2, "Monster").
+		AddRow(2, 3, 8.0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 0, "")
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE s.votes >= $1`)).
+		WithArgs(3, 10).
+		WillReturnRows(rows)
+
+	top, err := repo.GetCommunityTop(3, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(top) != 2 || top[0].AnimeID != 1 || top[1].Votes != 3 {
+		t.Errorf("unexpected top: %+v", top)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
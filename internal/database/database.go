@@ -1,51 +1,134 @@
 package database
 
 import (
+	"context"
+	"database/sql/driver"
 	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
+	"github.com/wywyy3cee/tgbot-anime-tracker/migrations"
 	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/logger"
+	"modernc.org/sqlite"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+func init() {
+	// sqlite's built-in lower() only folds ASCII, which breaks searching Cyrillic titles
+	sqlite.MustRegisterDeterministicScalarFunction("lower", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if s, ok := args[0].(string); ok {
+			return strings.ToLower(s), nil
+		}
+		return args[0], nil
+	})
+}
+
 type Database struct {
 	DB     *sqlx.DB
+	Driver string
 	logger *logger.Logger
 }
 
+func parseDatabaseURL(databaseURL string) (string, string) {
+	// sqlite://path, sqlite:path and file:path open an embedded SQLite file;
+	// anything else, including key=value lib/pq DSNs, goes to Postgres
+	switch {
+	case strings.HasPrefix(databaseURL, "sqlite://"):
+		return DriverSQLite, sqliteDSN(strings.TrimPrefix(databaseURL, "sqlite://"))
+	case strings.HasPrefix(databaseURL, "sqlite:"):
+		return DriverSQLite, sqliteDSN(strings.TrimPrefix(databaseURL, "sqlite:"))
+	case strings.HasPrefix(databaseURL, "file:"):
+		return DriverSQLite, sqliteDSN(databaseURL)
+	}
+	return DriverPostgres, databaseURL
+}
+
+func sqliteDSN(path string) string {
+	if !strings.HasPrefix(path, "file:") {
+		path = "file:" + path
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	// cascades rely on foreign keys, which sqlite leaves off by default
+	return path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+}
+
 func Connect(databaseURL string, logger *logger.Logger) (*Database, error) {
 	logger.Info("Connecting to database...")
 
-	db, err := sqlx.Connect("postgres", databaseURL)
+	driver, dsn := parseDatabaseURL(databaseURL)
+
+	db, err := sqlx.Connect(driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if driver == DriverSQLite {
+		// a single writer avoids "database is locked" errors
+		db.SetMaxOpenConns(1)
+	}
+
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	logger.Info("Database connection established")
+	logger.Info("Database connection established (%s)", driver)
 
 	return &Database{
 		DB:     db,
+		Driver: driver,
 		logger: logger,
 	}, nil
 }
 
+// RunMigrations applies migrations from a directory on disk
 func (d *Database) RunMigrations(migrationsDir string) error {
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
-	}
-
-	if err := goose.Up(d.DB.DB, migrationsDir); err != nil {
+	if err := d.runGoose(nil, migrationsDir, "up"); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
+	return nil
+}
 
+// Migrate runs a goose command against the migrations embedded in the binary
+func (d *Database) Migrate(command string, args ...string) error {
+	if err := d.runGoose(migrations.FS, ".", command, args...); err != nil {
+		return fmt.Errorf("failed to run migrate %s: %w", command, err)
+	}
 	return nil
 }
 
+func (d *Database) runGoose(fsys fs.FS, dir string, command string, args ...string) error {
+	dialect := "postgres"
+	if d.Driver == DriverSQLite {
+		dialect = "sqlite3"
+		if fsys != nil {
+			dir = path.Join(dir, "sqlite")
+		} else {
+			dir = filepath.Join(dir, "sqlite")
+		}
+	}
+
+	if err := goose.SetDialect(dialect); err != nil {
+		return fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+
+	goose.SetBaseFS(fsys)
+	defer goose.SetBaseFS(nil)
+
+	return goose.RunContext(context.Background(), command, d.DB.DB, dir, args...)
+}
+
 func (d *Database) Close() error {
 	return d.DB.Close()
 }
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+type FavoriteSort string
+
+const (
+	FavoriteSortDate   FavoriteSort = "date"
+	FavoriteSortTitle  FavoriteSort = "title"
+	FavoriteSortRating FavoriteSort = "rating"
+	FavoriteSortScore  FavoriteSort = "score"
+)
+
+var FavoriteSorts = []FavoriteSort{FavoriteSortDate, FavoriteSortTitle, FavoriteSortRating, FavoriteSortScore}
+
+type favoriteSortKey struct {
+	expr string
+	desc bool
+}
+
+// every order ends with anime_id, so (key, anime_id) identifies a row for the keyset
+var favoriteSortKeys = map[FavoriteSort]favoriteSortKey{
+	FavoriteSortDate:   {expr: "f.added_at", desc: true},
+	FavoriteSortTitle:  {expr: "f.title", desc: false},
+	FavoriteSortRating: {expr: "COALESCE(r.score, 0)", desc: true},
+	FavoriteSortScore:  {expr: "COALESCE(CAST(NULLIF(a.score, '') AS DOUBLE PRECISION), 0)", desc: true},
+}
+
+type FavoriteCursor struct {
+	AddedAt    time.Time
+	Title      string
+	MyScore    int
+	AnimeScore float64
+	AnimeID    int
+}
+
+func CursorAfter(item models.FavoriteListItem) *FavoriteCursor {
+	return &FavoriteCursor{
+		AddedAt:    item.AddedAt,
+		Title:      item.Title,
+		MyScore:    item.MyScore,
+		AnimeScore: item.AnimeScore,
+		AnimeID:    item.AnimeID,
+	}
+}
+
+func (c *FavoriteCursor) key(sort FavoriteSort) interface{} {
+	switch sort {
+	case FavoriteSortTitle:
+		return c.Title
+	case FavoriteSortRating:
+		return c.MyScore
+	case FavoriteSortScore:
+		return c.AnimeScore
+	}
+	return c.AddedAt
+}
+
+type FavoritesQuery struct {
+	Sort   FavoriteSort
+	Search string
+	After  *FavoriteCursor
+	Limit  int
+}
+
+func searchPattern(search string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(strings.ToLower(strings.TrimSpace(search))) + "%"
+}
+
+func favoritesFilter(userID int64, search string) (string, []interface{}) {
+	where := "f.user_id = $1"
+	args := []interface{}{userID}
+	if strings.TrimSpace(search) != "" {
+		args = append(args, searchPattern(search))
+		where += fmt.Sprintf(` AND LOWER(f.title) LIKE $%d ESCAPE '\'`, len(args))
+	}
+	return where, args
+}
+
+func (r *Repository) GetFavoritesPage(userID int64, q FavoritesQuery) ([]models.FavoriteListItem, error) {
+	return r.GetFavoritesPageContext(context.Background(), userID, q)
+}
+
+func (r *Repository) GetFavoritesPageContext(ctx context.Context, userID int64, q FavoritesQuery) ([]models.FavoriteListItem, error) {
+	sortKey, ok := favoriteSortKeys[q.Sort]
+	if !ok {
+		q.Sort = FavoriteSortDate
+		sortKey = favoriteSortKeys[q.Sort]
+	}
+
+	op, dir := ">", "ASC"
+	if sortKey.desc {
+		op, dir = "<", "DESC"
+	}
+
+	where, args := favoritesFilter(userID, q.Search)
+	if q.After != nil {
+		args = append(args, q.After.key(q.Sort), q.After.AnimeID)
+		keyArg, idArg := len(args)-1, len(args)
+		where += fmt.Sprintf(" AND (%[1]s %[2]s $%[3]d OR (%[1]s = $%[3]d AND f.anime_id %[2]s $%[4]d))",
+			sortKey.expr, op, keyArg, idArg)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+	args = append(args, limit)
+
+	var items []models.FavoriteListItem
+	query := fmt.Sprintf(`
+		SELECT f.anime_id, f.title, f.added_at,
+			COALESCE(r.score, 0) AS my_score,
+			COALESCE(CAST(NULLIF(a.score, '') AS DOUBLE PRECISION), 0) AS anime_score
+		FROM favorites f
+		LEFT JOIN ratings r ON r.user_id = f.user_id AND r.anime_id = f.anime_id
+		LEFT JOIN animes a ON a.id = f.anime_id
+		WHERE %s
+		ORDER BY %s %s, f.anime_id %s
+		LIMIT $%d
+	`, where, sortKey.expr, dir, dir, len(args))
+
+	err := sqlx.SelectContext(ctx, r.ext(), &items, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites page: %w", err)
+	}
+	return items, nil
+}
+
+func (r *Repository) CountFavoritesMatching(userID int64, search string) (int, error) {
+	return r.CountFavoritesMatchingContext(context.Background(), userID, search)
+}
+
+func (r *Repository) CountFavoritesMatchingContext(ctx context.Context, userID int64, search string) (int, error) {
+	where, args := favoritesFilter(userID, search)
+
+	var count int
+	query := `SELECT COUNT(*) FROM favorites f WHERE ` + where
+
+	err := sqlx.GetContext(ctx, r.ext(), &count, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count favorites: %w", err)
+	}
+	return count, nil
+}
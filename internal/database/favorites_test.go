@@ -0,0 +1,82 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetFavoritesPage_FirstPage(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"anime_id", "title", "added_at", "my_score", "anime_score"}).
+		AddRow(2, "Naruto", now, 8, 7.9).
+		AddRow(1, "Bleach", now.Add(-time.Hour), 0, 0.0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY f.added_at DESC, f.anime_id DESC`)).
+		WithArgs(int64(123), 10).
+		WillReturnRows(rows)
+
+	items, err := repo.GetFavoritesPage(123, FavoritesQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].MyScore != 8 || items[0].AnimeScore != 7.9 {
+		t.Errorf("unexpected items: %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetFavoritesPage_SearchAndCursor(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`LOWER(f.title) LIKE $2 ESCAPE '\' AND (f.title > $3 OR (f.title = $3 AND f.anime_id > $4))`)).
+		WithArgs(int64(123), `%100\% naru%`, "Naruto", 20, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id", "title", "added_at", "my_score", "anime_score"}))
+
+	_, err := repo.GetFavoritesPage(123, FavoritesQuery{
+		Sort:   FavoriteSortTitle,
+		Search: " 100% Naru ",
+		After:  &FavoriteCursor{Title: "Naruto", AnimeID: 20},
+		Limit:  5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetFavoritesPage_DatabaseError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM favorites f`)).
+		WillReturnError(errors.New("connection lost"))
+
+	if _, err := repo.GetFavoritesPage(123, FavoritesQuery{Sort: FavoriteSortScore}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCountFavoritesMatching(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM favorites f WHERE f.user_id = $1 AND LOWER(f.title) LIKE $2`)).
+		WithArgs(int64(123), "%naruto%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.CountFavoritesMatching(123, "Naruto")
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3, got %d (%v)", count, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
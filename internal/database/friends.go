@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// visibleFollowees selects users $1 follows who share their list
+const visibleFollowees = `
+	SELECT fo.followee_id FROM follows fo
+	JOIN users u ON u.id = fo.followee_id
+	WHERE fo.follower_id = $1 AND u.share_list
+`
+
+func (r *Repository) EnsureFriendCode(userID int64, code string) (string, error) {
+	return r.EnsureFriendCodeContext(context.Background(), userID, code)
+}
+
+// EnsureFriendCodeContext stores code unless the user already has one and
+// returns whichever code the user ends up with
+func (r *Repository) EnsureFriendCodeContext(ctx context.Context, userID int64, code string) (string, error) {
+	query := `UPDATE users SET friend_code = $2 WHERE id = $1 AND friend_code IS NULL`
+	if _, err := r.ext().ExecContext(ctx, query, userID, code); err != nil {
+		return "", fmt.Errorf("failed to save friend code: %w", err)
+	}
+
+	var stored sql.NullString
+	err := sqlx.GetContext(ctx, r.ext(), &stored, `SELECT friend_code FROM users WHERE id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get friend code: %w", err)
+	}
+	return stored.String, nil
+}
+
+func (r *Repository) GetUserByFriendCode(code string) (*models.User, error) {
+	return r.GetUserByFriendCodeContext(context.Background(), code)
+}
+
+func (r *Repository) GetUserByFriendCodeContext(ctx context.Context, code string) (*models.User, error) {
+	var user models.User
+	query := `SELECT id, username, created_at FROM users WHERE friend_code = $1`
+
+	err := sqlx.GetContext(ctx, r.ext(), &user, query, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by friend code: %w", err)
+	}
+	return &user, nil
+}
+
+func (r *Repository) Follow(followerID int64, followeeID int64) error {
+	return r.FollowContext(context.Background(), followerID, followeeID)
+}
+
+func (r *Repository) FollowContext(ctx context.Context, followerID int64, followeeID int64) error {
+	query := `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+
+	if _, err := r.ext().ExecContext(ctx, query, followerID, followeeID, time.Now()); err != nil {
+		return fmt.Errorf("failed to follow: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) Unfollow(followerID int64, followeeID int64) error {
+	return r.UnfollowContext(context.Background(), followerID, followeeID)
+}
+
+func (r *Repository) UnfollowContext(ctx context.Context, followerID int64, followeeID int64) error {
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+
+	if _, err := r.ext().ExecContext(ctx, query, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to unfollow: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetFollowing(userID int64) ([]models.Friend, error) {
+	return r.GetFollowingContext(context.Background(), userID)
+}
+
+func (r *Repository) GetFollowingContext(ctx context.Context, userID int64) ([]models.Friend, error) {
+	var friends []models.Friend
+	query := `
+		SELECT fo.followee_id AS user_id, COALESCE(u.username, '') AS username, fo.created_at AS followed_at
+		FROM follows fo
+		JOIN users u ON u.id = fo.followee_id
+		WHERE fo.follower_id = $1
+		ORDER BY fo.created_at, fo.followee_id
+	`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &friends, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get following: %w", err)
+	}
+	return friends, nil
+}
+
+func (r *Repository) GetFriendsActivity(userID int64, limit int) ([]models.FriendActivity, error) {
+	return r.GetFriendsActivityContext(context.Background(), userID, limit)
+}
+
+// GetFriendsActivityContext returns the latest favorites and ratings of followed
+// users who share their list, newest first
+func (r *Repository) GetFriendsActivityContext(ctx context.Context, userID int64, limit int) ([]models.FriendActivity, error) {
+	var activity []models.FriendActivity
+	favoritesQuery := `
+		SELECT f.user_id, COALESCE(u.username, '') AS username, f.anime_id,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title) AS title,
+			NULL AS score,
+			f.added_at AS at
+		FROM favorites f
+		JOIN users u ON u.id = f.user_id
+		LEFT JOIN animes a ON a.id = f.anime_id
+		WHERE f.user_id IN (` + visibleFollowees + `)
+		ORDER BY f.added_at DESC, f.anime_id
+		LIMIT $2
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &activity, favoritesQuery, userID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get friends favorites: %w", err)
+	}
+
+	var ratings []models.FriendActivity
+	ratingsQuery := `
+		SELECT r.user_id, COALESCE(u.username, '') AS username, r.anime_id,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title, '') AS title,
+			r.score,
+			r.rated_at AS at
+		FROM ratings r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN animes a ON a.id = r.anime_id
+		LEFT JOIN favorites f ON f.user_id = r.user_id AND f.anime_id = r.anime_id
+		WHERE r.user_id IN (` + visibleFollowees + `)
+		ORDER BY r.rated_at DESC, r.anime_id
+		LIMIT $2
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &ratings, ratingsQuery, userID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get friends ratings: %w", err)
+	}
+
+	activity = append(activity, ratings...)
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].At.After(activity[j].At)
+	})
+	if len(activity) > limit {
+		activity = activity[:limit]
+	}
+	return activity, nil
+}
+
+func (r *Repository) GetFriendRatings(userID int64, animeID int, limit int) ([]models.FriendRating, error) {
+	return r.GetFriendRatingsContext(context.Background(), userID, animeID, limit)
+}
+
+// GetFriendRatingsContext returns how followed users who share their list rated
+// an anime, highest first
+func (r *Repository) GetFriendRatingsContext(ctx context.Context, userID int64, animeID int, limit int) ([]models.FriendRating, error) {
+	var ratings []models.FriendRating
+	query := `
+		SELECT r.user_id, COALESCE(u.username, '') AS username, r.score
+		FROM ratings r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.anime_id = $2 AND r.user_id IN (` + visibleFollowees + `)
+		ORDER BY r.score DESC, u.username
+		LIMIT $3
+	`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &ratings, query, userID, animeID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get friend ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+func (r *Repository) GetShareList(userID int64) (bool, error) {
+	return r.GetShareListContext(context.Background(), userID)
+}
+
+func (r *Repository) GetShareListContext(ctx context.Context, userID int64) (bool, error) {
+	var share bool
+	err := sqlx.GetContext(ctx, r.ext(), &share, `SELECT share_list FROM users WHERE id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get privacy: %w", err)
+	}
+	return share, nil
+}
+
+func (r *Repository) SetShareList(userID int64, share bool) error {
+	return r.SetShareListContext(context.Background(), userID, share)
+}
+
+func (r *Repository) SetShareListContext(ctx context.Context, userID int64, share bool) error {
+	if _, err := r.ext().ExecContext(ctx, `UPDATE users SET share_list = $2 WHERE id = $1`, userID, share); err != nil {
+		return fmt.Errorf("failed to update privacy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestFollow_IgnoresDuplicates(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (follower_id, followee_id) DO NOTHING`)).
+		WithArgs(int64(1), int64(2), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Follow(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEnsureFriendCode_KeepsExisting(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET friend_code = $2 WHERE id = $1 AND friend_code IS NULL`)).
+		WithArgs(int64(1), "newcode1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT friend_code FROM users WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"friend_code"}).AddRow("oldcode1"))
+
+	code, err := repo.EnsureFriendCode(1, "newcode1")
+	if err != nil || code != "oldcode1" {
+		t.Errorf("expected the stored code, got %q (%v)", code, err)
+	}
+}
+
+func TestGetUserByFriendCode_NotFound(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE friend_code = $1`)).
+		WithArgs("missing1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "created_at"}))
+
+	if _, err := repo.GetUserByFriendCode("missing1"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func (r *Repository) GetLibrary(userID int64) ([]models.LibraryEntry, error) {
+	return r.GetLibraryContext(context.Background(), userID)
+}
+
+func (r *Repository) GetLibraryContext(ctx context.Context, userID int64) ([]models.LibraryEntry, error) {
+	var entries []models.LibraryEntry
+	query := `
+		SELECT ids.anime_id,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title, '') AS title,
+			COALESCE(a.kind, '') AS kind,
+			COALESCE(a.episodes, 0) AS episodes,
+			f.added_at,
+			r.score,
+			r.rated_at,
+			COALESCE(n.text, '') AS note
+		FROM (
+			SELECT anime_id FROM favorites WHERE user_id = $1
+			UNION
+			SELECT anime_id FROM ratings WHERE user_id = $1
+		) ids
+		LEFT JOIN favorites f ON f.user_id = $1 AND f.anime_id = ids.anime_id
+		LEFT JOIN ratings r ON r.user_id = $1 AND r.anime_id = ids.anime_id
+		LEFT JOIN notes n ON n.user_id = $1 AND n.anime_id = ids.anime_id
+		LEFT JOIN animes a ON a.id = ids.anime_id
+		ORDER BY ids.anime_id
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &entries, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library: %w", err)
+	}
+	return entries, nil
+}
+
+type existingRating struct {
+	AnimeID int `db:"anime_id"`
+	Score   int `db:"score"`
+}
+
+func (r *Repository) ImportLibrary(userID int64, entries []models.LibraryEntry) (models.ImportResult, error) {
+	return r.ImportLibraryContext(context.Background(), userID, entries)
+}
+
+func (r *Repository) ImportLibraryContext(ctx context.Context, userID int64, entries []models.LibraryEntry) (models.ImportResult, error) {
+	var result models.ImportResult
+
+	// existing data wins: a different score already in the bot is reported
+	// as a conflict and left untouched
+	err := r.inTx(ctx, func(tx *Repository) error {
+		var err error
+		result, err = tx.importLibrary(ctx, userID, entries)
+		return err
+	})
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+	return result, nil
+}
+
+func (r *Repository) importLibrary(ctx context.Context, userID int64, entries []models.LibraryEntry) (models.ImportResult, error) {
+	var result models.ImportResult
+
+	var favoriteIDs []int
+	if err := sqlx.SelectContext(ctx, r.ext(), &favoriteIDs, `SELECT anime_id FROM favorites WHERE user_id = $1`, userID); err != nil {
+		return result, fmt.Errorf("failed to get existing favorites: %w", err)
+	}
+	var ratings []existingRating
+	if err := sqlx.SelectContext(ctx, r.ext(), &ratings, `SELECT anime_id, score FROM ratings WHERE user_id = $1`, userID); err != nil {
+		return result, fmt.Errorf("failed to get existing ratings: %w", err)
+	}
+	var noteIDs []int
+	if err := sqlx.SelectContext(ctx, r.ext(), &noteIDs, `SELECT anime_id FROM notes WHERE user_id = $1`, userID); err != nil {
+		return result, fmt.Errorf("failed to get existing notes: %w", err)
+	}
+
+	hasFavorite := make(map[int]bool, len(favoriteIDs))
+	for _, id := range favoriteIDs {
+		hasFavorite[id] = true
+	}
+	scores := make(map[int]int, len(ratings))
+	for _, rating := range ratings {
+		scores[rating.AnimeID] = rating.Score
+	}
+	hasNote := make(map[int]bool, len(noteIDs))
+	for _, id := range noteIDs {
+		hasNote[id] = true
+	}
+
+	var newFavorites, newRatings, newNotes []models.LibraryEntry
+	for _, entry := range entries {
+		changed := false
+		conflict := false
+
+		if entry.AddedAt != nil && !hasFavorite[entry.AnimeID] {
+			newFavorites = append(newFavorites, entry)
+			changed = true
+		}
+		if entry.Score != nil {
+			existing, ok := scores[entry.AnimeID]
+			switch {
+			case !ok:
+				newRatings = append(newRatings, entry)
+				changed = true
+			case existing != *entry.Score:
+				conflict = true
+			}
+		}
+		if entry.Note != "" && !hasNote[entry.AnimeID] {
+			newNotes = append(newNotes, entry)
+			changed = true
+		}
+
+		switch {
+		case conflict:
+			result.Conflicts++
+		case changed:
+			result.Imported++
+		default:
+			result.Skipped++
+		}
+	}
+
+	if err := r.addFavoritesBatch(ctx, userID, newFavorites); err != nil {
+		return result, err
+	}
+	if err := r.addRatingsBatch(ctx, userID, newRatings); err != nil {
+		return result, err
+	}
+	if err := r.addNotesBatch(ctx, userID, newNotes); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (r *Repository) addFavoritesBatch(ctx context.Context, userID int64, entries []models.LibraryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	stmt, err := r.tx.PreparexContext(ctx, `
+		INSERT INTO favorites (user_id, anime_id, title, poster_url, added_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, anime_id) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare favorites batch: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.ExecContext(ctx, userID, entry.AnimeID, entry.Title, "", *entry.AddedAt); err != nil {
+			return fmt.Errorf("failed to add favorites batch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) addRatingsBatch(ctx context.Context, userID int64, entries []models.LibraryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	stmt, err := r.tx.PreparexContext(ctx, `
+		INSERT INTO ratings (user_id, anime_id, score, rated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, anime_id) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare ratings batch: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.ExecContext(ctx, userID, entry.AnimeID, *entry.Score, *entry.RatedAt); err != nil {
+			return fmt.Errorf("failed to add ratings batch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) addNotesBatch(ctx context.Context, userID int64, entries []models.LibraryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	stmt, err := r.tx.PreparexContext(ctx, `
+		INSERT INTO notes (user_id, anime_id, text, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id, anime_id) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare notes batch: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, entry := range entries {
+		if _, err := stmt.ExecContext(ctx, userID, entry.AnimeID, entry.Note, now); err != nil {
+			return fmt.Errorf("failed to add notes batch: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestGetLibrary_MergesFavoritesAndRatings(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"anime_id", "title", "kind", "episodes", "added_at", "score", "rated_at", "note"}).
+		AddRow(1, "Тетрадь смерти", "tv", 37, now, 10, now, "rewatch").
+		AddRow(2, "Наруто", "tv", 220, now, nil, nil, "").
+		AddRow(3, "", "", 0, nil, 6, now, "")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM favorites WHERE user_id = $1`)).
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	entries, err := repo.GetLibrary(userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Score == nil || *entries[0].Score != 10 || entries[0].Note != "rewatch" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Score != nil || entries[1].AddedAt == nil {
+		t.Errorf("expected favorite without rating, got %+v", entries[1])
+	}
+	if entries[2].AddedAt != nil {
+		t.Errorf("expected rating without favorite, got %+v", entries[2])
+	}
+}
+
+func TestImportLibrary_CountsImportedSkippedAndConflicts(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+	now := time.Now()
+	nine, seven, five := 9, 7, 5
+
+	entries := []models.LibraryEntry{
+		// new favorite and rating
+		{AnimeID: 1, Title: "Death Note", AddedAt: &now, Score: &nine, RatedAt: &now, Note: "classic"},
+		// already in favorites with the same score
+		{AnimeID: 2, Title: "Naruto", AddedAt: &now, Score: &seven, RatedAt: &now},
+		// rated differently in the bot
+		{AnimeID: 3, Title: "Bleach", Score: &five, RatedAt: &now},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM favorites WHERE user_id = $1`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id, score FROM ratings WHERE user_id = $1`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id", "score"}).AddRow(2, 7).AddRow(3, 8))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM notes WHERE user_id = $1`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id"}))
+
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO favorites`)).
+		ExpectExec().
+		WithArgs(userID, 1, "Death Note", "", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO ratings`)).
+		ExpectExec().
+		WithArgs(userID, 1, 9, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO notes`)).
+		ExpectExec().
+		WithArgs(userID, 1, "classic", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.ImportLibrary(userID, entries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Imported != 1 || result.Skipped != 1 || result.Conflicts != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestImportLibrary_RollsBackOnError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM favorites`)).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id, score FROM ratings`)).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id", "score"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM notes`)).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id"}))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO favorites`)).
+		ExpectExec().
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	_, err := repo.ImportLibrary(userID, []models.LibraryEntry{{AnimeID: 1, Title: "Death Note", AddedAt: &now}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
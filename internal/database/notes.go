@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func (r *Repository) SaveNote(note models.Note) error {
+	return r.SaveNoteContext(context.Background(), note)
+}
+
+func (r *Repository) SaveNoteContext(ctx context.Context, note models.Note) error {
+	query := `
+		INSERT INTO notes (user_id, anime_id, text, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, anime_id)
+		DO UPDATE SET text = EXCLUDED.text, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.ext().ExecContext(ctx, query,
+		note.UserID,
+		note.AnimeID,
+		note.Text,
+		note.CreatedAt,
+		note.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetNote(userID int64, animeID int) (*models.Note, error) {
+	return r.GetNoteContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) GetNoteContext(ctx context.Context, userID int64, animeID int) (*models.Note, error) {
+	var note models.Note
+	query := `SELECT id, user_id, anime_id, text, created_at, updated_at FROM notes WHERE user_id = $1 AND anime_id = $2`
+
+	err := sqlx.GetContext(ctx, r.ext(), &note, query, userID, animeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	return &note, nil
+}
+
+func (r *Repository) DeleteNote(userID int64, animeID int) error {
+	return r.DeleteNoteContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) DeleteNoteContext(ctx context.Context, userID int64, animeID int) error {
+	query := `DELETE FROM notes WHERE user_id = $1 AND anime_id = $2`
+
+	_, err := r.ext().ExecContext(ctx, query, userID, animeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	return nil
+}
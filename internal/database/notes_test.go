@@ -0,0 +1,97 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestSaveNote_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	now := time.Now()
+	note := models.Note{
+		UserID:    123,
+		AnimeID:   1,
+		Text:      "stopped at ep 5",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO notes`)).
+		WithArgs(note.UserID, note.AnimeID, note.Text, now, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.SaveNote(note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetNote_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "anime_id", "text", "created_at", "updated_at"}).
+		AddRow(1, userID, 1, "dub is bad", now, now)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, anime_id, text, created_at, updated_at FROM notes WHERE user_id = $1 AND anime_id = $2`)).
+		WithArgs(userID, 1).
+		WillReturnRows(rows)
+
+	note, err := repo.GetNote(userID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if note == nil || note.Text != "dub is bad" {
+		t.Errorf("unexpected note: %+v", note)
+	}
+}
+
+func TestGetNote_NotFound(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM notes`)).
+		WithArgs(int64(123), 999).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "anime_id", "text", "created_at", "updated_at"}))
+
+	note, err := repo.GetNote(123, 999)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if note != nil {
+		t.Error("expected nil for non-existent note")
+	}
+}
+
+func TestGetNote_DatabaseError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM notes`)).
+		WillReturnError(sql.ErrConnDone)
+
+	if _, err := repo.GetNote(123, 1); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func TestDeleteNote_Success(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM notes WHERE user_id = $1 AND anime_id = $2`)).
+		WithArgs(int64(123), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.DeleteNote(123, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func (r *Repository) GetPosterFile(animeID int, size string) (*models.PosterFile, error) {
+	return r.GetPosterFileContext(context.Background(), animeID, size)
+}
+
+func (r *Repository) GetPosterFileContext(ctx context.Context, animeID int, size string) (*models.PosterFile, error) {
+	var file models.PosterFile
+	query := `
+		SELECT anime_id, size, path, file_id
+		FROM poster_files
+		WHERE anime_id = $1 AND size = $2
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &file, query, animeID, size)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poster file: %w", err)
+	}
+	return &file, nil
+}
+
+func (r *Repository) SavePosterFile(file models.PosterFile) error {
+	return r.SavePosterFileContext(context.Background(), file)
+}
+
+func (r *Repository) SavePosterFileContext(ctx context.Context, file models.PosterFile) error {
+	query := `
+		INSERT INTO poster_files (anime_id, size, path, file_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (anime_id, size) DO UPDATE SET
+			path = EXCLUDED.path,
+			file_id = EXCLUDED.file_id,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.ext().ExecContext(ctx, query, file.AnimeID, file.Size, file.Path, file.FileID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save poster file: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeletePosterFile(animeID int, size string) error {
+	return r.DeletePosterFileContext(context.Background(), animeID, size)
+}
+
+func (r *Repository) DeletePosterFileContext(ctx context.Context, animeID int, size string) error {
+	_, err := r.ext().ExecContext(ctx, `DELETE FROM poster_files WHERE anime_id = $1 AND size = $2`, animeID, size)
+	if err != nil {
+		return fmt.Errorf("failed to delete poster file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// tasteTitles selects every title the user favorited, rated or reacted to
+const tasteTitles = `
+	SELECT anime_id FROM favorites WHERE user_id = $1
+	UNION
+	SELECT anime_id FROM ratings WHERE user_id = $1
+	UNION
+	SELECT anime_id FROM recommendation_feedback WHERE user_id = $1
+`
+
+type animeGenreRow struct {
+	AnimeID int `db:"anime_id"`
+	genreRow
+}
+
+// loadGenres reads catalog genres for every anime matched by idsQuery
+func (r *Repository) loadGenres(ctx context.Context, idsQuery string, args ...interface{}) (map[int][]models.Genre, error) {
+	var rows []animeGenreRow
+	query := `
+		SELECT ag.anime_id, g.id, g.name, g.russian, g.kind, g.entry_type
+		FROM anime_genres ag
+		JOIN genres g ON g.id = ag.genre_id
+		WHERE ag.anime_id IN (` + idsQuery + `)
+		ORDER BY ag.anime_id, ag.position
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to load genres: %w", err)
+	}
+
+	genres := make(map[int][]models.Genre)
+	for _, row := range rows {
+		genres[row.AnimeID] = append(genres[row.AnimeID], models.Genre{
+			ID:        row.ID,
+			Name:      row.Name,
+			Russian:   row.Russian,
+			Kind:      row.Kind,
+			EntryType: row.EntryType,
+		})
+	}
+	return genres, nil
+}
+
+func (r *Repository) GetTasteSignals(userID int64) ([]models.TasteSignal, error) {
+	return r.GetTasteSignalsContext(context.Background(), userID)
+}
+
+func (r *Repository) GetTasteSignalsContext(ctx context.Context, userID int64) ([]models.TasteSignal, error) {
+	var signals []models.TasteSignal
+	query := `
+		SELECT ids.anime_id,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title, '') AS title,
+			(f.anime_id IS NOT NULL) AS favorite,
+			r.score,
+			fb.liked
+		FROM (` + tasteTitles + `) ids
+		LEFT JOIN favorites f ON f.user_id = $1 AND f.anime_id = ids.anime_id
+		LEFT JOIN ratings r ON r.user_id = $1 AND r.anime_id = ids.anime_id
+		LEFT JOIN recommendation_feedback fb ON fb.user_id = $1 AND fb.anime_id = ids.anime_id
+		LEFT JOIN animes a ON a.id = ids.anime_id
+		ORDER BY ids.anime_id
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &signals, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get taste signals: %w", err)
+	}
+	if len(signals) == 0 {
+		return signals, nil
+	}
+
+	genres, err := r.loadGenres(ctx, tasteTitles, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range signals {
+		signals[i].Genres = genres[signals[i].AnimeID]
+	}
+	return signals, nil
+}
+
+func (r *Repository) GetCatalogCandidates(userID int64, genreIDs []int, limit int) ([]models.Anime, error) {
+	return r.GetCatalogCandidatesContext(context.Background(), userID, genreIDs, limit)
+}
+
+// GetCatalogCandidatesContext returns cached titles sharing any of genreIDs that
+// the user has no opinion on yet, most matching genres first
+func (r *Repository) GetCatalogCandidatesContext(ctx context.Context, userID int64, genreIDs []int, limit int) ([]models.Anime, error) {
+	if len(genreIDs) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{userID}
+	placeholders := make([]string, len(genreIDs))
+	for i, id := range genreIDs {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	args = append(args, limit)
+
+	ids := fmt.Sprintf(`
+		SELECT ag.anime_id
+		FROM anime_genres ag
+		WHERE ag.genre_id IN (%s)
+			AND ag.anime_id NOT IN (%s)
+		GROUP BY ag.anime_id
+		ORDER BY COUNT(*) DESC, ag.anime_id
+		LIMIT $%d
+	`, strings.Join(placeholders, ", "), tasteTitles, len(args))
+
+	var rows []animeRow
+	query := `
+		SELECT id, name, russian, kind, score, status, episodes, duration,
+			aired_on, released_on, description, image_original, image_preview, image_x96, fetched_at
+		FROM animes
+		WHERE id IN (SELECT anime_id FROM (` + ids + `) candidates)
+		ORDER BY id
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get catalog candidates: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	genres, err := r.loadGenres(ctx, `SELECT anime_id FROM (`+ids+`) candidates`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]models.Anime, 0, len(rows))
+	for _, row := range rows {
+		anime := row.toModel()
+		anime.Genres = genres[row.ID]
+		candidates = append(candidates, anime)
+	}
+	return candidates, nil
+}
+
+func (r *Repository) SaveRecommendationFeedback(userID int64, animeID int, liked bool) error {
+	return r.SaveRecommendationFeedbackContext(context.Background(), userID, animeID, liked)
+}
+
+func (r *Repository) SaveRecommendationFeedbackContext(ctx context.Context, userID int64, animeID int, liked bool) error {
+	query := `
+		INSERT INTO recommendation_feedback (user_id, anime_id, liked, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, anime_id) DO UPDATE SET
+			liked = EXCLUDED.liked,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err := r.ext().ExecContext(ctx, query, userID, animeID, liked, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save recommendation feedback: %w", err)
+	}
+	return nil
+}
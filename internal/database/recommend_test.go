@@ -0,0 +1,30 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSaveRecommendationFeedback(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recommendation_feedback`)).
+		WithArgs(int64(1), 10, false, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.SaveRecommendationFeedback(1, 10, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetCatalogCandidates_NoGenres(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	// no query is expected: sqlmock fails on unexpected calls
+	candidates, err := repo.GetCatalogCandidates(1, nil, 10)
+	if err != nil || candidates != nil {
+		t.Errorf("expected no candidates, got %v (%v)", candidates, err)
+	}
+}
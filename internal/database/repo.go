@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+type UserRepository interface {
+	CreateUserContext(ctx context.Context, user models.User) error
+	GetUserContext(ctx context.Context, userID int64) (*models.User, error)
+	DeleteUserContext(ctx context.Context, userID int64, source string) (bool, error)
+}
+
+type SettingsRepository interface {
+	GetUserSettingsContext(ctx context.Context, userID int64) (*models.UserSettings, error)
+	SaveUserSettingsContext(ctx context.Context, settings models.UserSettings) error
+	GetUserLanguageContext(ctx context.Context, userID int64) (string, error)
+	SetUserLanguageContext(ctx context.Context, userID int64, language string) error
+}
+
+type FavoriteRepository interface {
+	AddFavoriteContext(ctx context.Context, favorite models.Favorite) error
+	RemoveFavoriteContext(ctx context.Context, userID int64, animeID int) error
+	GetFavoritesContext(ctx context.Context, userID int64) ([]models.Favorite, error)
+	IsFavoriteContext(ctx context.Context, userID int64, animeID int) (bool, error)
+	CountFavoritesContext(ctx context.Context, userID int64) (int, error)
+	GetFavoritesPageContext(ctx context.Context, userID int64, q FavoritesQuery) ([]models.FavoriteListItem, error)
+	CountFavoritesMatchingContext(ctx context.Context, userID int64, search string) (int, error)
+}
+
+type RatingRepository interface {
+	AddRatingContext(ctx context.Context, rating models.Rating) error
+	GetRatingContext(ctx context.Context, userID int64, animeID int) (*models.Rating, error)
+	DeleteRatingContext(ctx context.Context, userID int64, animeID int) error
+	GetUserRatingsContext(ctx context.Context, userID int64, q RatingsQuery) ([]models.UserRating, error)
+	CountRatingsByScoreContext(ctx context.Context, userID int64) (map[int]int, error)
+	GetRatingHistoryContext(ctx context.Context, userID int64, animeID int) ([]models.RatingChange, error)
+	GetCommunityRatingContext(ctx context.Context, animeID int) (*models.CommunityRating, error)
+	GetCommunityTopContext(ctx context.Context, minVotes int, limit int) ([]models.CommunityRating, error)
+}
+
+type CatalogRepository interface {
+	UpsertAnimeContext(ctx context.Context, anime models.Anime, fetchedAt time.Time) error
+	GetCatalogAnimeContext(ctx context.Context, id int) (*models.Anime, time.Time, error)
+	CountFavoritesByGenreContext(ctx context.Context, userID int64) ([]models.GenreCount, error)
+	GetFavoritesByKindContext(ctx context.Context, userID int64, kind string) ([]models.Favorite, error)
+	GetStatsEntriesContext(ctx context.Context, userID int64, q StatsQuery) ([]models.StatsEntry, error)
+	CountStatsGenresContext(ctx context.Context, userID int64, q StatsQuery, limit int) ([]models.GenreCount, error)
+}
+
+type NoteRepository interface {
+	SaveNoteContext(ctx context.Context, note models.Note) error
+	GetNoteContext(ctx context.Context, userID int64, animeID int) (*models.Note, error)
+	DeleteNoteContext(ctx context.Context, userID int64, animeID int) error
+}
+
+type CollectionRepository interface {
+	CreateCollectionContext(ctx context.Context, collection models.Collection) (int, error)
+	GetCollectionsContext(ctx context.Context, userID int64) ([]models.Collection, error)
+	GetCollectionContext(ctx context.Context, userID int64, collectionID int) (*models.Collection, error)
+	RenameCollectionContext(ctx context.Context, userID int64, collectionID int, name string) error
+	DeleteCollectionContext(ctx context.Context, userID int64, collectionID int) error
+	AddToCollectionContext(ctx context.Context, item models.CollectionItem) error
+	RemoveFromCollectionContext(ctx context.Context, collectionID int, animeID int) error
+	GetCollectionItemsContext(ctx context.Context, collectionID int) ([]models.CollectionItem, error)
+	GetCollectionItemsPageContext(ctx context.Context, collectionID int, after *CollectionCursor, limit int) ([]models.CollectionItem, error)
+	GetAnimeCollectionIDsContext(ctx context.Context, userID int64, animeID int) ([]int, error)
+}
+
+type LibraryRepository interface {
+	GetLibraryContext(ctx context.Context, userID int64) ([]models.LibraryEntry, error)
+	ImportLibraryContext(ctx context.Context, userID int64, entries []models.LibraryEntry) (models.ImportResult, error)
+}
+
+type WrappedRepository interface {
+	GetActivityContext(ctx context.Context, userID int64, q StatsQuery) ([]models.ActivityEvent, error)
+	GetWrappedRecipientsContext(ctx context.Context, year int, from, to time.Time) ([]int64, error)
+	MarkWrappedSentContext(ctx context.Context, userID int64, year int) error
+}
+
+type RecommendationRepository interface {
+	GetTasteSignalsContext(ctx context.Context, userID int64) ([]models.TasteSignal, error)
+	GetCatalogCandidatesContext(ctx context.Context, userID int64, genreIDs []int, limit int) ([]models.Anime, error)
+	SaveRecommendationFeedbackContext(ctx context.Context, userID int64, animeID int, liked bool) error
+}
+
+type SimilarityRepository interface {
+	GetAllRatingsContext(ctx context.Context) ([]models.Rating, error)
+	GetSimilarityDirtyContext(ctx context.Context) (map[int]int, error)
+	GetNeighborOwnersContext(ctx context.Context, animeIDs []int) ([]int, error)
+	ReplaceAnimeNeighborsContext(ctx context.Context, animeID int, neighbors []models.AnimeNeighbor) error
+	ClearSimilarityDirtyContext(ctx context.Context, versions map[int]int) error
+	GetAnimeNeighborsContext(ctx context.Context, animeID int, limit int) ([]models.AnimeNeighbor, error)
+}
+
+type FriendRepository interface {
+	EnsureFriendCodeContext(ctx context.Context, userID int64, code string) (string, error)
+	GetUserByFriendCodeContext(ctx context.Context, code string) (*models.User, error)
+	FollowContext(ctx context.Context, followerID int64, followeeID int64) error
+	UnfollowContext(ctx context.Context, followerID int64, followeeID int64) error
+	GetFollowingContext(ctx context.Context, userID int64) ([]models.Friend, error)
+	GetFriendsActivityContext(ctx context.Context, userID int64, limit int) ([]models.FriendActivity, error)
+	GetFriendRatingsContext(ctx context.Context, userID int64, animeID int, limit int) ([]models.FriendRating, error)
+	GetShareListContext(ctx context.Context, userID int64) (bool, error)
+	SetShareListContext(ctx context.Context, userID int64, share bool) error
+}
+
+type ChatRepository interface {
+	AddToChatWatchlistContext(ctx context.Context, item models.ChatWatchlistItem) (bool, error)
+	RemoveFromChatWatchlistContext(ctx context.Context, chatID int64, animeID int) error
+	GetChatWatchlistContext(ctx context.Context, chatID int64) ([]models.ChatWatchlistItem, error)
+	GetChatWatchlistItemContext(ctx context.Context, chatID int64, animeID int) (*models.ChatWatchlistItem, error)
+	MoveChatWatchlistContext(ctx context.Context, fromChatID int64, toChatID int64) error
+	TouchChatMemberContext(ctx context.Context, chatID int64, userID int64) error
+	GetMemberFavoritesContext(ctx context.Context, chatID int64, limit int) ([]models.VoteOption, error)
+}
+
+type VoteRepository interface {
+	CreateVoteSessionContext(ctx context.Context, session models.VoteSession) error
+	GetVoteSessionContext(ctx context.Context, pollID string) (*models.VoteSession, error)
+	GetOpenVoteSessionContext(ctx context.Context, chatID int64) (*models.VoteSession, error)
+	CloseVoteSessionContext(ctx context.Context, pollID string, winnerAnimeID *int) (bool, error)
+}
+
+type PosterRepository interface {
+	GetPosterFileContext(ctx context.Context, animeID int, size string) (*models.PosterFile, error)
+	SavePosterFileContext(ctx context.Context, file models.PosterFile) error
+	DeletePosterFileContext(ctx context.Context, animeID int, size string) error
+}
+
+// Repository implements Repo for both Postgres and SQLite; WithTx hands fn a
+// Repo bound to a single transaction
+type Repo interface {
+	UserRepository
+	FavoriteRepository
+	RatingRepository
+	CatalogRepository
+	NoteRepository
+	CollectionRepository
+	LibraryRepository
+	WrappedRepository
+	RecommendationRepository
+	SimilarityRepository
+	FriendRepository
+	ChatRepository
+	VoteRepository
+	SettingsRepository
+	PosterRepository
+
+	WithTx(ctx context.Context, fn func(tx Repo) error) error
+}
+
+var _ Repo = (*Repository)(nil)
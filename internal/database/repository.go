@@ -1,13 +1,19 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
 )
 
 type Repository struct {
 	db *Database
+	tx *sqlx.Tx
 }
 
 func NewRepository(db *Database) *Repository {
@@ -15,12 +21,16 @@ func NewRepository(db *Database) *Repository {
 }
 
 func (r *Repository) CreateUser(user models.User) error {
+	return r.CreateUserContext(context.Background(), user)
+}
+
+func (r *Repository) CreateUserContext(ctx context.Context, user models.User) error {
 	query := `
 		INSERT INTO users (id, username, created_at)
 		VALUES ($1, $2, $3)
 		ON CONFLICT (id) DO NOTHING
 	`
-	_, err := r.db.DB.Exec(query, user.ID, user.Username, user.CreatedAt)
+	_, err := r.ext().ExecContext(ctx, query, user.ID, user.Username, user.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -28,10 +38,17 @@ func (r *Repository) CreateUser(user models.User) error {
 }
 
 func (r *Repository) GetUser(userID int64) (*models.User, error) {
+	return r.GetUserContext(context.Background(), userID)
+}
+
+func (r *Repository) GetUserContext(ctx context.Context, userID int64) (*models.User, error) {
 	var user models.User
 	query := `SELECT id, username, created_at FROM users WHERE id = $1`
 
-	err := r.db.DB.Get(&user, query, userID)
+	err := sqlx.GetContext(ctx, r.ext(), &user, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -39,12 +56,16 @@ func (r *Repository) GetUser(userID int64) (*models.User, error) {
 }
 
 func (r *Repository) AddFavorite(favorite models.Favorite) error {
+	return r.AddFavoriteContext(context.Background(), favorite)
+}
+
+func (r *Repository) AddFavoriteContext(ctx context.Context, favorite models.Favorite) error {
 	query := `
 		INSERT INTO favorites (user_id, anime_id, title, poster_url, added_at)
 		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (user_id, anime_id) DO NOTHING
 	`
-	_, err := r.db.DB.Exec(query,
+	_, err := r.ext().ExecContext(ctx, query,
 		favorite.UserID,
 		favorite.AnimeID,
 		favorite.Title,
@@ -58,9 +79,13 @@ func (r *Repository) AddFavorite(favorite models.Favorite) error {
 }
 
 func (r *Repository) RemoveFavorite(userID int64, animeID int) error {
+	return r.RemoveFavoriteContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) RemoveFavoriteContext(ctx context.Context, userID int64, animeID int) error {
 	query := `DELETE FROM favorites WHERE user_id = $1 AND anime_id = $2`
 
-	_, err := r.db.DB.Exec(query, userID, animeID)
+	_, err := r.ext().ExecContext(ctx, query, userID, animeID)
 	if err != nil {
 		return fmt.Errorf("failed to remove favorite: %w", err)
 	}
@@ -68,6 +93,10 @@ func (r *Repository) RemoveFavorite(userID int64, animeID int) error {
 }
 
 func (r *Repository) GetFavorites(userID int64) ([]models.Favorite, error) {
+	return r.GetFavoritesContext(context.Background(), userID)
+}
+
+func (r *Repository) GetFavoritesContext(ctx context.Context, userID int64) ([]models.Favorite, error) {
 	var favorites []models.Favorite
 	query := `
 		SELECT id, user_id, anime_id, title, poster_url, added_at 
@@ -76,7 +105,7 @@ func (r *Repository) GetFavorites(userID int64) ([]models.Favorite, error) {
 		ORDER BY added_at DESC
 	`
 
-	err := r.db.DB.Select(&favorites, query, userID)
+	err := sqlx.SelectContext(ctx, r.ext(), &favorites, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get favorites: %w", err)
 	}
@@ -84,10 +113,14 @@ func (r *Repository) GetFavorites(userID int64) ([]models.Favorite, error) {
 }
 
 func (r *Repository) IsFavorite(userID int64, animeID int) (bool, error) {
+	return r.IsFavoriteContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) IsFavoriteContext(ctx context.Context, userID int64, animeID int) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM favorites WHERE user_id = $1 AND anime_id = $2)`
 
-	err := r.db.DB.Get(&exists, query, userID, animeID)
+	err := sqlx.GetContext(ctx, r.ext(), &exists, query, userID, animeID)
 	if err != nil {
 		return false, fmt.Errorf("failed to check favorite: %w", err)
 	}
@@ -95,10 +128,14 @@ func (r *Repository) IsFavorite(userID int64, animeID int) (bool, error) {
 }
 
 func (r *Repository) CountFavorites(userID int64) (int, error) {
+	return r.CountFavoritesContext(context.Background(), userID)
+}
+
+func (r *Repository) CountFavoritesContext(ctx context.Context, userID int64) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM favorites WHERE user_id = $1`
 
-	err := r.db.DB.Get(&count, query, userID)
+	err := sqlx.GetContext(ctx, r.ext(), &count, query, userID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count favorites: %w", err)
 	}
@@ -106,13 +143,17 @@ func (r *Repository) CountFavorites(userID int64) (int, error) {
 }
 
 func (r *Repository) AddRating(rating models.Rating) error {
+	return r.AddRatingContext(context.Background(), rating)
+}
+
+func (r *Repository) AddRatingContext(ctx context.Context, rating models.Rating) error {
 	query := `
 		INSERT INTO ratings (user_id, anime_id, score, rated_at)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (user_id, anime_id) 
 		DO UPDATE SET score = EXCLUDED.score, rated_at = EXCLUDED.rated_at
 	`
-	_, err := r.db.DB.Exec(query,
+	_, err := r.ext().ExecContext(ctx, query,
 		rating.UserID,
 		rating.AnimeID,
 		rating.Score,
@@ -125,22 +166,124 @@ func (r *Repository) AddRating(rating models.Rating) error {
 }
 
 func (r *Repository) GetRating(userID int64, animeID int) (*models.Rating, error) {
+	return r.GetRatingContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) GetRatingContext(ctx context.Context, userID int64, animeID int) (*models.Rating, error) {
 	var rating models.Rating
 	query := `SELECT id, user_id, anime_id, score, rated_at FROM ratings WHERE user_id = $1 AND anime_id = $2`
 
-	err := r.db.DB.Get(&rating, query, userID, animeID)
+	err := sqlx.GetContext(ctx, r.ext(), &rating, query, userID, animeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
-		return nil, nil
+		return nil, fmt.Errorf("failed to get rating: %w", err)
 	}
 	return &rating, nil
 }
 
 func (r *Repository) DeleteRating(userID int64, animeID int) error {
+	return r.DeleteRatingContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) DeleteRatingContext(ctx context.Context, userID int64, animeID int) error {
 	query := `DELETE FROM ratings WHERE user_id = $1 AND anime_id = $2`
 
-	_, err := r.db.DB.Exec(query, userID, animeID)
+	_, err := r.ext().ExecContext(ctx, query, userID, animeID)
 	if err != nil {
 		return fmt.Errorf("failed to delete rating: %w", err)
 	}
 	return nil
 }
+
+type RatingSort string
+
+const (
+	RatingSortDate  RatingSort = "date"
+	RatingSortScore RatingSort = "score"
+)
+
+type RatingsQuery struct {
+	Score  int
+	Sort   RatingSort
+	Limit  int
+	Offset int
+}
+
+func (r *Repository) GetUserRatings(userID int64, q RatingsQuery) ([]models.UserRating, error) {
+	return r.GetUserRatingsContext(context.Background(), userID, q)
+}
+
+func (r *Repository) GetUserRatingsContext(ctx context.Context, userID int64, q RatingsQuery) ([]models.UserRating, error) {
+	orderBy := "r.rated_at DESC, r.anime_id"
+	if q.Sort == RatingSortScore {
+		orderBy = "r.score DESC, r.rated_at DESC, r.anime_id"
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+
+	var ratings []models.UserRating
+	query := `
+		SELECT r.anime_id, r.score, r.rated_at,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title, '') AS title
+		FROM ratings r
+		LEFT JOIN animes a ON a.id = r.anime_id
+		LEFT JOIN favorites f ON f.user_id = r.user_id AND f.anime_id = r.anime_id
+		WHERE r.user_id = $1 AND ($2 = 0 OR r.score = $2)
+		ORDER BY ` + orderBy + `
+		LIMIT $3 OFFSET $4
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &ratings, query, userID, q.Score, limit, q.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+func (r *Repository) CountRatingsByScore(userID int64) (map[int]int, error) {
+	return r.CountRatingsByScoreContext(context.Background(), userID)
+}
+
+func (r *Repository) CountRatingsByScoreContext(ctx context.Context, userID int64) (map[int]int, error) {
+	var rows []struct {
+		Score int `db:"score"`
+		Count int `db:"count"`
+	}
+	query := `SELECT score, COUNT(*) AS count FROM ratings WHERE user_id = $1 GROUP BY score`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &rows, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ratings: %w", err)
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.Score] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *Repository) GetRatingHistory(userID int64, animeID int) ([]models.RatingChange, error) {
+	return r.GetRatingHistoryContext(context.Background(), userID, animeID)
+}
+
+func (r *Repository) GetRatingHistoryContext(ctx context.Context, userID int64, animeID int) ([]models.RatingChange, error) {
+	var history []models.RatingChange
+	query := `
+		SELECT score, rated_at
+		FROM rating_history
+		WHERE user_id = $1 AND anime_id = $2
+		ORDER BY rated_at, id
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &history, query, userID, animeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating history: %w", err)
+	}
+	return history, nil
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"database/sql"
+	"errors"
+	"math"
 	"regexp"
 	"testing"
 	"time"
@@ -484,8 +486,8 @@ func TestGetRating_NotFound(t *testing.T) {
 		WillReturnRows(rows)
 
 	rating, err := repo.GetRating(userID, animeID)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 
 	if rating != nil {
@@ -641,3 +643,83 @@ func TestMultipleFavorites_Order(t *testing.T) {
 		t.Errorf("expected first to be 'One Piece', got '%s'", favorites[0].Title)
 	}
 }
+
+func TestGetUserRatings_ByScoreWithPagination(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"anime_id", "score", "rated_at", "title"}).
+		AddRow(1, 10, now, "Steins;Gate").
+		AddRow(2, 9, now, "Monster")
+
+	mock.ExpectQuery(`ORDER BY r\.score DESC, r\.rated_at DESC, r\.anime_id\s+LIMIT \$3 OFFSET \$4`).
+		WithArgs(userID, 0, 10, 20).
+		WillReturnRows(rows)
+
+	ratings, err := repo.GetUserRatings(userID, RatingsQuery{Sort: RatingSortScore, Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ratings) != 2 || ratings[0].Title != "Steins;Gate" {
+		t.Errorf("unexpected ratings: %+v", ratings)
+	}
+}
+
+func TestGetUserRatings_FilterByScoreDefaultsToDate(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+
+	mock.ExpectQuery(`ORDER BY r\.rated_at DESC, r\.anime_id`).
+		WithArgs(userID, 9, math.MaxInt32, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id", "score", "rated_at", "title"}))
+
+	if _, err := repo.GetUserRatings(userID, RatingsQuery{Score: 9}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestCountRatingsByScore(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT score, COUNT(*) AS count FROM ratings WHERE user_id = $1 GROUP BY score`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"score", "count"}).AddRow(10, 2).AddRow(7, 5))
+
+	counts, err := repo.CountRatingsByScore(userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if counts[10] != 2 || counts[7] != 5 || counts[1] != 0 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestGetRatingHistory(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	userID := int64(123)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"score", "rated_at"}).
+		AddRow(7, now.Add(-time.Hour)).
+		AddRow(9, now)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM rating_history`)).
+		WithArgs(userID, 1).
+		WillReturnRows(rows)
+
+	history, err := repo.GetRatingHistory(userID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(history) != 2 || history[1].Score != 9 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
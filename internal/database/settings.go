@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func (r *Repository) GetUserSettings(userID int64) (*models.UserSettings, error) {
+	return r.GetUserSettingsContext(context.Background(), userID)
+}
+
+// GetUserSettingsContext returns ErrNotFound for users who never changed a setting
+func (r *Repository) GetUserSettingsContext(ctx context.Context, userID int64) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	query := `
+		SELECT user_id, COALESCE(language, '') AS language, title_display, time_zone,
+			hide_nsfw, notify_follows, notify_wrapped, hide_spoilers, page_size
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &settings, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *Repository) SaveUserSettings(settings models.UserSettings) error {
+	return r.SaveUserSettingsContext(context.Background(), settings)
+}
+
+func (r *Repository) SaveUserSettingsContext(ctx context.Context, settings models.UserSettings) error {
+	query := `
+		INSERT INTO user_settings (user_id, language, title_display, time_zone,
+			hide_nsfw, notify_follows, notify_wrapped, hide_spoilers, page_size, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id) DO UPDATE SET
+			language = EXCLUDED.language,
+			title_display = EXCLUDED.title_display,
+			time_zone = EXCLUDED.time_zone,
+			hide_nsfw = EXCLUDED.hide_nsfw,
+			notify_follows = EXCLUDED.notify_follows,
+			notify_wrapped = EXCLUDED.notify_wrapped,
+			hide_spoilers = EXCLUDED.hide_spoilers,
+			page_size = EXCLUDED.page_size,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	language := sql.NullString{String: settings.Language, Valid: settings.Language != ""}
+	_, err := r.ext().ExecContext(ctx, query,
+		settings.UserID,
+		language,
+		settings.TitleDisplay,
+		settings.TimeZone,
+		settings.HideNSFW,
+		settings.NotifyFollows,
+		settings.NotifyWrapped,
+		settings.HideSpoilers,
+		settings.PageSize,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetUserLanguage(userID int64) (string, error) {
+	return r.GetUserLanguageContext(context.Background(), userID)
+}
+
+// GetUserLanguageContext returns the user's language override, "" when the
+// bot should follow the Telegram client
+func (r *Repository) GetUserLanguageContext(ctx context.Context, userID int64) (string, error) {
+	var language sql.NullString
+	query := `
+		SELECT s.language FROM users u
+		LEFT JOIN user_settings s ON s.user_id = u.id
+		WHERE u.id = $1
+	`
+
+	err := sqlx.GetContext(ctx, r.ext(), &language, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get language: %w", err)
+	}
+	return language.String, nil
+}
+
+func (r *Repository) SetUserLanguage(userID int64, language string) error {
+	return r.SetUserLanguageContext(context.Background(), userID, language)
+}
+
+// SetUserLanguageContext stores an override; "" goes back to the Telegram
+// client's language. The other settings keep their values or defaults
+func (r *Repository) SetUserLanguageContext(ctx context.Context, userID int64, language string) error {
+	query := `
+		INSERT INTO user_settings (user_id, language, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET language = EXCLUDED.language, updated_at = EXCLUDED.updated_at
+	`
+
+	value := sql.NullString{String: language, Valid: language != ""}
+	if _, err := r.ext().ExecContext(ctx, query, userID, value, time.Now()); err != nil {
+		return fmt.Errorf("failed to update language: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func (r *Repository) GetAllRatings() ([]models.Rating, error) {
+	return r.GetAllRatingsContext(context.Background())
+}
+
+// GetAllRatingsContext returns only what the similarity model needs from every rating
+func (r *Repository) GetAllRatingsContext(ctx context.Context) ([]models.Rating, error) {
+	var ratings []models.Rating
+	query := `SELECT user_id, anime_id, score FROM ratings ORDER BY user_id, anime_id`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &ratings, query); err != nil {
+		return nil, fmt.Errorf("failed to get all ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+func (r *Repository) GetSimilarityDirty() (map[int]int, error) {
+	return r.GetSimilarityDirtyContext(context.Background())
+}
+
+// GetSimilarityDirtyContext maps every anime with changed ratings to its dirty version
+func (r *Repository) GetSimilarityDirtyContext(ctx context.Context) (map[int]int, error) {
+	var rows []struct {
+		AnimeID int `db:"anime_id"`
+		Version int `db:"version"`
+	}
+	query := `SELECT anime_id, version FROM similarity_dirty`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to get dirty anime: %w", err)
+	}
+
+	dirty := make(map[int]int, len(rows))
+	for _, row := range rows {
+		dirty[row.AnimeID] = row.Version
+	}
+	return dirty, nil
+}
+
+func (r *Repository) GetNeighborOwners(animeIDs []int) ([]int, error) {
+	return r.GetNeighborOwnersContext(context.Background(), animeIDs)
+}
+
+// GetNeighborOwnersContext returns anime whose stored neighbour lists mention any of animeIDs
+func (r *Repository) GetNeighborOwnersContext(ctx context.Context, animeIDs []int) ([]int, error) {
+	if len(animeIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(animeIDs))
+	placeholders := make([]string, len(animeIDs))
+	for i, id := range animeIDs {
+		args[i] = id
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	var owners []int
+	query := `
+		SELECT DISTINCT anime_id FROM anime_neighbors
+		WHERE neighbor_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY anime_id
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &owners, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get neighbor owners: %w", err)
+	}
+	return owners, nil
+}
+
+func (r *Repository) ReplaceAnimeNeighbors(animeID int, neighbors []models.AnimeNeighbor) error {
+	return r.ReplaceAnimeNeighborsContext(context.Background(), animeID, neighbors)
+}
+
+func (r *Repository) ReplaceAnimeNeighborsContext(ctx context.Context, animeID int, neighbors []models.AnimeNeighbor) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		if _, err := tx.ext().ExecContext(ctx, `DELETE FROM anime_neighbors WHERE anime_id = $1`, animeID); err != nil {
+			return fmt.Errorf("failed to clear neighbors: %w", err)
+		}
+
+		query := `
+			INSERT INTO anime_neighbors (anime_id, neighbor_id, similarity, common_users, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		now := time.Now()
+		for _, neighbor := range neighbors {
+			_, err := tx.ext().ExecContext(ctx, query, animeID, neighbor.NeighborID, neighbor.Similarity, neighbor.CommonUsers, now)
+			if err != nil {
+				return fmt.Errorf("failed to save neighbor: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) ClearSimilarityDirty(versions map[int]int) error {
+	return r.ClearSimilarityDirtyContext(context.Background(), versions)
+}
+
+// ClearSimilarityDirtyContext drops dirty marks the job has processed; a rating
+// changed meanwhile bumps the version, so that anime stays dirty for the next run
+func (r *Repository) ClearSimilarityDirtyContext(ctx context.Context, versions map[int]int) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		query := `DELETE FROM similarity_dirty WHERE anime_id = $1 AND version = $2`
+		for animeID, version := range versions {
+			if _, err := tx.ext().ExecContext(ctx, query, animeID, version); err != nil {
+				return fmt.Errorf("failed to clear dirty anime: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) GetAnimeNeighbors(animeID int, limit int) ([]models.AnimeNeighbor, error) {
+	return r.GetAnimeNeighborsContext(context.Background(), animeID, limit)
+}
+
+func (r *Repository) GetAnimeNeighborsContext(ctx context.Context, animeID int, limit int) ([]models.AnimeNeighbor, error) {
+	var neighbors []models.AnimeNeighbor
+	query := `
+		SELECT n.anime_id, n.neighbor_id, n.similarity, n.common_users,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), '') AS title
+		FROM anime_neighbors n
+		LEFT JOIN animes a ON a.id = n.neighbor_id
+		WHERE n.anime_id = $1
+		ORDER BY n.similarity DESC, n.neighbor_id
+		LIMIT $2
+	`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &neighbors, query, animeID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get anime neighbors: %w", err)
+	}
+	return neighbors, nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestReplaceAnimeNeighbors(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM anime_neighbors WHERE anime_id = $1`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO anime_neighbors`)).
+		WithArgs(1, 2, 0.9, 4, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	neighbors := []models.AnimeNeighbor{{AnimeID: 1, NeighborID: 2, Similarity: 0.9, CommonUsers: 4}}
+	if err := repo.ReplaceAnimeNeighbors(1, neighbors); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestClearSimilarityDirty_MatchesVersion(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM similarity_dirty WHERE anime_id = $1 AND version = $2`)).
+		WithArgs(5, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.ClearSimilarityDirty(map[int]int{5: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetNeighborOwners_NoIDs(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	owners, err := repo.GetNeighborOwners(nil)
+	if err != nil || owners != nil {
+		t.Errorf("expected no owners, got %v (%v)", owners, err)
+	}
+}
@@ -0,0 +1,919 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/logger"
+)
+
+func newSQLiteRepo(t *testing.T) *Repository {
+	db, err := Connect("sqlite://"+filepath.Join(t.TempDir(), "bot.db"), logger.New())
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Migrate("up"); err != nil {
+		t.Fatalf("failed to migrate sqlite: %v", err)
+	}
+	return NewRepository(db)
+}
+
+func TestParseDatabaseURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		driver string
+	}{
+		{"postgres://u:p@localhost:5432/db?sslmode=disable", DriverPostgres},
+		{"postgresql://localhost/db", DriverPostgres},
+		{"sqlite:///var/lib/bot/bot.db", DriverSQLite},
+		{"sqlite:bot.db", DriverSQLite},
+		{"file:bot.db", DriverSQLite},
+		{"host=localhost port=5432 user=bot dbname=anime sslmode=disable", DriverPostgres},
+	}
+
+	for _, tt := range tests {
+		driver, _ := parseDatabaseURL(tt.url)
+		if driver != tt.driver {
+			t.Errorf("parseDatabaseURL(%q) = %q; want %q", tt.url, driver, tt.driver)
+		}
+	}
+
+	// key=value DSNs reach lib/pq untouched
+	dsn := "host=localhost dbname=anime"
+	if _, got := parseDatabaseURL(dsn); got != dsn {
+		t.Errorf("expected the DSN to be passed through, got %q", got)
+	}
+}
+
+func TestSQLite_FavoritesAndRatings(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	if err := repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := repo.AddFavorite(models.Favorite{UserID: 123, AnimeID: 1, Title: "Death Note", AddedAt: now}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	// duplicates are ignored
+	if err := repo.AddFavorite(models.Favorite{UserID: 123, AnimeID: 1, Title: "Death Note", AddedAt: now}); err != nil {
+		t.Fatalf("failed to add duplicate favorite: %v", err)
+	}
+
+	count, err := repo.CountFavorites(123)
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 favorite, got %d (%v)", count, err)
+	}
+
+	for _, score := range []int{7, 7, 9} {
+		if err := repo.AddRating(models.Rating{UserID: 123, AnimeID: 1, Score: score, RatedAt: time.Now()}); err != nil {
+			t.Fatalf("failed to add rating: %v", err)
+		}
+	}
+
+	rating, err := repo.GetRating(123, 1)
+	if err != nil || rating == nil || rating.Score != 9 {
+		t.Fatalf("expected rating 9, got %+v (%v)", rating, err)
+	}
+
+	history, err := repo.GetRatingHistory(123, 1)
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	if len(history) != 2 || history[0].Score != 7 || history[1].Score != 9 {
+		t.Errorf("expected history 7 -> 9, got %+v", history)
+	}
+
+	ratings, err := repo.GetUserRatings(123, RatingsQuery{Score: 9, Sort: RatingSortScore})
+	if err != nil || len(ratings) != 1 || ratings[0].Title != "Death Note" {
+		t.Errorf("unexpected user ratings: %+v (%v)", ratings, err)
+	}
+}
+
+func TestSQLite_CollectionUniqueName(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now})
+
+	if _, err := repo.CreateCollection(models.Collection{UserID: 123, Name: "comfy", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create collection: %v", err)
+	}
+	_, err := repo.CreateCollection(models.Collection{UserID: 123, Name: "comfy", CreatedAt: now})
+	if !errors.Is(err, ErrCollectionExists) {
+		t.Errorf("expected ErrCollectionExists, got %v", err)
+	}
+}
+
+func TestSQLite_CollectionItemsPage(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now})
+	collectionID, err := repo.CreateCollection(models.Collection{UserID: 123, Name: "comfy", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("failed to create collection: %v", err)
+	}
+	// 2 and 3 share a timestamp, anime_id has to break the tie
+	for animeID, minutes := range map[int]int{1: 0, 2: 1, 3: 1, 4: 2, 5: 3} {
+		item := models.CollectionItem{CollectionID: collectionID, AnimeID: animeID, Title: "title", AddedAt: now.Add(time.Duration(minutes) * time.Minute)}
+		if err := repo.AddToCollection(item); err != nil {
+			t.Fatalf("failed to add item: %v", err)
+		}
+	}
+
+	var ids []int
+	var after *CollectionCursor
+	for {
+		page, err := repo.GetCollectionItemsPage(collectionID, after, 2)
+		if err != nil {
+			t.Fatalf("failed to get page: %v", err)
+		}
+		for _, item := range page {
+			ids = append(ids, item.AnimeID)
+		}
+		if len(page) < 2 {
+			break
+		}
+		after = CollectionCursorAfter(page[len(page)-1])
+	}
+	if fmt.Sprint(ids) != fmt.Sprint([]int{5, 4, 3, 2, 1}) {
+		t.Errorf("expected newest first without gaps, got %v", ids)
+	}
+}
+
+func TestSQLite_DeleteUserCascades(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now})
+	repo.AddFavorite(models.Favorite{UserID: 123, AnimeID: 1, Title: "Death Note", AddedAt: now})
+	repo.AddRating(models.Rating{UserID: 123, AnimeID: 1, Score: 8, RatedAt: now})
+	repo.SaveNote(models.Note{UserID: 123, AnimeID: 1, Text: "classic", CreatedAt: now, UpdatedAt: now})
+
+	deleted, err := repo.DeleteUser(123, "test")
+	if err != nil || !deleted {
+		t.Fatalf("expected user to be deleted, got %v (%v)", deleted, err)
+	}
+
+	library, err := repo.GetLibrary(123)
+	if err != nil {
+		t.Fatalf("failed to get library: %v", err)
+	}
+	if len(library) != 0 {
+		t.Errorf("expected user data to be gone, got %+v", library)
+	}
+	history, _ := repo.GetRatingHistory(123, 1)
+	if len(history) != 0 {
+		t.Errorf("expected rating history to be gone, got %+v", history)
+	}
+}
+
+func TestSQLite_ImportLibrary(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+	eight, six := 8, 6
+
+	repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now})
+	repo.AddRating(models.Rating{UserID: 123, AnimeID: 2, Score: 5, RatedAt: now})
+
+	result, err := repo.ImportLibrary(123, []models.LibraryEntry{
+		{AnimeID: 1, Title: "Death Note", AddedAt: &now, Score: &eight, RatedAt: &now, Note: "classic"},
+		{AnimeID: 2, Title: "Naruto", Score: &six, RatedAt: &now},
+	})
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if result.Imported != 1 || result.Conflicts != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	library, err := repo.GetLibrary(123)
+	if err != nil || len(library) != 2 {
+		t.Fatalf("expected 2 library entries, got %+v (%v)", library, err)
+	}
+	if library[0].Note != "classic" || library[1].Score == nil || *library[1].Score != 5 {
+		t.Errorf("unexpected library: %+v", library)
+	}
+}
+
+func TestSQLite_FavoritesPage(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	if err := repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	titles := []string{"Стальной алхимик", "Bleach", "Naruto", "Monster", "Akira"}
+	for i, title := range titles {
+		animeID := i + 1
+		favorite := models.Favorite{UserID: 123, AnimeID: animeID, Title: title, AddedAt: now.Add(time.Duration(i) * time.Minute)}
+		if err := repo.AddFavorite(favorite); err != nil {
+			t.Fatalf("failed to add favorite: %v", err)
+		}
+		if err := repo.UpsertAnime(models.Anime{ID: animeID, Name: title, Score: fmt.Sprintf("%d.5", i+5)}, now); err != nil {
+			t.Fatalf("failed to upsert anime: %v", err)
+		}
+	}
+	// ties on the sort key must still page deterministically
+	for _, animeID := range []int{2, 4} {
+		if err := repo.AddRating(models.Rating{UserID: 123, AnimeID: animeID, Score: 8, RatedAt: now}); err != nil {
+			t.Fatalf("failed to add rating: %v", err)
+		}
+	}
+
+	walk := func(q FavoritesQuery) []int {
+		var ids []int
+		for {
+			page, err := repo.GetFavoritesPage(123, q)
+			if err != nil {
+				t.Fatalf("failed to get page: %v", err)
+			}
+			for _, item := range page {
+				ids = append(ids, item.AnimeID)
+			}
+			if len(page) < q.Limit {
+				return ids
+			}
+			q.After = CursorAfter(page[len(page)-1])
+		}
+	}
+
+	tests := []struct {
+		sort FavoriteSort
+		want []int
+	}{
+		{FavoriteSortDate, []int{5, 4, 3, 2, 1}},
+		{FavoriteSortTitle, []int{5, 2, 4, 3, 1}},
+		{FavoriteSortRating, []int{4, 2, 5, 3, 1}},
+		{FavoriteSortScore, []int{5, 4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		got := walk(FavoritesQuery{Sort: tt.sort, Limit: 2})
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("sort %s: got %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	found := walk(FavoritesQuery{Search: "АЛХИМ", Limit: 2})
+	if len(found) != 1 || found[0] != 1 {
+		t.Errorf("expected case-insensitive cyrillic match, got %v", found)
+	}
+
+	count, err := repo.CountFavoritesMatching(123, "a")
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 titles containing 'a', got %d (%v)", count, err)
+	}
+}
+
+func TestSQLite_MigrateEmbedded(t *testing.T) {
+	db, err := Connect("sqlite://"+filepath.Join(t.TempDir(), "bot.db"), logger.New())
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	tableExists := func(name string) bool {
+		var count int
+		if err := db.DB.Get(&count, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1`, name); err != nil {
+			t.Fatalf("failed to inspect schema: %v", err)
+		}
+		return count == 1
+	}
+
+	for _, command := range []string{"up", "status", "version", "redo"} {
+		if err := db.Migrate(command); err != nil {
+			t.Fatalf("migrate %s failed: %v", command, err)
+		}
+	}
+	if !tableExists("audit_log") {
+		t.Fatal("expected audit_log after migrate up")
+	}
+
+	if err := db.Migrate("down"); err != nil {
+		t.Fatalf("migrate down failed: %v", err)
+	}
+	if err := db.Migrate("down-to", "6"); err != nil {
+		t.Fatalf("migrate down-to failed: %v", err)
+	}
+	if tableExists("audit_log") {
+		t.Error("expected audit_log to be dropped below version 7")
+	}
+
+	if err := db.Migrate("bogus"); err == nil {
+		t.Error("expected error for unknown command")
+	}
+}
+
+func TestSQLite_CommunityRatingTriggers(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	for _, userID := range []int64{1, 2, 3} {
+		if err := repo.CreateUser(models.User{ID: userID, Username: "u", CreatedAt: now}); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if err := repo.UpsertAnime(models.Anime{ID: 10, Name: "Monster", Russian: "Монстр"}, now); err != nil {
+		t.Fatalf("failed to upsert anime: %v", err)
+	}
+
+	rate := func(userID int64, animeID, score int) {
+		if err := repo.AddRating(models.Rating{UserID: userID, AnimeID: animeID, Score: score, RatedAt: now}); err != nil {
+			t.Fatalf("failed to add rating: %v", err)
+		}
+	}
+	rate(1, 10, 10)
+	rate(2, 10, 6)
+	rate(3, 10, 8)
+	rate(1, 20, 5)
+	// re-rating replaces the old score in the aggregate
+	rate(2, 10, 9)
+
+	rating, err := repo.GetCommunityRating(10)
+	if err != nil {
+		t.Fatalf("failed to get community rating: %v", err)
+	}
+	if rating.Votes != 3 || rating.Average != 9 || rating.Title != "Монстр" {
+		t.Errorf("unexpected aggregate: %+v", rating)
+	}
+	if rating.Distribution[6] != 0 || rating.Distribution[9] != 1 || rating.Distribution[10] != 1 {
+		t.Errorf("unexpected distribution: %v", rating.Distribution)
+	}
+
+	if err := repo.DeleteRating(3, 10); err != nil {
+		t.Fatalf("failed to delete rating: %v", err)
+	}
+	if _, err := repo.DeleteUser(1, "test"); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+
+	rating, err = repo.GetCommunityRating(10)
+	if err != nil || rating.Votes != 1 || rating.Average != 9 {
+		t.Errorf("expected only user 2's vote to remain, got %+v (%v)", rating, err)
+	}
+	if _, err := repo.GetCommunityRating(20); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected aggregate to vanish with its last vote, got %v", err)
+	}
+
+	top, err := repo.GetCommunityTop(1, 10)
+	if err != nil || len(top) != 1 || top[0].AnimeID != 10 {
+		t.Errorf("unexpected top: %+v (%v)", top, err)
+	}
+	top, err = repo.GetCommunityTop(2, 10)
+	if err != nil || len(top) != 0 {
+		t.Errorf("expected min votes to filter the top, got %+v (%v)", top, err)
+	}
+}
+
+func TestSQLite_StatsEntries(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+	old := now.AddDate(-2, 0, 0)
+
+	if err := repo.CreateUser(models.User{ID: 1, Username: "u", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	animes := []models.Anime{
+		{ID: 10, Name: "Monster", Kind: "tv", Episodes: 74, Duration: 24, Genres: []models.Genre{{ID: 1, Name: "Mystery"}}},
+		{ID: 20, Name: "Akira", Kind: "movie", Episodes: 1, Duration: 124, Genres: []models.Genre{{ID: 1, Name: "Mystery"}, {ID: 2, Name: "Sci-Fi"}}},
+	}
+	for _, anime := range animes {
+		if err := repo.UpsertAnime(anime, now); err != nil {
+			t.Fatalf("failed to upsert anime: %v", err)
+		}
+	}
+	if err := repo.AddFavorite(models.Favorite{UserID: 1, AnimeID: 10, Title: "Monster", AddedAt: now}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 10, Score: 9, RatedAt: now}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 20, Score: 7, RatedAt: old}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+
+	entries, err := repo.GetStatsEntries(1, StatsQuery{})
+	if err != nil {
+		t.Fatalf("failed to get stats entries: %v", err)
+	}
+	if len(entries) != 2 || !entries[0].Favorite || entries[1].Favorite || entries[1].Duration != 124 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	recent := StatsQuery{From: now.AddDate(-1, 0, 0)}
+	entries, err = repo.GetStatsEntries(1, recent)
+	if err != nil {
+		t.Fatalf("failed to get stats entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].AnimeID != 10 {
+		t.Errorf("expected only the recent title, got %+v", entries)
+	}
+
+	genres, err := repo.CountStatsGenres(1, StatsQuery{}, 8)
+	if err != nil {
+		t.Fatalf("failed to count genres: %v", err)
+	}
+	if len(genres) != 2 || genres[0].Name != "Mystery" || genres[0].Count != 2 {
+		t.Errorf("unexpected genres: %+v", genres)
+	}
+}
+
+func TestSQLite_WrappedActivity(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+	year := StatsQuery{From: time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.Local)}
+	year.To = year.From.AddDate(1, 0, 0)
+
+	for _, userID := range []int64{1, 2} {
+		if err := repo.CreateUser(models.User{ID: userID, Username: "u", CreatedAt: now}); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if err := repo.UpsertAnime(models.Anime{ID: 10, Name: "Monster", Russian: "Монстр", Episodes: 74, Duration: 24}, now); err != nil {
+		t.Fatalf("failed to upsert anime: %v", err)
+	}
+	if err := repo.AddFavorite(models.Favorite{UserID: 1, AnimeID: 10, Title: "Monster", AddedAt: now}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 10, Score: 9, RatedAt: now}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+	// activity outside the year doesn't make a recipient
+	if err := repo.AddRating(models.Rating{UserID: 2, AnimeID: 10, Score: 7, RatedAt: year.From.AddDate(-1, 0, 0)}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+
+	events, err := repo.GetActivity(1, year)
+	if err != nil {
+		t.Fatalf("failed to get activity: %v", err)
+	}
+	if len(events) != 2 || events[0].Score != nil || events[1].Score == nil || *events[1].Score != 9 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[1].Title != "Монстр" || events[1].Name != "Monster" || events[1].Episodes != 74 || events[1].At.IsZero() {
+		t.Errorf("unexpected rating event: %+v", events[1])
+	}
+
+	recipients, err := repo.GetWrappedRecipients(now.Year(), year.From, year.To)
+	if err != nil {
+		t.Fatalf("failed to get recipients: %v", err)
+	}
+	if len(recipients) != 1 || recipients[0] != 1 {
+		t.Fatalf("unexpected recipients: %v", recipients)
+	}
+
+	if err := repo.MarkWrappedSent(1, now.Year()); err != nil {
+		t.Fatalf("failed to mark sent: %v", err)
+	}
+	// marking twice is a no-op
+	if err := repo.MarkWrappedSent(1, now.Year()); err != nil {
+		t.Fatalf("failed to mark sent again: %v", err)
+	}
+	recipients, err = repo.GetWrappedRecipients(now.Year(), year.From, year.To)
+	if err != nil || len(recipients) != 0 {
+		t.Errorf("expected no recipients after delivery, got %v (%v)", recipients, err)
+	}
+}
+
+func TestSQLite_RecommendationData(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	if err := repo.CreateUser(models.User{ID: 1, Username: "u", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	scifi := models.Genre{ID: 24, Name: "Sci-Fi", Russian: "Фантастика"}
+	drama := models.Genre{ID: 8, Name: "Drama", Russian: "Драма"}
+	animes := []models.Anime{
+		{ID: 9253, Name: "Steins;Gate", Genres: []models.Genre{scifi, drama}},
+		{ID: 10, Name: "Planetes", Genres: []models.Genre{scifi, drama}},
+		{ID: 11, Name: "Clannad", Genres: []models.Genre{drama}},
+		{ID: 12, Name: "Disliked", Genres: []models.Genre{scifi}},
+	}
+	for _, anime := range animes {
+		if err := repo.UpsertAnime(anime, now); err != nil {
+			t.Fatalf("failed to upsert anime: %v", err)
+		}
+	}
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 9253, Score: 10, RatedAt: now}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+	if err := repo.SaveRecommendationFeedback(1, 12, true); err != nil {
+		t.Fatalf("failed to save feedback: %v", err)
+	}
+	// feedback can be changed later
+	if err := repo.SaveRecommendationFeedback(1, 12, false); err != nil {
+		t.Fatalf("failed to update feedback: %v", err)
+	}
+
+	signals, err := repo.GetTasteSignals(1)
+	if err != nil {
+		t.Fatalf("failed to get taste signals: %v", err)
+	}
+	if len(signals) != 2 {
+		t.Fatalf("expected 2 signals, got %+v", signals)
+	}
+	if signals[0].AnimeID != 12 || signals[0].Liked == nil || *signals[0].Liked || signals[0].Score != nil {
+		t.Errorf("unexpected feedback signal: %+v", signals[0])
+	}
+	if signals[1].Title != "Steins;Gate" || signals[1].Score == nil || *signals[1].Score != 10 || len(signals[1].Genres) != 2 || signals[1].Genres[0].ID != scifi.ID {
+		t.Errorf("unexpected rating signal: %+v", signals[1])
+	}
+
+	candidates, err := repo.GetCatalogCandidates(1, []int{scifi.ID, drama.ID}, 10)
+	if err != nil {
+		t.Fatalf("failed to get candidates: %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].ID != 10 || candidates[1].ID != 11 || len(candidates[0].Genres) != 2 {
+		t.Errorf("unexpected candidates: %+v", candidates)
+	}
+
+	candidates, err = repo.GetCatalogCandidates(1, []int{scifi.ID, drama.ID}, 1)
+	if err != nil || len(candidates) != 1 || candidates[0].ID != 10 {
+		t.Errorf("expected the best matching candidate only, got %+v (%v)", candidates, err)
+	}
+}
+
+func TestSQLite_SimilarityDirtyTriggers(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	if err := repo.CreateUser(models.User{ID: 1, Username: "u", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 10, Score: 7, RatedAt: now}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+	// re-saving the same score is not a change
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 10, Score: 7, RatedAt: now}); err != nil {
+		t.Fatalf("failed to re-add rating: %v", err)
+	}
+
+	dirty, err := repo.GetSimilarityDirty()
+	if err != nil || len(dirty) != 1 || dirty[10] != 1 {
+		t.Fatalf("expected anime 10 dirty once, got %v (%v)", dirty, err)
+	}
+
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 10, Score: 9, RatedAt: now}); err != nil {
+		t.Fatalf("failed to update rating: %v", err)
+	}
+	// the job saw version 1, so the newer change has to survive the clear
+	if err := repo.ClearSimilarityDirty(map[int]int{10: 1}); err != nil {
+		t.Fatalf("failed to clear dirty: %v", err)
+	}
+	dirty, err = repo.GetSimilarityDirty()
+	if err != nil || dirty[10] != 2 {
+		t.Fatalf("expected anime 10 still dirty, got %v (%v)", dirty, err)
+	}
+
+	if err := repo.ClearSimilarityDirty(dirty); err != nil {
+		t.Fatalf("failed to clear dirty: %v", err)
+	}
+	if err := repo.DeleteRating(1, 10); err != nil {
+		t.Fatalf("failed to delete rating: %v", err)
+	}
+	dirty, err = repo.GetSimilarityDirty()
+	if err != nil || dirty[10] != 1 {
+		t.Errorf("expected delete to mark anime 10, got %v (%v)", dirty, err)
+	}
+}
+
+func TestSQLite_AnimeNeighbors(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	if err := repo.UpsertAnime(models.Anime{ID: 2, Name: "Cowboy Bebop", Russian: "Ковбой Бибоп"}, now); err != nil {
+		t.Fatalf("failed to upsert anime: %v", err)
+	}
+	neighbors := []models.AnimeNeighbor{
+		{NeighborID: 3, Similarity: 0.4, CommonUsers: 2},
+		{NeighborID: 2, Similarity: 0.8, CommonUsers: 5},
+	}
+	if err := repo.ReplaceAnimeNeighbors(1, neighbors); err != nil {
+		t.Fatalf("failed to save neighbors: %v", err)
+	}
+
+	stored, err := repo.GetAnimeNeighbors(1, 10)
+	if err != nil || len(stored) != 2 {
+		t.Fatalf("expected 2 neighbors, got %+v (%v)", stored, err)
+	}
+	if stored[0].NeighborID != 2 || stored[0].Title != "Ковбой Бибоп" || stored[0].CommonUsers != 5 {
+		t.Errorf("unexpected first neighbor: %+v", stored[0])
+	}
+	if stored[1].Title != "" {
+		t.Errorf("expected empty title outside the catalog, got %+v", stored[1])
+	}
+
+	owners, err := repo.GetNeighborOwners([]int{3, 99})
+	if err != nil || len(owners) != 1 || owners[0] != 1 {
+		t.Errorf("expected anime 1 to own neighbor 3, got %v (%v)", owners, err)
+	}
+
+	if err := repo.ReplaceAnimeNeighbors(1, nil); err != nil {
+		t.Fatalf("failed to clear neighbors: %v", err)
+	}
+	if stored, _ := repo.GetAnimeNeighbors(1, 10); len(stored) != 0 {
+		t.Errorf("expected neighbors replaced, got %+v", stored)
+	}
+}
+
+func TestSQLite_Friends(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+
+	for _, user := range []models.User{{ID: 1, Username: "me"}, {ID: 2, Username: "alice"}, {ID: 3, Username: "bob"}} {
+		user.CreatedAt = now
+		if err := repo.CreateUser(user); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if err := repo.UpsertAnime(models.Anime{ID: 10, Name: "Monster"}, now); err != nil {
+		t.Fatalf("failed to upsert anime: %v", err)
+	}
+
+	code, err := repo.EnsureFriendCode(2, "alice234")
+	if err != nil || code != "alice234" {
+		t.Fatalf("expected new code, got %q (%v)", code, err)
+	}
+	if code, _ := repo.EnsureFriendCode(2, "other234"); code != "alice234" {
+		t.Errorf("expected code to stay the same, got %q", code)
+	}
+	friend, err := repo.GetUserByFriendCode("alice234")
+	if err != nil || friend.ID != 2 {
+		t.Fatalf("expected alice by code, got %+v (%v)", friend, err)
+	}
+
+	for _, followee := range []int64{2, 3} {
+		if err := repo.Follow(1, followee); err != nil {
+			t.Fatalf("failed to follow: %v", err)
+		}
+	}
+	if err := repo.Follow(1, 2); err != nil {
+		t.Fatalf("failed to follow twice: %v", err)
+	}
+	following, err := repo.GetFollowing(1)
+	if err != nil || len(following) != 2 || following[0].Username != "alice" {
+		t.Fatalf("unexpected following: %+v (%v)", following, err)
+	}
+
+	if err := repo.AddFavorite(models.Favorite{UserID: 2, AnimeID: 10, Title: "Monster", AddedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	for userID, score := range map[int64]int{2: 9, 3: 7} {
+		if err := repo.AddRating(models.Rating{UserID: userID, AnimeID: 10, Score: score, RatedAt: now}); err != nil {
+			t.Fatalf("failed to add rating: %v", err)
+		}
+	}
+
+	activity, err := repo.GetFriendsActivity(1, 10)
+	if err != nil || len(activity) != 3 {
+		t.Fatalf("expected 3 events, got %+v (%v)", activity, err)
+	}
+	if last := activity[2]; last.Score != nil || last.Username != "alice" || last.Title != "Monster" {
+		t.Errorf("expected the older favorite last, got %+v", last)
+	}
+
+	ratings, err := repo.GetFriendRatings(1, 10, 5)
+	if err != nil || len(ratings) != 2 || ratings[0].Username != "alice" || ratings[0].Score != 9 {
+		t.Fatalf("unexpected friend ratings: %+v (%v)", ratings, err)
+	}
+
+	// a hidden list disappears from followers' views
+	if err := repo.SetShareList(2, false); err != nil {
+		t.Fatalf("failed to hide list: %v", err)
+	}
+	if share, err := repo.GetShareList(2); err != nil || share {
+		t.Errorf("expected list hidden, got %v (%v)", share, err)
+	}
+	ratings, _ = repo.GetFriendRatings(1, 10, 5)
+	if len(ratings) != 1 || ratings[0].UserID != 3 {
+		t.Errorf("expected only bob, got %+v", ratings)
+	}
+	if activity, _ := repo.GetFriendsActivity(1, 10); len(activity) != 1 {
+		t.Errorf("expected only bob's rating, got %+v", activity)
+	}
+
+	if err := repo.Unfollow(1, 3); err != nil {
+		t.Fatalf("failed to unfollow: %v", err)
+	}
+	if _, err := repo.DeleteUser(2, "test"); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	if following, _ := repo.GetFollowing(1); len(following) != 0 {
+		t.Errorf("expected no follows left, got %+v", following)
+	}
+}
+
+func TestSQLite_ChatWatchlist(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+	const chatID = int64(-100123)
+
+	for _, user := range []models.User{{ID: 1, Username: "alice"}, {ID: 2, Username: "bob"}} {
+		user.CreatedAt = now
+		if err := repo.CreateUser(user); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	alice, bob := int64(1), int64(2)
+	added, err := repo.AddToChatWatchlist(models.ChatWatchlistItem{ChatID: chatID, AnimeID: 10, Title: "Monster", AddedBy: &alice, AddedAt: now})
+	if err != nil || !added {
+		t.Fatalf("expected anime added, got %v (%v)", added, err)
+	}
+	added, err = repo.AddToChatWatchlist(models.ChatWatchlistItem{ChatID: chatID, AnimeID: 10, Title: "Monster", AddedBy: &bob, AddedAt: now})
+	if err != nil || added {
+		t.Fatalf("expected duplicate ignored, got %v (%v)", added, err)
+	}
+	if _, err := repo.AddToChatWatchlist(models.ChatWatchlistItem{ChatID: chatID, AnimeID: 11, Title: "Mushishi", AddedBy: &bob, AddedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("failed to add anime: %v", err)
+	}
+
+	item, err := repo.GetChatWatchlistItem(chatID, 10)
+	if err != nil || item.AddedByName != "alice" || item.AddedBy == nil || *item.AddedBy != alice {
+		t.Fatalf("expected alice's entry, got %+v (%v)", item, err)
+	}
+	if _, err := repo.GetChatWatchlistItem(-1, 10); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound for another chat, got %v", err)
+	}
+
+	// the entry stays on the list after its author deletes their data
+	if _, err := repo.DeleteUser(2, "test"); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	items, err := repo.GetChatWatchlist(chatID)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("expected 2 entries, got %+v (%v)", items, err)
+	}
+	if items[1].AnimeID != 11 || items[1].AddedBy != nil || items[1].AddedByName != "" {
+		t.Errorf("expected anonymous entry, got %+v", items[1])
+	}
+
+	if err := repo.MoveChatWatchlist(chatID, -100999); err != nil {
+		t.Fatalf("failed to move watchlist: %v", err)
+	}
+	if items, _ := repo.GetChatWatchlist(-100999); len(items) != 2 {
+		t.Errorf("expected entries under the new chat id, got %+v", items)
+	}
+
+	if err := repo.RemoveFromChatWatchlist(-100999, 10); err != nil {
+		t.Fatalf("failed to remove: %v", err)
+	}
+	if items, _ := repo.GetChatWatchlist(-100999); len(items) != 1 {
+		t.Errorf("expected one entry left, got %+v", items)
+	}
+}
+
+func TestSQLite_VoteSessions(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+	const chatID = int64(-100123)
+
+	for _, user := range []models.User{{ID: 1, Username: "alice"}, {ID: 2, Username: "bob"}, {ID: 3, Username: "carol"}} {
+		user.CreatedAt = now
+		if err := repo.CreateUser(user); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		if err := repo.AddFavorite(models.Favorite{UserID: user.ID, AnimeID: 10, Title: "Monster", AddedAt: now}); err != nil {
+			t.Fatalf("failed to add favorite: %v", err)
+		}
+	}
+	if err := repo.AddFavorite(models.Favorite{UserID: 1, AnimeID: 11, Title: "Mushishi", AddedAt: now}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	if err := repo.AddFavorite(models.Favorite{UserID: 3, AnimeID: 12, Title: "Hidden", AddedAt: now}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	if err := repo.SetShareList(3, false); err != nil {
+		t.Fatalf("failed to hide list: %v", err)
+	}
+
+	for _, id := range []int64{1, 2, 3} {
+		if err := repo.TouchChatMember(chatID, id); err != nil {
+			t.Fatalf("failed to touch member: %v", err)
+		}
+	}
+	if err := repo.TouchChatMember(chatID, 1); err != nil {
+		t.Fatalf("expected touching twice to succeed, got %v", err)
+	}
+
+	favorites, err := repo.GetMemberFavorites(chatID, 10)
+	if err != nil || len(favorites) != 2 || favorites[0].AnimeID != 10 || favorites[1].AnimeID != 11 {
+		t.Fatalf("expected shared favorites by popularity without hidden lists, got %+v (%v)", favorites, err)
+	}
+	if favorites, _ := repo.GetMemberFavorites(-1, 10); len(favorites) != 0 {
+		t.Errorf("expected no favorites for another chat, got %+v", favorites)
+	}
+
+	alice := int64(1)
+	session := models.VoteSession{
+		PollID:    "poll-1",
+		ChatID:    chatID,
+		MessageID: 100,
+		StartedBy: &alice,
+		CreatedAt: now,
+		Options:   []models.VoteOption{{AnimeID: 11, Title: "Mushishi"}, {AnimeID: 10, Title: "Monster"}},
+	}
+	if err := repo.CreateVoteSession(session); err != nil {
+		t.Fatalf("failed to create vote session: %v", err)
+	}
+
+	open, err := repo.GetOpenVoteSession(chatID)
+	if err != nil || open.PollID != "poll-1" || len(open.Options) != 2 || open.Options[0].AnimeID != 11 {
+		t.Fatalf("expected the open session with ordered options, got %+v (%v)", open, err)
+	}
+
+	winner := 10
+	closed, err := repo.CloseVoteSession("poll-1", &winner)
+	if err != nil || !closed {
+		t.Fatalf("expected the session closed, got %v (%v)", closed, err)
+	}
+	if closed, _ := repo.CloseVoteSession("poll-1", nil); closed {
+		t.Error("expected the second close to be a no-op")
+	}
+
+	if _, err := repo.GetOpenVoteSession(chatID); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound with no open session, got %v", err)
+	}
+	stored, err := repo.GetVoteSession("poll-1")
+	if err != nil || stored.ClosedAt == nil || stored.WinnerAnimeID == nil || *stored.WinnerAnimeID != 10 {
+		t.Errorf("expected the winner stored, got %+v (%v)", stored, err)
+	}
+}
+
+func TestSQLite_UserSettings(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	now := time.Now()
+	year := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.Local)
+
+	if _, err := repo.GetUserSettings(1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound without settings, got %v", err)
+	}
+	if err := repo.CreateUser(models.User{ID: 1, Username: "u", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := repo.AddRating(models.Rating{UserID: 1, AnimeID: 10, Score: 9, RatedAt: now}); err != nil {
+		t.Fatalf("failed to add rating: %v", err)
+	}
+
+	// the language alone creates the row with default settings
+	if err := repo.SetUserLanguage(1, "en"); err != nil {
+		t.Fatalf("failed to set language: %v", err)
+	}
+	settings, err := repo.GetUserSettings(1)
+	if err != nil || settings.Language != "en" || settings.TitleDisplay != models.TitleDisplayAuto || !settings.NotifyWrapped || settings.PageSize != 10 {
+		t.Fatalf("unexpected settings: %+v (%v)", settings, err)
+	}
+
+	settings.Language = ""
+	settings.TimeZone = "Europe/Berlin"
+	settings.NotifyWrapped = false
+	if err := repo.SaveUserSettings(*settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+	if lang, err := repo.GetUserLanguage(1); err != nil || lang != "" {
+		t.Errorf("expected the language cleared, got %q (%v)", lang, err)
+	}
+	if stored, _ := repo.GetUserSettings(1); stored == nil || *stored != *settings {
+		t.Errorf("expected %+v, got %+v", settings, stored)
+	}
+
+	recipients, err := repo.GetWrappedRecipients(now.Year(), year, year.AddDate(1, 0, 0))
+	if err != nil || len(recipients) != 0 {
+		t.Errorf("expected opted out user skipped, got %v (%v)", recipients, err)
+	}
+}
+
+func TestSQLite_PosterFiles(t *testing.T) {
+	repo := newSQLiteRepo(t)
+
+	if _, err := repo.GetPosterFile(10, "original"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	file := models.PosterFile{AnimeID: 10, Size: "original", Path: "/a.jpg", FileID: "file-a"}
+	if err := repo.SavePosterFile(file); err != nil {
+		t.Fatalf("failed to save poster file: %v", err)
+	}
+	file.Path, file.FileID = "/b.jpg", "file-b"
+	if err := repo.SavePosterFile(file); err != nil {
+		t.Fatalf("failed to replace poster file: %v", err)
+	}
+	if stored, err := repo.GetPosterFile(10, "original"); err != nil || *stored != file {
+		t.Errorf("expected %+v, got %+v (%v)", file, stored, err)
+	}
+
+	if err := repo.DeletePosterFile(10, "original"); err != nil {
+		t.Fatalf("failed to delete poster file: %v", err)
+	}
+	if _, err := repo.GetPosterFile(10, "original"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the poster file gone, got %v", err)
+	}
+}
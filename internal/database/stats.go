@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// StatsQuery limits statistics to titles added or rated within [From, To);
+// a zero bound leaves that side open
+type StatsQuery struct {
+	From time.Time
+	To   time.Time
+}
+
+// rangeFilter appends the StatsQuery bounds on column to where and args
+// localTime moves a bound into the server's zone: TIMESTAMP columns keep only
+// the wall clock, and rows are written with the server's local time
+func localTime(t time.Time) time.Time {
+	return t.In(time.Local)
+}
+
+func rangeFilter(where string, column string, q StatsQuery, args []interface{}) (string, []interface{}) {
+	if !q.From.IsZero() {
+		args = append(args, localTime(q.From))
+		where += fmt.Sprintf(" AND %s >= $%d", column, len(args))
+	}
+	if !q.To.IsZero() {
+		args = append(args, localTime(q.To))
+		where += fmt.Sprintf(" AND %s < $%d", column, len(args))
+	}
+	return where, args
+}
+
+// statsTitles selects the ids of every title the user favorited or rated within the range
+func statsTitles(userID int64, q StatsQuery) (string, []interface{}) {
+	favoritesWhere, args := rangeFilter("user_id = $1", "added_at", q, []interface{}{userID})
+	// both halves share the same bound placeholders
+	ratingsWhere, _ := rangeFilter("user_id = $1", "rated_at", q, []interface{}{userID})
+
+	ids := `
+		SELECT anime_id FROM favorites WHERE ` + favoritesWhere + `
+		UNION
+		SELECT anime_id FROM ratings WHERE ` + ratingsWhere
+	return ids, args
+}
+
+func (r *Repository) GetStatsEntries(userID int64, q StatsQuery) ([]models.StatsEntry, error) {
+	return r.GetStatsEntriesContext(context.Background(), userID, q)
+}
+
+func (r *Repository) GetStatsEntriesContext(ctx context.Context, userID int64, q StatsQuery) ([]models.StatsEntry, error) {
+	ids, args := statsTitles(userID, q)
+
+	var entries []models.StatsEntry
+	query := `
+		SELECT ids.anime_id,
+			COALESCE(a.kind, '') AS kind,
+			COALESCE(a.episodes, 0) AS episodes,
+			COALESCE(a.duration, 0) AS duration,
+			(f.anime_id IS NOT NULL) AS favorite,
+			r.score,
+			(a.id IS NOT NULL) AS cataloged
+		FROM (` + ids + `) ids
+		LEFT JOIN favorites f ON f.user_id = $1 AND f.anime_id = ids.anime_id
+		LEFT JOIN ratings r ON r.user_id = $1 AND r.anime_id = ids.anime_id
+		LEFT JOIN animes a ON a.id = ids.anime_id
+		ORDER BY ids.anime_id
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &entries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *Repository) CountStatsGenres(userID int64, q StatsQuery, limit int) ([]models.GenreCount, error) {
+	return r.CountStatsGenresContext(context.Background(), userID, q, limit)
+}
+
+func (r *Repository) CountStatsGenresContext(ctx context.Context, userID int64, q StatsQuery, limit int) ([]models.GenreCount, error) {
+	ids, args := statsTitles(userID, q)
+	args = append(args, limit)
+
+	var counts []models.GenreCount
+	query := fmt.Sprintf(`
+		SELECT g.id AS genre_id, g.name, g.russian, COUNT(*) AS count
+		FROM (%s) ids
+		JOIN anime_genres ag ON ag.anime_id = ids.anime_id
+		JOIN genres g ON g.id = ag.genre_id
+		GROUP BY g.id, g.name, g.russian
+		ORDER BY count DESC, g.name
+		LIMIT $%d
+	`, ids, len(args))
+
+	err := sqlx.SelectContext(ctx, r.ext(), &counts, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count genres: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetStatsEntries_Range(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+
+	rows := sqlmock.NewRows([]string{"anime_id", "kind", "episodes", "duration", "favorite", "score"}).
+		AddRow(1, "tv", 12, 24, true, 8).
+		AddRow(2, "movie", 1, 120, false, nil)
+	mock.ExpectQuery(regexp.QuoteMeta(`AND added_at >= $2 AND added_at < $3`)).
+		WithArgs(int64(1), from, to).
+		WillReturnRows(rows)
+
+	entries, err := repo.GetStatsEntries(1, StatsQuery{From: from, To: to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if !entries[0].Favorite || entries[0].Score == nil || *entries[0].Score != 8 {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Favorite || entries[1].Score != nil {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestCountStatsGenres_OpenRange(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	rows := sqlmock.NewRows([]string{"genre_id", "name", "russian", "count"}).
+		AddRow(1, "Action", "Экшен", 3)
+	mock.ExpectQuery(regexp.QuoteMeta(`LIMIT $2`)).
+		WithArgs(int64(1), 8).
+		WillReturnRows(rows)
+
+	genres, err := repo.CountStatsGenres(1, StatsQuery{}, 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(genres) != 1 || genres[0].Count != 3 {
+		t.Errorf("unexpected genres: %+v", genres)
+	}
+}
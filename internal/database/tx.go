@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ext returns the transaction when the repository is bound to one
+func (r *Repository) ext() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db.DB
+}
+
+func (r *Repository) inTx(ctx context.Context, fn func(tx *Repository) error) error {
+	// nested units of work join the outer transaction
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&Repository{db: r.db, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) WithTx(ctx context.Context, fn func(tx Repo) error) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		return fn(tx)
+	})
+}
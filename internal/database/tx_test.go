@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestWithTx_Commit(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO favorites`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO ratings`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.WithTx(ctx, func(tx Repo) error {
+		if err := tx.AddFavoriteContext(ctx, models.Favorite{UserID: 123, AnimeID: 1, Title: "Death Note", AddedAt: now}); err != nil {
+			return err
+		}
+		return tx.AddRatingContext(ctx, models.Rating{UserID: 123, AnimeID: 1, Score: 9, RatedAt: now})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWithTx_RollbackOnError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+	failure := errors.New("boom")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO favorites`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	err := repo.WithTx(ctx, func(tx Repo) error {
+		if err := tx.AddFavoriteContext(ctx, models.Favorite{UserID: 123, AnimeID: 1, Title: "Death Note", AddedAt: now}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWithTx_NestedJoinsOuterTransaction(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	// a single Begin/Commit pair even though DeleteUser opens its own unit of work
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM favorites`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO audit_log`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.WithTx(ctx, func(tx Repo) error {
+		if err := tx.CreateUserContext(ctx, models.User{ID: 123, Username: "test", CreatedAt: now}); err != nil {
+			return err
+		}
+		_, err := tx.DeleteUserContext(ctx, 123, "test")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetRating_DatabaseError(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM ratings WHERE user_id = $1 AND anime_id = $2`)).
+		WillReturnError(errors.New("connection reset"))
+
+	rating, err := repo.GetRating(123, 1)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected database error, got %v", err)
+	}
+	if rating != nil {
+		t.Errorf("expected nil rating, got %+v", rating)
+	}
+}
+
+func TestSQLite_WithTxRollsBack(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	repo.CreateUser(models.User{ID: 123, Username: "test", CreatedAt: now})
+
+	err := repo.WithTx(ctx, func(tx Repo) error {
+		if err := tx.AddFavoriteContext(ctx, models.Favorite{UserID: 123, AnimeID: 1, Title: "Death Note", AddedAt: now}); err != nil {
+			return err
+		}
+		// score out of range violates the CHECK constraint
+		return tx.AddRatingContext(ctx, models.Rating{UserID: 123, AnimeID: 1, Score: 42, RatedAt: now})
+	})
+	if err == nil {
+		t.Fatal("expected constraint error")
+	}
+
+	isFav, err := repo.IsFavorite(123, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isFav {
+		t.Error("expected favorite to be rolled back")
+	}
+}
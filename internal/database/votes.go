@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const voteSessionColumns = `poll_id, chat_id, message_id, started_by, created_at, closed_at, winner_anime_id`
+
+func (r *Repository) TouchChatMember(chatID int64, userID int64) error {
+	return r.TouchChatMemberContext(context.Background(), chatID, userID)
+}
+
+func (r *Repository) TouchChatMemberContext(ctx context.Context, chatID int64, userID int64) error {
+	query := `
+		INSERT INTO chat_members (chat_id, user_id, last_seen_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, user_id) DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
+	`
+
+	if _, err := r.ext().ExecContext(ctx, query, chatID, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to save chat member: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetMemberFavorites(chatID int64, limit int) ([]models.VoteOption, error) {
+	return r.GetMemberFavoritesContext(context.Background(), chatID, limit)
+}
+
+// GetMemberFavoritesContext returns titles favorited by members of a chat who
+// share their list, those liked by the most members first
+func (r *Repository) GetMemberFavoritesContext(ctx context.Context, chatID int64, limit int) ([]models.VoteOption, error) {
+	var options []models.VoteOption
+	query := `
+		SELECT f.anime_id, MAX(COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title)) AS title
+		FROM favorites f
+		JOIN chat_members m ON m.user_id = f.user_id AND m.chat_id = $1
+		JOIN users u ON u.id = f.user_id
+		LEFT JOIN animes a ON a.id = f.anime_id
+		WHERE u.share_list
+		GROUP BY f.anime_id
+		ORDER BY COUNT(*) DESC, f.anime_id
+		LIMIT $2
+	`
+
+	if err := sqlx.SelectContext(ctx, r.ext(), &options, query, chatID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get member favorites: %w", err)
+	}
+	return options, nil
+}
+
+func (r *Repository) CreateVoteSession(session models.VoteSession) error {
+	return r.CreateVoteSessionContext(context.Background(), session)
+}
+
+func (r *Repository) CreateVoteSessionContext(ctx context.Context, session models.VoteSession) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		query := `
+			INSERT INTO vote_sessions (poll_id, chat_id, message_id, started_by, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		_, err := tx.ext().ExecContext(ctx, query, session.PollID, session.ChatID, session.MessageID, session.StartedBy, session.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create vote session: %w", err)
+		}
+
+		optionQuery := `
+			INSERT INTO vote_options (poll_id, position, anime_id, title)
+			VALUES ($1, $2, $3, $4)
+		`
+		for i, option := range session.Options {
+			if _, err := tx.ext().ExecContext(ctx, optionQuery, session.PollID, i, option.AnimeID, option.Title); err != nil {
+				return fmt.Errorf("failed to save vote option: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) GetVoteSession(pollID string) (*models.VoteSession, error) {
+	return r.GetVoteSessionContext(context.Background(), pollID)
+}
+
+func (r *Repository) GetVoteSessionContext(ctx context.Context, pollID string) (*models.VoteSession, error) {
+	query := `SELECT ` + voteSessionColumns + ` FROM vote_sessions WHERE poll_id = $1`
+	return r.getVoteSession(ctx, query, pollID)
+}
+
+func (r *Repository) GetOpenVoteSession(chatID int64) (*models.VoteSession, error) {
+	return r.GetOpenVoteSessionContext(context.Background(), chatID)
+}
+
+func (r *Repository) GetOpenVoteSessionContext(ctx context.Context, chatID int64) (*models.VoteSession, error) {
+	query := `
+		SELECT ` + voteSessionColumns + ` FROM vote_sessions
+		WHERE chat_id = $1 AND closed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	return r.getVoteSession(ctx, query, chatID)
+}
+
+func (r *Repository) getVoteSession(ctx context.Context, query string, args ...interface{}) (*models.VoteSession, error) {
+	var session models.VoteSession
+	err := sqlx.GetContext(ctx, r.ext(), &session, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote session: %w", err)
+	}
+
+	optionsQuery := `SELECT anime_id, title FROM vote_options WHERE poll_id = $1 ORDER BY position`
+	if err := sqlx.SelectContext(ctx, r.ext(), &session.Options, optionsQuery, session.PollID); err != nil {
+		return nil, fmt.Errorf("failed to get vote options: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *Repository) CloseVoteSession(pollID string, winnerAnimeID *int) (bool, error) {
+	return r.CloseVoteSessionContext(context.Background(), pollID, winnerAnimeID)
+}
+
+// CloseVoteSessionContext reports whether this call closed the session, so the
+// stop button and the closed-poll update never both announce a winner
+func (r *Repository) CloseVoteSessionContext(ctx context.Context, pollID string, winnerAnimeID *int) (bool, error) {
+	query := `
+		UPDATE vote_sessions SET closed_at = $2, winner_anime_id = $3
+		WHERE poll_id = $1 AND closed_at IS NULL
+	`
+
+	res, err := r.ext().ExecContext(ctx, query, pollID, time.Now(), winnerAnimeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to close vote session: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to close vote session: %w", err)
+	}
+	return affected > 0, nil
+}
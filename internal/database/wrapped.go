@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func (r *Repository) GetActivity(userID int64, q StatsQuery) ([]models.ActivityEvent, error) {
+	return r.GetActivityContext(context.Background(), userID, q)
+}
+
+// GetActivityContext returns favorites added and ratings given within the range,
+// ordered by time; favorite events carry no score
+func (r *Repository) GetActivityContext(ctx context.Context, userID int64, q StatsQuery) ([]models.ActivityEvent, error) {
+	var events []models.ActivityEvent
+
+	favoritesWhere, args := rangeFilter("f.user_id = $1", "f.added_at", q, []interface{}{userID})
+	favoritesQuery := `
+		SELECT f.anime_id,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), f.title) AS title,
+			COALESCE(a.name, '') AS name,
+			COALESCE(a.episodes, 0) AS episodes,
+			COALESCE(a.duration, 0) AS duration,
+			NULL AS score,
+			f.added_at AS at
+		FROM favorites f
+		LEFT JOIN animes a ON a.id = f.anime_id
+		WHERE ` + favoritesWhere + `
+		ORDER BY f.added_at, f.anime_id
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &events, favoritesQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get favorites activity: %w", err)
+	}
+
+	var ratings []models.ActivityEvent
+	ratingsWhere, args := rangeFilter("r.user_id = $1", "r.rated_at", q, []interface{}{userID})
+	ratingsQuery := `
+		SELECT r.anime_id,
+			COALESCE(NULLIF(a.russian, ''), NULLIF(a.name, ''), '') AS title,
+			COALESCE(a.name, '') AS name,
+			COALESCE(a.episodes, 0) AS episodes,
+			COALESCE(a.duration, 0) AS duration,
+			r.score,
+			r.rated_at AS at
+		FROM ratings r
+		LEFT JOIN animes a ON a.id = r.anime_id
+		WHERE ` + ratingsWhere + `
+		ORDER BY r.rated_at, r.anime_id
+	`
+	if err := sqlx.SelectContext(ctx, r.ext(), &ratings, ratingsQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get ratings activity: %w", err)
+	}
+
+	return append(events, ratings...), nil
+}
+
+func (r *Repository) GetWrappedRecipients(year int, from, to time.Time) ([]int64, error) {
+	return r.GetWrappedRecipientsContext(context.Background(), year, from, to)
+}
+
+// GetWrappedRecipientsContext lists users with any activity in [from, to) who
+// haven't been sent the recap for year yet and didn't turn it off in /settings
+func (r *Repository) GetWrappedRecipientsContext(ctx context.Context, year int, from, to time.Time) ([]int64, error) {
+	var userIDs []int64
+	query := `
+		SELECT u.id FROM users u
+		WHERE NOT EXISTS (
+			SELECT 1 FROM wrapped_deliveries d WHERE d.user_id = u.id AND d.year = $1
+		) AND NOT EXISTS (
+			SELECT 1 FROM user_settings s WHERE s.user_id = u.id AND NOT s.notify_wrapped
+		) AND (
+			EXISTS (SELECT 1 FROM favorites f WHERE f.user_id = u.id AND f.added_at >= $2 AND f.added_at < $3)
+			OR EXISTS (SELECT 1 FROM ratings r WHERE r.user_id = u.id AND r.rated_at >= $2 AND r.rated_at < $3)
+		)
+		ORDER BY u.id
+	`
+
+	err := sqlx.SelectContext(ctx, r.ext(), &userIDs, query, year, localTime(from), localTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wrapped recipients: %w", err)
+	}
+	return userIDs, nil
+}
+
+func (r *Repository) MarkWrappedSent(userID int64, year int) error {
+	return r.MarkWrappedSentContext(context.Background(), userID, year)
+}
+
+func (r *Repository) MarkWrappedSentContext(ctx context.Context, userID int64, year int) error {
+	query := `
+		INSERT INTO wrapped_deliveries (user_id, year, sent_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, year) DO NOTHING
+	`
+
+	_, err := r.ext().ExecContext(ctx, query, userID, year, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark wrapped as sent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetWrappedRecipients(t *testing.T) {
+	repo, mock := newTestRepo(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(1, 0, 0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM wrapped_deliveries d WHERE d.user_id = u.id AND d.year = $1`)).
+		WithArgs(2025, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	userIDs, err := repo.GetWrappedRecipients(2025, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(userIDs) != 2 || userIDs[0] != 1 {
+		t.Errorf("unexpected recipients: %v", userIDs)
+	}
+}
+
+func TestMarkWrappedSent(t *testing.T) {
+	repo, mock := newTestRepo(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (user_id, year) DO NOTHING`)).
+		WithArgs(int64(1), 2025, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.MarkWrappedSent(1, 2025); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
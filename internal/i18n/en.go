@@ -0,0 +1,347 @@
+package i18n
+
+var en = catalog{
+	messages: map[string]string{
+		"menu.search":    "🔍 Search",
+		"menu.favorites": "❤️ Favorites",
+		"menu.help":      "ℹ️ Help",
+		"menu.cancel":    "Cancel",
+		"menu.fallback":  "Use the menu buttons or commands",
+
+		"start.greeting": "Hi! I help you find and keep track of anime.",
+		"help.title":     "ℹ️ Help:",
+		"help.buttons":   "🔍 Search - find anime by title\n❤️ Favorites - the anime you saved",
+		"help.commands": "Commands:\n" +
+			"/search <title> - search anime\n" +
+			"/favorites - your favorites\n" +
+			"/collections - your collections\n" +
+			"/ratings - your ratings\n" +
+			"/community_top - top rated by bot users\n" +
+			"/recommend - picks for your taste\n" +
+			"/stats [month|year|2024|2024-01-01..2024-06-30] - your stats\n" +
+			"/wrapped [year] - your year in review\n" +
+			"/friends - friends and their ratings\n" +
+			"/follow <code> - follow a friend\n" +
+			"/export - export your list (JSON, CSV, MAL XML)\n" +
+			"/import - import a list from MAL or Shikimori\n" +
+			"/language - bot language\n" +
+			"/settings - preferences\n" +
+			"/deleteme - delete all your data",
+
+		"language.prompt":  "🌐 Bot language. Current: %s",
+		"language.auto":    "🌐 Same as Telegram",
+		"language.saved":   "Language saved",
+		"language.name.ru": "Русский",
+		"language.name.en": "English",
+
+		"settings.title":          "⚙️ Settings",
+		"settings.language":       "🌐 Language: %s",
+		"settings.titles":         "🔤 Titles: %s",
+		"settings.titles.auto":    "by language",
+		"settings.titles.both":    "both",
+		"settings.titles.romaji":  "romaji",
+		"settings.titles.russian": "Russian",
+		"settings.time_zone":      "🕒 Time zone: %s",
+		"settings.zone_server":    "same as the bot",
+		"settings.nsfw":           "🔞 Hide 18+: %s",
+		"settings.notify_follows": "🔔 New followers: %s",
+		"settings.notify_wrapped": "🎁 Year in review: %s",
+		"settings.spoilers":       "🙈 Hide descriptions: %s",
+		"settings.page_size":      "📄 Results per page: %d",
+		"settings.on":             "on",
+		"settings.off":            "off",
+		"settings.pick_zone":      "🕒 Pick a time zone. Any other one can be set with /settings tz Europe/Paris",
+		"settings.unknown_zone":   "Unknown time zone. Use an IANA name such as Europe/Paris",
+		"settings.zone_saved":     "🕒 Time zone: %s",
+		"button.back_to_settings": "⬅️ Back to settings",
+
+		"search.prompt":      "Type an anime title to search for:",
+		"search.cancelled":   "Search cancelled.",
+		"search.empty_query": "Add a title. For example: /search bebop",
+
+		"error.generic":     "Error: %v",
+		"error.short":       "Error",
+		"error.save":        "Failed to save",
+		"cancelled":         "Cancelled",
+		"anime.not_found":   "Anime not found",
+		"anime.load_failed": "Failed to load anime details",
+		"anime.number":      "Anime #%d",
+
+		"note.prompt":         "Write a note for this anime (up to 1000 characters).\nSend «-» to delete the note.",
+		"note.current":        "Current note:\n%s\n\n",
+		"note.unchanged":      "Note unchanged.",
+		"note.deleted":        "🗑️ Note deleted",
+		"note.delete_failed":  "Failed to delete the note",
+		"note.saved":          "📝 Note saved",
+		"rating.choose":       "Pick a score from 1 to 10",
+		"rating.saved":        "✅ Rated %d",
+		"rating.save_failed":  "Failed to save the rating",
+		"favorites.added":     "✅ Added to favorites",
+		"favorites.removed":   "💔 Removed from favorites",
+		"favorites.add_error": "Failed to add",
+		"favorites.rm_error":  "Failed to remove",
+
+		"card.type":              "📺 Type: %s",
+		"card.genres":            "🎭 Genres: %s",
+		"card.score":             "⭐ Score: %s",
+		"card.shikimori_score":   "⭐ Score: %s",
+		"card.status":            "📊 Status: %s",
+		"card.episodes":          "📺 Episodes: %d",
+		"card.description":       "Description: ",
+		"card.none":              "none",
+		"card.your_rating":       "⭐ Your rating: %d",
+		"card.in_favorites":      "💚 In favorites",
+		"card.note":              "📝 Note: ",
+		"card.community":         "👥 Bot users' rating: %.1f (%d)",
+		"card.friends":           "👥 Friends: ",
+		"card.also_liked":        "👥 People who liked this also liked: ",
+		"card.rating_history":    "📈 Rating history: ",
+		"card.image_unavailable": "⚠️ Image unavailable",
+		"card.spoiler_hidden":    "🙈 hidden, see /settings",
+
+		"button.add_favorite":       "❤️ Add",
+		"button.remove_favorite":    "💔 Remove",
+		"button.delete_favorite":    "🗑️ Remove from favorites",
+		"button.rate":               "⭐ Rate",
+		"button.rated":              "⭐ Rated: %d",
+		"button.note":               "📝 Note",
+		"button.to_collection":      "📁 To collection",
+		"button.share":              "📤 Share",
+		"button.share_list":         "📤 Share list",
+		"button.share_collection":   "📤 Share collection",
+		"button.send_to_friend":     "📤 Send to a friend",
+		"button.cancel":             "❌ Cancel",
+		"button.cancel_plain":       "Cancel",
+		"button.close":              "✖️ Close",
+		"button.page":               "Page %d/%d",
+		"button.search":             "🔍 Search",
+		"button.search_clear":       "✖️ Clear search",
+		"button.back_to_list":       "⬅️ Back to list",
+		"button.new_collection":     "➕ New collection",
+		"button.rename":             "✏️ Rename",
+		"button.delete":             "🗑️ Delete",
+		"button.delete_confirm":     "🗑️ Yes, delete",
+		"button.to_collections":     "⬅️ To collections",
+		"button.remove_from_col":    "🗑️ Remove from collection",
+		"button.back_to_collection": "⬅️ Back to collection",
+		"button.ratings_by_date":    "🕒 All by date",
+		"button.ratings_by_score":   "🏆 All by score",
+		"button.to_ratings":         "⬅️ To ratings",
+		"button.stats_all":          "All time",
+		"button.stats_year":         "Year",
+		"button.stats_month":        "Month",
+		"button.wrapped_next":       "Next ➡️",
+		"button.wrapped_image":      "🖼 Picture for friends",
+		"button.open_numbered":      "%d. Open",
+		"button.hide_list":          "🔒 Hide my list",
+		"button.show_list":          "👀 Show my list",
+		"button.unfollow":           "❌ Unfollow %s",
+		"button.add_to_chat":        "➕ To chat list",
+		"button.chat_list":          "📋 Chat list",
+		"button.close_vote":         "🏁 Close the vote",
+		"button.delete_everything":  "🗑 Yes, delete everything",
+
+		"sort.date":    "By date",
+		"sort.title":   "By title",
+		"sort.rating":  "By my rating",
+		"sort.score":   "By Shikimori score",
+		"sort.changed": "Sorted %s",
+
+		"error.delete": "Failed to delete",
+
+		"favorites.empty":         "Your favorites are empty. Add anime from the search!",
+		"favorites.title":         "❤️ Your favorites (%d):\n\nPick an anime to open:",
+		"favorites.search_none":   "🔍 Nothing in your favorites matches “%s”",
+		"favorites.search_found":  "🔍 Favorites matching “%s” (%d):",
+		"favorites.load_failed":   "Failed to load your favorites",
+		"favorites.searching":     "🔍 Searching your favorites...",
+		"favorites.search_prompt": "Type part of a title to search your favorites:",
+
+		"ratings.empty":       "You haven't rated anything yet. Use the “%s” button on an anime card.",
+		"ratings.title":       "⭐ Your ratings (%d):\n\nPick a score to see its titles:",
+		"ratings.score_title": "⭐ Rated %d (%d):",
+		"ratings.all_title":   "⭐ All ratings (%d):",
+		"ratings.load_failed": "Failed to load your ratings",
+
+		"collections.load_failed":        "Failed to load your collections",
+		"collections.empty":              "You have no collections yet. Create the first one!",
+		"collections.title":              "📁 Your collections (%d):",
+		"collections.items":              "📁 %s (%d):\n\nPick an anime:",
+		"collections.items_empty":        "📁 %s\n\nThe collection is empty. Add anime with the “%s” button on a card.",
+		"collections.not_found":          "Collection not found",
+		"collections.pick":               "Pick a collection:",
+		"collections.name_prompt":        "Type a name for the new collection:",
+		"collections.rename_prompt":      "Type a new name for the collection:",
+		"collections.renamed":            "✏️ Collection renamed",
+		"collections.created":            "📁 Collection “%s” created",
+		"collections.created_with_anime": "📁 Collection “%s” created with the anime in it",
+		"collections.exists":             "You already have a collection with this name",
+		"collections.deleted":            "🗑️ Collection deleted",
+		"collections.delete_confirm":     "Delete the collection? Its anime stay in your favorites and other collections.",
+		"collections.item_added":         "✅ Added to the collection",
+		"collections.item_removed":       "Removed from the collection",
+
+		"community.empty":       "No title has %s from bot users yet. Rate anime on their cards!",
+		"community.title":       "🏆 Top rated by bot users (%s or more):",
+		"community.load_failed": "Failed to load the top",
+
+		"account.delete_confirm":   "⚠️ Deleting your account\n\nYour favorites, ratings and their history, notes and collections will be deleted. This can't be undone.\n\nTo keep your list, run /export first.",
+		"account.delete_cancelled": "Deletion cancelled. Your data is still here.",
+		"account.delete_failed":    "Failed to delete, try again later",
+		"account.deleted":          "🗑 All your data is deleted. If you message the bot again, it starts from scratch.",
+
+		"export.pick_format":    "📦 Pick an export format.\n\nMAL XML can be imported into MyAnimeList and Shikimori.",
+		"export.failed":         "Failed to export your list",
+		"export.empty":          "Nothing to export yet: add an anime to favorites or rate one.",
+		"export.caption":        "📦 Your anime list",
+		"export.unknown_format": "Unknown format",
+		"export.preparing":      "Preparing the file...",
+
+		"list.more": "…and %d more",
+
+		"share.open_failed":       "Couldn't open the anime from the link",
+		"share.list_empty":        "Nothing here yet.",
+		"share.list_hint":         "Tap a title to open its card.",
+		"share.link_expired":      "The link is outdated: this list is gone.",
+		"share.list_hidden":       "🔒 The owner has hidden their list.",
+		"share.list_failed":       "Failed to load the list",
+		"share.favorites_header":  "❤️ %s's favorites",
+		"share.collection_header": "📁 “%s” by %s",
+		"share.forward_hint":      "Forward the link to a friend - the bot will open what you shared right away.",
+		"share.anime_link":        "📤 Link to “%s”:",
+		"share.link_failed":       "Failed to create the link",
+		"share.favorites_link":    "📤 Link to your favorites:",
+		"share.favorites_text":    "My favorite anime",
+		"share.collection_link":   "📤 Link to the collection “%s”:",
+		"share.collection_text":   "Anime collection “%s”",
+		"share.hidden_notice":     "🔒 Your list is hidden in /friends, nobody will see it by the link until you open it.",
+
+		"stats.usage":          "I didn't get the period. Examples:\n/stats — all time\n/stats year — the last year\n/stats month — the last month\n/stats 2024 or /stats 2024-03\n/stats 2024-01-01..2024-06-30",
+		"stats.period.all":     "for all time",
+		"stats.period.year":    "for the last year",
+		"stats.period.month":   "for the last month",
+		"stats.period.custom":  "for %s",
+		"stats.title":          "📊 Your stats %s",
+		"stats.average":        "Average score: %.2f",
+		"stats.hours":          "Watched about %.1f h",
+		"stats.kinds":          "Kinds: %s",
+		"stats.genres":         "Genres: %s",
+		"stats.kind.tv":        "TV",
+		"stats.kind.movie":     "movies",
+		"stats.kind.ova":       "OVA",
+		"stats.kind.ona":       "ONA",
+		"stats.kind.special":   "specials",
+		"stats.kind.music":     "music videos",
+		"stats.kind.unknown":   "no kind",
+		"stats.failed":         "Failed to count your stats",
+		"stats.empty":          "Nothing to count %s yet: add an anime to favorites or rate one.",
+		"stats.unknown_period": "Unknown period",
+		"stats.counting":       "Counting...",
+
+		"wrapped.summary":       "🎉 Your %d in anime\n\nAdded to favorites: %d\nRated: %d",
+		"wrapped.top":           "🏆 Best of %d:",
+		"wrapped.genre":         "🎭 Genre of the year: %s\n\n%s",
+		"wrapped.month":         "📅 Most active month: %s\n\nAdditions and ratings: %d",
+		"wrapped.longest":       "⏳ The longest title you watched:\n\n%s — about %.1f h",
+		"wrapped.year_prompt":   "Give a year, for example: /wrapped 2025",
+		"wrapped.failed":        "Failed to prepare your year in review",
+		"wrapped.empty":         "You have no favorites or ratings in %d, nothing to sum up yet.",
+		"wrapped.image_caption": "🎉 My %d in anime",
+		"wrapped.drawing":       "Drawing the picture...",
+		"wrapped.unavailable":   "The recap is unavailable",
+
+		"month.1":  "January",
+		"month.2":  "February",
+		"month.3":  "March",
+		"month.4":  "April",
+		"month.5":  "May",
+		"month.6":  "June",
+		"month.7":  "July",
+		"month.8":  "August",
+		"month.9":  "September",
+		"month.10": "October",
+		"month.11": "November",
+		"month.12": "December",
+
+		"recommend.reason.rated":    "because you rated “%s” %d",
+		"recommend.reason.favorite": "because “%s” is in your favorites",
+		"recommend.reason.liked":    "because you liked “%s”",
+		"recommend.reason.genre":    "%s, like “%s” (%s)",
+		"recommend.thanks":          "Thanks for the feedback! Send /recommend to get a new selection.",
+		"recommend.title":           "🎯 Recommendations for you:",
+		"recommend.hint":            "👍/👎 help tune the next recommendations.",
+		"recommend.not_enough":      "Nothing to go on yet: rate a couple of titles or add them to favorites.",
+		"recommend.failed":          "Failed to pick recommendations",
+		"recommend.nothing_new":     "Found nothing new for your taste. Try later or rate a few more titles.",
+		"recommend.feedback_failed": "Failed to save the feedback",
+		"recommend.liked":           "Noted: more like this 👍",
+		"recommend.disliked":        "Noted: less like this 👎",
+
+		"friends.title":             "👥 Friends\n\nYour code: %s\nLink for friends: %s\nA friend can open the link or send /follow %s",
+		"friends.following_none":    "You don't follow anyone yet.",
+		"friends.following":         "You follow: %s",
+		"friends.activity":          "Recently from friends:",
+		"friends.activity_none":     "Nothing new from friends yet, or their lists are hidden.",
+		"friends.activity.favorite": "• %s %s added “%s” to favorites",
+		"friends.activity.rated":    "• %s %s rated “%s” %d",
+		"friends.shared":            "👀 Followers see your favorites and ratings.",
+		"friends.hidden":            "🔒 Your list is hidden from followers.",
+		"friends.load_failed":       "Failed to load friends",
+		"friends.follow_prompt":     "Give your friend's code. For example: /follow abcd2345\nYour own code is in /friends",
+		"friends.unknown_code":      "I don't know this code. Check that your friend sent all of it.",
+		"friends.own_code":          "That's your own code 🙂 Send it to your friends.",
+		"friends.follow_failed":     "Failed to follow, try again later",
+		"friends.followed":          "✅ You follow %s. Their ratings will show up on anime cards and in /friends.",
+		"friends.new_follower":      "👥 %s now sees your favorites and ratings. You can hide your list in /friends.",
+		"friends.list_opened":       "The list is open to followers",
+		"friends.list_closed":       "The list is hidden",
+		"friends.unfollow_failed":   "Failed to unfollow",
+		"friends.unfollowed":        "Unfollowed",
+
+		"import.help":               "📥 List import\n\nSend me an export file as a document:\n• MyAnimeList or Shikimori XML (.xml.gz works too)\n• a Shikimori JSON export\n• a JSON file from /export\n\nDropped titles won't go to favorites, but their scores are kept. If a score in the bot differs from the file, the bot's score stays.",
+		"import.too_large":          "The file is too large: 5 MB at most",
+		"import.download_failed":    "Couldn't download the file, try again",
+		"import.too_large_unpacked": "The file is too large: over 50 MB unpacked",
+		"import.failed":             "Import failed: couldn't parse the file",
+		"import.done":               "📥 Import finished",
+		"import.conflicts_note":     "On conflicts the score already in the bot was kept.",
+
+		"group.help":             "👋 I keep this chat's shared anime list.\n\n/search <title> - find an anime and add it to the chat list\n/watchlist - the shared list and who added what\n/vote [N] - a “what do we watch tonight” poll from the chat list and members' favorites\n\nFavorites, ratings and stats are personal - they live in a private chat with the bot: @%s",
+		"group.private_only":     "This command works in a private chat with the bot: @%s",
+		"group.search_prompt":    "Put a title after the command. For example: /search bebop",
+		"group.results_expired":  "The search results are outdated, send /search again",
+		"group.in_watchlist":     "📋 In the chat list",
+		"group.in_watchlist_by":  "📋 In the chat list, added by %s",
+		"group.watchlist_empty":  "📋 The chat list is empty. Find an anime with /search and press “%s”.",
+		"group.watchlist_title":  "📋 Chat list (%d):",
+		"group.watchlist_failed": "Failed to load the chat list",
+		"group.add_failed":       "Failed to add",
+		"group.already_added":    "Already in the chat list",
+		"group.added":            "📋 %s added “%s” to the chat list",
+		"group.added_short":      "Added to the chat list",
+		"group.not_in_watchlist": "This anime is no longer in the list",
+		"group.remove_forbidden": "Only the member who added it or a chat admin can remove it",
+		"group.removed":          "Removed from the chat list",
+
+		"vote.question":        "What do we watch tonight? 🍿",
+		"vote.no_votes":        "🤷 Nobody voted. Run /vote again when everyone is here.",
+		"vote.winner":          "🏆 “%s” wins - %s!",
+		"vote.in_progress":     "A vote is already running. Close it with the button under the poll.",
+		"vote.not_enough":      "Nothing to choose from 🙃 Add a couple of anime with /search or to favorites in a private chat with the bot.",
+		"vote.start_failed":    "Failed to start the vote",
+		"vote.finished":        "The vote is already over",
+		"vote.close_forbidden": "Only the member who started the vote or a chat admin can close it",
+		"vote.stop_failed":     "Couldn't stop the poll",
+	},
+	plurals: map[string]plural{
+
+		"community.min_votes":  {One: "%d rating", Many: "%d ratings"},
+		"stats.titles":         {One: "%d title (favorites: %d, rated: %d)", Many: "%d titles (favorites: %d, rated: %d)"},
+		"stats.uncataloged":    {One: "%d title has no shikimori data yet, its hours and kind aren't counted", Many: "%d titles have no shikimori data yet, their hours and kind aren't counted"},
+		"wrapped.genre_titles": {One: "%d title in this genre", Many: "%d titles in this genre"},
+		"import.imported":      {One: "✅ Added %d title", Many: "✅ Added %d titles"},
+		"import.skipped":       {One: "⏭ Skipped %d title", Many: "⏭ Skipped %d titles"},
+		"import.conflicts":     {One: "⚠️ %d conflict", Many: "⚠️ %d conflicts"},
+		"votes":                {One: "%d vote", Many: "%d votes"},
+	},
+}
@@ -0,0 +1,144 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+
+	// Default is used for group chats, background jobs and untranslated keys
+	Default = Russian
+)
+
+var Supported = []Lang{Russian, English}
+
+// plural holds the forms a counted message takes; English only uses One and Many
+type plural struct {
+	One  string
+	Few  string
+	Many string
+}
+
+type catalog struct {
+	messages map[string]string
+	plurals  map[string]plural
+}
+
+var catalogs = map[Lang]catalog{
+	Russian: ru,
+	English: en,
+}
+
+// Parse accepts a stored language override
+func Parse(code string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(code)))
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// FromTelegram picks a locale from a user's Telegram language_code
+// ("ru", "en-US", ...). Readers of the other CIS languages mostly read
+// Russian, everyone else gets English
+func FromTelegram(code string) Lang {
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	switch base {
+	case "":
+		return Default
+	case "ru", "uk", "be", "kk", "uz", "ky", "tg", "hy", "az":
+		return Russian
+	default:
+		return English
+	}
+}
+
+// pluralForm follows the CLDR cardinal rules for the supported languages
+func pluralForm(lang Lang, p plural, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	if lang != Russian {
+		if n == 1 {
+			return p.One
+		}
+		return p.Many
+	}
+
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return p.One
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return p.Few
+	default:
+		return p.Many
+	}
+}
+
+type Localizer struct {
+	lang Lang
+}
+
+func For(lang Lang) Localizer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = Default
+	}
+	return Localizer{lang: lang}
+}
+
+func (l Localizer) Lang() Lang {
+	if l.lang == "" {
+		return Default
+	}
+	return l.lang
+}
+
+// T formats a message; keys missing from a catalog fall back to the default
+// language and then to the key itself, so a gap shows up instead of breaking
+func (l Localizer) T(key string, args ...interface{}) string {
+	text, ok := catalogs[l.Lang()].messages[key]
+	if !ok {
+		if text, ok = catalogs[Default].messages[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// N formats a counted message, choosing the plural form for n. n itself is
+// not passed to the format unless it is among args
+func (l Localizer) N(key string, n int, args ...interface{}) string {
+	lang := l.Lang()
+	p, ok := catalogs[lang].plurals[key]
+	if !ok {
+		lang = Default
+		if p, ok = catalogs[Default].plurals[key]; !ok {
+			return key
+		}
+	}
+	text := pluralForm(lang, p, n)
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Match reports which of keys has text as its translation in any language.
+// Reply keyboards send their label back as plain text, and a user may still
+// have a keyboard drawn before they switched language
+func Match(text string, keys ...string) (string, bool) {
+	for _, key := range keys {
+		for _, lang := range Supported {
+			if catalogs[lang].messages[key] == text {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatch(t *testing.T) {
+	for _, lang := range Supported {
+		for key, text := range catalogs[Default].messages {
+			translated, ok := catalogs[lang].messages[key]
+			if !ok {
+				t.Errorf("%s: missing %q", lang, key)
+				continue
+			}
+			want, got := verbPattern.FindAllString(text, -1), verbPattern.FindAllString(translated, -1)
+			if len(want) != len(got) {
+				t.Errorf("%s: %q has verbs %v, expected %v", lang, key, got, want)
+			}
+		}
+		for key := range catalogs[lang].messages {
+			if _, ok := catalogs[Default].messages[key]; !ok {
+				t.Errorf("%s: %q is not in the default catalog", lang, key)
+			}
+		}
+		for key := range catalogs[Default].plurals {
+			if _, ok := catalogs[lang].plurals[key]; !ok {
+				t.Errorf("%s: missing plural %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	ru, en := For(Russian), For(English)
+	tests := []struct {
+		n      int
+		ru, en string
+	}{
+		{1, "1 голос", "1 vote"},
+		{2, "2 голоса", "2 votes"},
+		{5, "5 голосов", "5 votes"},
+		{11, "11 голосов", "11 votes"},
+		{12, "12 голосов", "12 votes"},
+		{21, "21 голос", "21 votes"},
+		{22, "22 голоса", "22 votes"},
+		{0, "0 голосов", "0 votes"},
+	}
+
+	for _, tt := range tests {
+		if got := ru.N("votes", tt.n, tt.n); got != tt.ru {
+			t.Errorf("ru %d: expected %q, got %q", tt.n, tt.ru, got)
+		}
+		if got := en.N("votes", tt.n, tt.n); got != tt.en {
+			t.Errorf("en %d: expected %q, got %q", tt.n, tt.en, got)
+		}
+	}
+}
+
+func TestFromTelegram(t *testing.T) {
+	tests := map[string]Lang{
+		"":      Default,
+		"ru":    Russian,
+		"uk":    Russian,
+		"en":    English,
+		"en-US": English,
+		"de":    English,
+		"RU":    Russian,
+	}
+	for code, want := range tests {
+		if got := FromTelegram(code); got != want {
+			t.Errorf("%q: expected %s, got %s", code, want, got)
+		}
+	}
+}
+
+func TestLocalizerFallbacks(t *testing.T) {
+	if got := For("xx").Lang(); got != Default {
+		t.Errorf("expected unknown languages to fall back to %s, got %s", Default, got)
+	}
+	if got := (Localizer{}).T("menu.search"); got != "Поиск" {
+		t.Errorf("expected the zero localizer to use the default catalog, got %q", got)
+	}
+	if got := For(English).T("no.such.key"); got != "no.such.key" {
+		t.Errorf("expected the key for a missing message, got %q", got)
+	}
+	if got := For(English).T("button.rated", 7); got != "⭐ Rated: 7" {
+		t.Errorf("unexpected formatted message: %q", got)
+	}
+	if _, ok := Parse(" EN "); !ok {
+		t.Error("expected a stored override to parse")
+	}
+	if _, ok := Parse("auto"); ok {
+		t.Error("expected unknown overrides to be rejected")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, text := range []string{"Поиск", "🔍 Search"} {
+		if key, ok := Match(text, "menu.search", "menu.favorites"); !ok || key != "menu.search" {
+			t.Errorf("%q: expected menu.search, got %q", text, key)
+		}
+	}
+	if _, ok := Match("поиск", "menu.search"); ok {
+		t.Error("expected free text not to match a button")
+	}
+}
@@ -0,0 +1,348 @@
+package i18n
+
+var ru = catalog{
+	messages: map[string]string{
+		// reply keyboard; labels come back as plain text, see Match
+		"menu.search":    "Поиск",
+		"menu.favorites": "Избранное",
+		"menu.help":      "Помощь",
+		"menu.cancel":    "Отмена",
+		"menu.fallback":  "Используй кнопки меню или команды",
+
+		"start.greeting": "Привет! Я бот для поиска аниме.",
+		"help.title":     "ℹ️ Справка:",
+		"help.buttons":   "Поиск - найти аниме по названию\nИзбранное - список сохраненных аниме",
+		"help.commands": "Команды:\n" +
+			"/search <название> - поиск аниме\n" +
+			"/favorites - твое избранное\n" +
+			"/collections - твои коллекции\n" +
+			"/ratings - твои оценки\n" +
+			"/community_top - топ по оценкам пользователей бота\n" +
+			"/recommend - что посмотреть под твой вкус\n" +
+			"/stats [month|year|2024|2024-01-01..2024-06-30] - твоя статистика\n" +
+			"/wrapped [год] - итоги года\n" +
+			"/friends - друзья и их оценки\n" +
+			"/follow <код> - подписаться на друга\n" +
+			"/export - выгрузить список (JSON, CSV, MAL XML)\n" +
+			"/import - загрузить список из MAL или Shikimori\n" +
+			"/language - язык бота\n" +
+			"/settings - настройки\n" +
+			"/deleteme - удалить все свои данные",
+
+		"language.prompt":  "🌐 Язык бота. Сейчас: %s",
+		"language.auto":    "🌐 Как в Telegram",
+		"language.saved":   "Язык сохранен",
+		"language.name.ru": "Русский",
+		"language.name.en": "English",
+
+		"settings.title":          "⚙️ Настройки",
+		"settings.language":       "🌐 Язык: %s",
+		"settings.titles":         "🔤 Названия: %s",
+		"settings.titles.auto":    "по языку",
+		"settings.titles.both":    "оба",
+		"settings.titles.romaji":  "ромадзи",
+		"settings.titles.russian": "русские",
+		"settings.time_zone":      "🕒 Часовой пояс: %s",
+		"settings.zone_server":    "как у бота",
+		"settings.nsfw":           "🔞 Скрывать 18+: %s",
+		"settings.notify_follows": "🔔 О новых подписчиках: %s",
+		"settings.notify_wrapped": "🎁 Итоги года: %s",
+		"settings.spoilers":       "🙈 Скрывать описания: %s",
+		"settings.page_size":      "📄 Результатов на страницу: %d",
+		"settings.on":             "да",
+		"settings.off":            "нет",
+		"settings.pick_zone":      "🕒 Выбери часовой пояс. Другой можно указать командой /settings tz Europe/Paris",
+		"settings.unknown_zone":   "Не знаю такого часового пояса. Нужно название из базы IANA, например Europe/Paris",
+		"settings.zone_saved":     "🕒 Часовой пояс: %s",
+		"button.back_to_settings": "⬅️ К настройкам",
+
+		"search.prompt":      "Напиши название аниме для поиска:",
+		"search.cancelled":   "Поиск отменен.",
+		"search.empty_query": "Укажи название. Например: /search bebop",
+
+		"error.generic":     "Ошибка: %v",
+		"error.short":       "Ошибка",
+		"error.save":        "Ошибка сохранения",
+		"cancelled":         "Отменено",
+		"anime.not_found":   "Аниме не найдено",
+		"anime.load_failed": "Ошибка загрузки данных аниме",
+		"anime.number":      "Аниме #%d",
+
+		"note.prompt":         "Напиши заметку к аниме (до 1000 символов).\nЧтобы удалить заметку, отправь «-».",
+		"note.current":        "Текущая заметка:\n%s\n\n",
+		"note.unchanged":      "Заметка не изменена.",
+		"note.deleted":        "🗑️ Заметка удалена",
+		"note.delete_failed":  "Ошибка при удалении заметки",
+		"note.saved":          "📝 Заметка сохранена",
+		"rating.choose":       "Выбери оценку от 1 до 10",
+		"rating.saved":        "✅ Оценка %d сохранена",
+		"rating.save_failed":  "Ошибка при сохранении оценки",
+		"favorites.added":     "✅ Добавлено в избранное",
+		"favorites.removed":   "💔 Удалено из избранного",
+		"favorites.add_error": "Ошибка добавления",
+		"favorites.rm_error":  "Ошибка удаления",
+
+		"card.type":              "📺 Тип: %s",
+		"card.genres":            "🎭 Жанр: %s",
+		"card.score":             "⭐ Оценка: %s",
+		"card.shikimori_score":   "⭐ Общая оценка: %s",
+		"card.status":            "📊 Статус: %s",
+		"card.episodes":          "📺 Эпизодов: %d",
+		"card.description":       "Описание: ",
+		"card.none":              "нет",
+		"card.your_rating":       "⭐ Твоя оценка: %d",
+		"card.in_favorites":      "💚 В избранном",
+		"card.note":              "📝 Заметка: ",
+		"card.community":         "👥 Оценка пользователей бота: %.1f (%d)",
+		"card.friends":           "👥 Друзья: ",
+		"card.also_liked":        "👥 Кому понравилось это, также понравились: ",
+		"card.rating_history":    "📈 История оценок: ",
+		"card.image_unavailable": "⚠️ Изображение недоступно",
+		"card.spoiler_hidden":    "🙈 скрыто, показать можно в /settings",
+
+		"button.add_favorite":       "❤️ Добавить",
+		"button.remove_favorite":    "💔 Удалить",
+		"button.delete_favorite":    "🗑️ Удалить из избранного",
+		"button.rate":               "⭐ Оценить",
+		"button.rated":              "⭐ Оценка: %d",
+		"button.note":               "📝 Заметка",
+		"button.to_collection":      "📁 В коллекцию",
+		"button.share":              "📤 Поделиться",
+		"button.share_list":         "📤 Поделиться списком",
+		"button.share_collection":   "📤 Поделиться коллекцией",
+		"button.send_to_friend":     "📤 Отправить другу",
+		"button.cancel":             "❌ Отмена",
+		"button.cancel_plain":       "Отмена",
+		"button.close":              "✖️ Закрыть",
+		"button.page":               "Стр. %d/%d",
+		"button.search":             "🔍 Поиск",
+		"button.search_clear":       "✖️ Сбросить поиск",
+		"button.back_to_list":       "⬅️ Назад к списку",
+		"button.new_collection":     "➕ Новая коллекция",
+		"button.rename":             "✏️ Переименовать",
+		"button.delete":             "🗑️ Удалить",
+		"button.delete_confirm":     "🗑️ Да, удалить",
+		"button.to_collections":     "⬅️ К коллекциям",
+		"button.remove_from_col":    "🗑️ Убрать из коллекции",
+		"button.back_to_collection": "⬅️ Назад к коллекции",
+		"button.ratings_by_date":    "🕒 Все по дате",
+		"button.ratings_by_score":   "🏆 Все по оценке",
+		"button.to_ratings":         "⬅️ К оценкам",
+		"button.stats_all":          "Всё время",
+		"button.stats_year":         "Год",
+		"button.stats_month":        "Месяц",
+		"button.wrapped_next":       "Дальше ➡️",
+		"button.wrapped_image":      "🖼 Картинка для друзей",
+		"button.open_numbered":      "%d. Открыть",
+		"button.hide_list":          "🔒 Скрыть мой список",
+		"button.show_list":          "👀 Показывать мой список",
+		"button.unfollow":           "❌ Отписаться от %s",
+		"button.add_to_chat":        "➕ В список чата",
+		"button.chat_list":          "📋 Список чата",
+		"button.close_vote":         "🏁 Подвести итоги",
+		"button.delete_everything":  "🗑 Да, удалить всё",
+
+		"sort.date":    "По дате",
+		"sort.title":   "По названию",
+		"sort.rating":  "По моей оценке",
+		"sort.score":   "По оценке Shikimori",
+		"sort.changed": "Сортировка: %s",
+
+		"error.delete": "Ошибка удаления",
+
+		"favorites.empty":         "Твое избранное пусто. Добавь аниме через поиск!",
+		"favorites.title":         "❤️ Твое избранное (%d):\n\nВыбери аниме для просмотра:",
+		"favorites.search_none":   "🔍 В избранном ничего не нашлось по запросу «%s»",
+		"favorites.search_found":  "🔍 Найдено в избранном по запросу «%s» (%d):",
+		"favorites.load_failed":   "Ошибка получения избранного",
+		"favorites.searching":     "🔍 Ищу в избранном...",
+		"favorites.search_prompt": "Напиши часть названия для поиска в избранном:",
+
+		"ratings.empty":       "Ты пока ничего не оценил. Оценки ставятся кнопкой «%s» на карточке аниме.",
+		"ratings.title":       "⭐ Твои оценки (%d):\n\nВыбери оценку, чтобы посмотреть тайтлы:",
+		"ratings.score_title": "⭐ Оценка %d (%d):",
+		"ratings.all_title":   "⭐ Все оценки (%d):",
+		"ratings.load_failed": "Ошибка получения оценок",
+
+		"collections.load_failed":        "Ошибка получения коллекций",
+		"collections.empty":              "У тебя пока нет коллекций. Создай первую!",
+		"collections.title":              "📁 Твои коллекции (%d):",
+		"collections.items":              "📁 %s (%d):\n\nВыбери аниме:",
+		"collections.items_empty":        "📁 %s\n\nКоллекция пуста. Добавляй аниме кнопкой «%s» на карточке.",
+		"collections.not_found":          "Коллекция не найдена",
+		"collections.pick":               "Выбери коллекцию:",
+		"collections.name_prompt":        "Напиши название новой коллекции:",
+		"collections.rename_prompt":      "Напиши новое название коллекции:",
+		"collections.renamed":            "✏️ Коллекция переименована",
+		"collections.created":            "📁 Коллекция «%s» создана",
+		"collections.created_with_anime": "📁 Коллекция «%s» создана и аниме добавлено в неё",
+		"collections.exists":             "Коллекция с таким названием уже есть",
+		"collections.deleted":            "🗑️ Коллекция удалена",
+		"collections.delete_confirm":     "Удалить коллекцию? Аниме из неё останутся в избранном и других коллекциях.",
+		"collections.item_added":         "✅ Добавлено в коллекцию",
+		"collections.item_removed":       "Убрано из коллекции",
+
+		"community.empty":       "Пока ни у одного тайтла нет %s от пользователей бота. Ставь оценки на карточках аниме!",
+		"community.title":       "🏆 Топ по оценкам пользователей бота (от %s):",
+		"community.load_failed": "Ошибка получения топа",
+
+		"account.delete_confirm":   "⚠️ Удаление аккаунта\n\nБудут удалены избранное, оценки и их история, заметки и коллекции. Отменить это нельзя.\n\nЕсли хочешь сохранить список, сначала сделай /export.",
+		"account.delete_cancelled": "Удаление отменено. Твои данные на месте.",
+		"account.delete_failed":    "Ошибка удаления, попробуй позже",
+		"account.deleted":          "🗑 Все твои данные удалены. Если напишешь боту снова, он начнет с чистого листа.",
+
+		"export.pick_format":    "📦 Выбери формат выгрузки.\n\nMAL XML подходит для импорта в MyAnimeList и Shikimori.",
+		"export.failed":         "Ошибка выгрузки списка",
+		"export.empty":          "Выгружать пока нечего: добавь аниме в избранное или поставь оценку.",
+		"export.caption":        "📦 Твой список аниме",
+		"export.unknown_format": "Неизвестный формат",
+		"export.preparing":      "Готовлю файл...",
+
+		"list.more": "…и еще %d",
+
+		"share.open_failed":       "Не удалось открыть аниме по ссылке",
+		"share.list_empty":        "Здесь пока пусто.",
+		"share.list_hint":         "Нажми на название, чтобы открыть карточку.",
+		"share.link_expired":      "Ссылка устарела: такого списка больше нет.",
+		"share.list_hidden":       "🔒 Владелец скрыл свой список.",
+		"share.list_failed":       "Ошибка загрузки списка",
+		"share.favorites_header":  "❤️ Избранное %s",
+		"share.collection_header": "📁 «%s» от %s",
+		"share.forward_hint":      "Перешли ссылку другу - бот сразу откроет то, чем ты поделился.",
+		"share.anime_link":        "📤 Ссылка на «%s»:",
+		"share.link_failed":       "Ошибка создания ссылки",
+		"share.favorites_link":    "📤 Ссылка на твое избранное:",
+		"share.favorites_text":    "Мое избранное аниме",
+		"share.collection_link":   "📤 Ссылка на коллекцию «%s»:",
+		"share.collection_text":   "Коллекция аниме «%s»",
+		"share.hidden_notice":     "🔒 Твой список скрыт в /friends, по ссылке его не увидят, пока ты его не откроешь.",
+
+		"stats.usage":          "Не понял период. Примеры:\n/stats — за всё время\n/stats year — за последний год\n/stats month — за последний месяц\n/stats 2024 или /stats 2024-03\n/stats 2024-01-01..2024-06-30",
+		"stats.period.all":     "за всё время",
+		"stats.period.year":    "за последний год",
+		"stats.period.month":   "за последний месяц",
+		"stats.period.custom":  "за %s",
+		"stats.title":          "📊 Твоя статистика %s",
+		"stats.average":        "Средняя оценка: %.2f",
+		"stats.hours":          "Просмотрено примерно: %.1f ч",
+		"stats.kinds":          "Типы: %s",
+		"stats.genres":         "Жанры: %s",
+		"stats.kind.tv":        "TV",
+		"stats.kind.movie":     "фильмы",
+		"stats.kind.ova":       "OVA",
+		"stats.kind.ona":       "ONA",
+		"stats.kind.special":   "спешлы",
+		"stats.kind.music":     "клипы",
+		"stats.kind.unknown":   "без типа",
+		"stats.failed":         "Ошибка подсчета статистики",
+		"stats.empty":          "Считать пока нечего %s: добавь аниме в избранное или поставь оценку.",
+		"stats.unknown_period": "Неизвестный период",
+		"stats.counting":       "Считаю...",
+
+		"wrapped.summary":       "🎉 Твой %d год в аниме\n\nДобавлено в избранное: %d\nОценено: %d",
+		"wrapped.top":           "🏆 Лучшее за %d год:",
+		"wrapped.genre":         "🎭 Любимый жанр года: %s\n\n%s",
+		"wrapped.month":         "📅 Самый активный месяц: %s\n\nДобавлений и оценок: %d",
+		"wrapped.longest":       "⏳ Самый длинный просмотренный тайтл:\n\n%s — примерно %.1f ч",
+		"wrapped.year_prompt":   "Укажи год, например: /wrapped 2025",
+		"wrapped.failed":        "Ошибка подготовки итогов года",
+		"wrapped.empty":         "За %d год у тебя нет ни избранного, ни оценок — подводить пока нечего.",
+		"wrapped.image_caption": "🎉 Мой %d год в аниме",
+		"wrapped.drawing":       "Рисую картинку...",
+		"wrapped.unavailable":   "Итоги недоступны",
+
+		"month.1":  "январь",
+		"month.2":  "февраль",
+		"month.3":  "март",
+		"month.4":  "апрель",
+		"month.5":  "май",
+		"month.6":  "июнь",
+		"month.7":  "июль",
+		"month.8":  "август",
+		"month.9":  "сентябрь",
+		"month.10": "октябрь",
+		"month.11": "ноябрь",
+		"month.12": "декабрь",
+
+		"recommend.reason.rated":    "потому что ты оценил «%s» на %d",
+		"recommend.reason.favorite": "потому что «%s» у тебя в избранном",
+		"recommend.reason.liked":    "потому что тебе понравилось «%s»",
+		"recommend.reason.genre":    "жанр «%s», как у «%s» (%s)",
+		"recommend.thanks":          "Спасибо за отзывы! Набери /recommend, чтобы получить новую подборку.",
+		"recommend.title":           "🎯 Рекомендации для тебя:",
+		"recommend.hint":            "👍/👎 помогают точнее подбирать следующие рекомендации.",
+		"recommend.not_enough":      "Пока не из чего подбирать: оцени пару тайтлов или добавь их в избранное.",
+		"recommend.failed":          "Ошибка подбора рекомендаций",
+		"recommend.nothing_new":     "Не нашел ничего нового под твой вкус. Попробуй позже или оцени еще несколько тайтлов.",
+		"recommend.feedback_failed": "Ошибка сохранения отзыва",
+		"recommend.liked":           "Учту: больше похожего 👍",
+		"recommend.disliked":        "Учту: меньше такого 👎",
+
+		"friends.title":             "👥 Друзья\n\nТвой код: %s\nСсылка для друзей: %s\nДруг может открыть ссылку или отправить /follow %s",
+		"friends.following_none":    "Ты пока ни на кого не подписан.",
+		"friends.following":         "Ты подписан: %s",
+		"friends.activity":          "Недавно у друзей:",
+		"friends.activity_none":     "У друзей пока ничего нового или их списки скрыты.",
+		"friends.activity.favorite": "• %s %s добавил(а) «%s» в избранное",
+		"friends.activity.rated":    "• %s %s оценил(а) «%s» на %d",
+		"friends.shared":            "👀 Подписчики видят твое избранное и оценки.",
+		"friends.hidden":            "🔒 Твой список скрыт от подписчиков.",
+		"friends.load_failed":       "Ошибка загрузки друзей",
+		"friends.follow_prompt":     "Укажи код друга. Например: /follow abcd2345\nСвой код можно найти в /friends",
+		"friends.unknown_code":      "Не знаю такого кода. Проверь, что друг прислал его целиком.",
+		"friends.own_code":          "Это твой собственный код 🙂 Отправь его друзьям.",
+		"friends.follow_failed":     "Ошибка подписки, попробуй позже",
+		"friends.followed":          "✅ Ты подписан на %s. Оценки друга появятся на карточках аниме и в /friends.",
+		"friends.new_follower":      "👥 %s теперь видит твое избранное и оценки. Скрыть список можно в /friends.",
+		"friends.list_opened":       "Список открыт для подписчиков",
+		"friends.list_closed":       "Список скрыт",
+		"friends.unfollow_failed":   "Ошибка отписки",
+		"friends.unfollowed":        "Ты отписался",
+
+		"import.help":               "📥 Импорт списка\n\nОтправь мне файл выгрузки как документ:\n• MyAnimeList или Shikimori XML (можно .xml.gz)\n• JSON-выгрузку Shikimori\n• JSON-файл из /export\n\nБрошенные тайтлы не попадут в избранное, но их оценки сохранятся. Если оценка в боте отличается от файла, останется оценка из бота.",
+		"import.too_large":          "Файл слишком большой: максимум 5 МБ",
+		"import.download_failed":    "Не удалось скачать файл, попробуй еще раз",
+		"import.too_large_unpacked": "Файл слишком большой: после распаковки больше 50 МБ",
+		"import.failed":             "Ошибка импорта: не удалось разобрать файл",
+		"import.done":               "📥 Импорт завершен",
+		"import.conflicts_note":     "При конфликте оставлена оценка, которая уже была в боте.",
+
+		"group.help":             "👋 Я веду общий список аниме этого чата.\n\n/search <название> - найти аниме и добавить его в список чата\n/watchlist - общий список и кто что добавил\n/vote [N] - опрос «что смотрим сегодня» из списка чата и избранного участников\n\nИзбранное, оценки и статистика личные - они в личке с ботом: @%s",
+		"group.private_only":     "Эта команда работает в личных сообщениях с ботом: @%s",
+		"group.search_prompt":    "Напиши название после команды. Например: /search bebop",
+		"group.results_expired":  "Результаты поиска устарели, набери /search заново",
+		"group.in_watchlist":     "📋 В списке чата",
+		"group.in_watchlist_by":  "📋 В списке чата, добавил(а) %s",
+		"group.watchlist_empty":  "📋 Список чата пуст. Найди аниме через /search и нажми «%s».",
+		"group.watchlist_title":  "📋 Список чата (%d):",
+		"group.watchlist_failed": "Ошибка загрузки списка чата",
+		"group.add_failed":       "Ошибка добавления",
+		"group.already_added":    "Уже в списке чата",
+		"group.added":            "📋 %s добавил(а) «%s» в список чата",
+		"group.added_short":      "Добавлено в список чата",
+		"group.not_in_watchlist": "Этого аниме уже нет в списке",
+		"group.remove_forbidden": "Убрать может тот, кто добавил, или админ чата",
+		"group.removed":          "Убрано из списка чата",
+
+		"vote.question":        "Что смотрим сегодня? 🍿",
+		"vote.no_votes":        "🤷 Никто не проголосовал. Запусти /vote еще раз, когда все соберутся.",
+		"vote.winner":          "🏆 Побеждает «%s» - %s!",
+		"vote.in_progress":     "Голосование уже идет. Подведите итоги кнопкой под опросом.",
+		"vote.not_enough":      "Не из чего выбирать 🙃 Добавьте пару аниме через /search или в избранное в личке с ботом.",
+		"vote.start_failed":    "Ошибка запуска голосования",
+		"vote.finished":        "Голосование уже закончено",
+		"vote.close_forbidden": "Подвести итоги может тот, кто начал голосование, или админ чата",
+		"vote.stop_failed":     "Не удалось остановить опрос",
+	},
+	plurals: map[string]plural{
+
+		"community.min_votes":  {One: "%d оценки", Few: "%d оценок", Many: "%d оценок"},
+		"stats.titles":         {One: "%d тайтл (в избранном %d, оценено %d)", Few: "%d тайтла (в избранном %d, оценено %d)", Many: "%d тайтлов (в избранном %d, оценено %d)"},
+		"stats.uncataloged":    {One: "%d тайтл без данных shikimori — его часы и тип пока не учтены", Few: "%d тайтла без данных shikimori — их часы и тип пока не учтены", Many: "%d тайтлов без данных shikimori — их часы и тип пока не учтены"},
+		"wrapped.genre_titles": {One: "%d тайтл в этом жанре", Few: "%d тайтла в этом жанре", Many: "%d тайтлов в этом жанре"},
+		"import.imported":      {One: "✅ Добавлен %d тайтл", Few: "✅ Добавлено %d тайтла", Many: "✅ Добавлено %d тайтлов"},
+		"import.skipped":       {One: "⏭ Пропущен %d тайтл", Few: "⏭ Пропущено %d тайтла", Many: "⏭ Пропущено %d тайтлов"},
+		"import.conflicts":     {One: "⚠️ %d конфликт", Few: "⚠️ %d конфликта", Many: "⚠️ %d конфликтов"},
+		"votes":                {One: "%d голос", Few: "%d голоса", Many: "%d голосов"},
+	},
+}
@@ -0,0 +1,244 @@
+package library
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatMAL  Format = "xml"
+)
+
+const jsonVersion = 1
+
+// MAL statuses used in exports; there are no watch statuses in the bot yet,
+// so rated titles count as completed and everything else as planned
+const (
+	malCompleted   = "Completed"
+	malPlanToWatch = "Plan to Watch"
+)
+
+type Entry struct {
+	AnimeID  int        `json:"anime_id"`
+	Title    string     `json:"title"`
+	Kind     string     `json:"kind,omitempty"`
+	Episodes int        `json:"episodes,omitempty"`
+	Favorite bool       `json:"favorite"`
+	AddedAt  *time.Time `json:"added_at,omitempty"`
+	Score    int        `json:"score,omitempty"`
+	RatedAt  *time.Time `json:"rated_at,omitempty"`
+	Note     string     `json:"note,omitempty"`
+}
+
+type Library struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Entries    []Entry   `json:"entries"`
+}
+
+func New(entries []models.LibraryEntry, exportedAt time.Time) Library {
+	lib := Library{
+		Version:    jsonVersion,
+		ExportedAt: exportedAt,
+		Entries:    make([]Entry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		entry := Entry{
+			AnimeID:  e.AnimeID,
+			Title:    e.Title,
+			Kind:     e.Kind,
+			Episodes: e.Episodes,
+			Favorite: e.AddedAt != nil,
+			AddedAt:  e.AddedAt,
+			RatedAt:  e.RatedAt,
+			Note:     e.Note,
+		}
+		if e.Score != nil {
+			entry.Score = *e.Score
+		}
+		lib.Entries = append(lib.Entries, entry)
+	}
+	return lib
+}
+
+func ParseFormat(s string) (Format, bool) {
+	switch Format(s) {
+	case FormatJSON, FormatCSV, FormatMAL:
+		return Format(s), true
+	case "mal":
+		return FormatMAL, true
+	}
+	return "", false
+}
+
+func FileName(format Format, exportedAt time.Time) string {
+	return fmt.Sprintf("anime-library-%s.%s", exportedAt.Format("2006-01-02"), format)
+}
+
+func Export(w io.Writer, lib Library, format Format) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, lib)
+	case FormatCSV:
+		return WriteCSV(w, lib)
+	case FormatMAL:
+		return WriteMAL(w, lib)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+func WriteJSON(w io.Writer, lib Library) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(lib); err != nil {
+		return fmt.Errorf("failed to encode json: %w", err)
+	}
+	return nil
+}
+
+var csvHeader = []string{"anime_id", "title", "kind", "episodes", "favorite", "added_at", "score", "rated_at", "note"}
+
+func WriteCSV(w io.Writer, lib Library) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	for _, e := range lib.Entries {
+		score := ""
+		if e.Score > 0 {
+			score = strconv.Itoa(e.Score)
+		}
+		record := []string{
+			strconv.Itoa(e.AnimeID),
+			e.Title,
+			e.Kind,
+			strconv.Itoa(e.Episodes),
+			strconv.FormatBool(e.Favorite),
+			formatTime(e.AddedAt),
+			score,
+			formatTime(e.RatedAt),
+			e.Note,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	Info    malInfo    `xml:"myinfo"`
+	Anime   []malAnime `xml:"anime"`
+}
+
+type malInfo struct {
+	UserExportType   int `xml:"user_export_type"`
+	TotalAnime       int `xml:"user_total_anime"`
+	TotalWatching    int `xml:"user_total_watching"`
+	TotalCompleted   int `xml:"user_total_completed"`
+	TotalOnHold      int `xml:"user_total_onhold"`
+	TotalDropped     int `xml:"user_total_dropped"`
+	TotalPlanToWatch int `xml:"user_total_plantowatch"`
+}
+
+type malAnime struct {
+	SeriesAnimeDBID   int    `xml:"series_animedb_id"`
+	SeriesTitle       cdata  `xml:"series_title"`
+	SeriesType        string `xml:"series_type"`
+	SeriesEpisodes    int    `xml:"series_episodes"`
+	MyWatchedEpisodes int    `xml:"my_watched_episodes"`
+	MyStartDate       string `xml:"my_start_date"`
+	MyFinishDate      string `xml:"my_finish_date"`
+	MyScore           int    `xml:"my_score"`
+	MyStatus          string `xml:"my_status"`
+	MyComments        cdata  `xml:"my_comments"`
+	MyTimesWatched    int    `xml:"my_times_watched"`
+	UpdateOnImport    int    `xml:"update_on_import"`
+}
+
+var malTypes = map[string]string{
+	"tv":         "TV",
+	"movie":      "Movie",
+	"ova":        "OVA",
+	"ona":        "ONA",
+	"special":    "Special",
+	"tv_special": "Special",
+	"music":      "Music",
+}
+
+func WriteMAL(w io.Writer, lib Library) error {
+	// shikimori ids match MAL ids, so both sites accept the file as is
+	export := malExport{Info: malInfo{UserExportType: 1}}
+
+	for _, e := range lib.Entries {
+		item := malAnime{
+			SeriesAnimeDBID: e.AnimeID,
+			SeriesTitle:     cdata{e.Title},
+			SeriesType:      "Unknown",
+			SeriesEpisodes:  e.Episodes,
+			MyStartDate:     "0000-00-00",
+			MyFinishDate:    "0000-00-00",
+			MyScore:         e.Score,
+			MyStatus:        malPlanToWatch,
+			MyComments:      cdata{e.Note},
+			UpdateOnImport:  1,
+		}
+		if t, ok := malTypes[e.Kind]; ok {
+			item.SeriesType = t
+		}
+		if e.Score > 0 {
+			item.MyStatus = malCompleted
+			item.MyWatchedEpisodes = e.Episodes
+			if e.RatedAt != nil {
+				item.MyFinishDate = e.RatedAt.Format("2006-01-02")
+			}
+			export.Info.TotalCompleted++
+		} else {
+			export.Info.TotalPlanToWatch++
+		}
+		export.Anime = append(export.Anime, item)
+	}
+	export.Info.TotalAnime = len(export.Anime)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write xml: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to encode xml: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write xml: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package library
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func testLibrary() Library {
+	added := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	rated := time.Date(2024, 4, 2, 18, 30, 0, 0, time.UTC)
+	score := 9
+
+	return New([]models.LibraryEntry{
+		{AnimeID: 1535, Title: "Тетрадь смерти", Kind: "tv", Episodes: 37, AddedAt: &added, Score: &score, RatedAt: &rated, Note: "пересмотреть, \"обязательно\""},
+		{AnimeID: 20, Title: "Наруто", Kind: "movie", Episodes: 1, AddedAt: &added},
+	}, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestNew_ConvertsEntries(t *testing.T) {
+	lib := testLibrary()
+
+	if len(lib.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(lib.Entries))
+	}
+	if !lib.Entries[0].Favorite || lib.Entries[0].Score != 9 {
+		t.Errorf("unexpected first entry: %+v", lib.Entries[0])
+	}
+	if lib.Entries[1].Score != 0 || lib.Entries[1].RatedAt != nil {
+		t.Errorf("expected unrated second entry, got %+v", lib.Entries[1])
+	}
+}
+
+func TestWriteJSON_RoundTrip(t *testing.T) {
+	lib := testLibrary()
+	var buf bytes.Buffer
+
+	if err := Export(&buf, lib, FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded Library
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if decoded.Version != jsonVersion || len(decoded.Entries) != 2 {
+		t.Fatalf("unexpected decoded library: %+v", decoded)
+	}
+	if decoded.Entries[0].Note != lib.Entries[0].Note {
+		t.Errorf("expected note %q, got %q", lib.Entries[0].Note, decoded.Entries[0].Note)
+	}
+}
+
+func TestWriteCSV_Records(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Export(&buf, testLibrary(), FormatCSV); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 records, got %d", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	if records[1][6] != "9" || records[1][7] != "2024-04-02T18:30:00Z" {
+		t.Errorf("unexpected rated record: %v", records[1])
+	}
+	if records[2][6] != "" || records[2][4] != "true" {
+		t.Errorf("unexpected favorite record: %v", records[2])
+	}
+}
+
+func TestWriteMAL_Format(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Export(&buf, testLibrary(), FormatMAL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<myanimelist>",
+		"<user_export_type>1</user_export_type>",
+		"<user_total_anime>2</user_total_anime>",
+		"<user_total_completed>1</user_total_completed>",
+		"<user_total_plantowatch>1</user_total_plantowatch>",
+		"<series_animedb_id>1535</series_animedb_id>",
+		"<series_title><![CDATA[Тетрадь смерти]]></series_title>",
+		"<series_type>TV</series_type>",
+		"<my_watched_episodes>37</my_watched_episodes>",
+		"<my_finish_date>2024-04-02</my_finish_date>",
+		"<my_score>9</my_score>",
+		"<my_status>Completed</my_status>",
+		"<series_type>Movie</series_type>",
+		"<my_status>Plan to Watch</my_status>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, testLibrary(), Format("yaml")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Format
+		ok    bool
+	}{
+		{"json", FormatJSON, true},
+		{"csv", FormatCSV, true},
+		{"xml", FormatMAL, true},
+		{"mal", FormatMAL, true},
+		{"pdf", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseFormat(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
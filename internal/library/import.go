@@ -0,0 +1,182 @@
+package library
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	minScore = 1
+	maxScore = 10
+)
+
+// MaxUnpackedSize caps what a gzipped export may expand to; a large MAL list
+// is a few megabytes, so anything bigger is a gzip bomb rather than a list
+const MaxUnpackedSize = 50 << 20
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrTooLarge      = errors.New("import file too large")
+)
+
+type Import struct {
+	Entries []Entry
+	Skipped int
+}
+
+// dropped titles are not kept in favorites, but their scores still count
+var droppedStatuses = map[string]bool{
+	"dropped": true,
+	"4":       true,
+}
+
+type shikimoriRate struct {
+	TargetID      int     `json:"target_id"`
+	TargetType    string  `json:"target_type"`
+	TargetTitle   string  `json:"target_title"`
+	TargetTitleRu *string `json:"target_title_ru"`
+	Score         int     `json:"score"`
+	Status        string  `json:"status"`
+	Episodes      int     `json:"episodes"`
+	Text          *string `json:"text"`
+}
+
+func ParseImport(data []byte) (*Import, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip: %w", err)
+		}
+		defer reader.Close()
+
+		data, err = io.ReadAll(io.LimitReader(reader, MaxUnpackedSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip: %w", err)
+		}
+		if len(data) > MaxUnpackedSize {
+			return nil, fmt.Errorf("unpacked file exceeds %d bytes: %w", MaxUnpackedSize, ErrTooLarge)
+		}
+	}
+
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\xef\xbb\xbf"))
+	if len(data) == 0 {
+		return nil, ErrUnknownFormat
+	}
+
+	switch data[0] {
+	case '<':
+		return parseMAL(data)
+	case '{':
+		return parseLibraryJSON(data)
+	case '[':
+		return parseShikimoriJSON(data)
+	}
+	return nil, ErrUnknownFormat
+}
+
+func parseMAL(data []byte) (*Import, error) {
+	if !bytes.Contains(data, []byte("<myanimelist")) {
+		return nil, ErrUnknownFormat
+	}
+
+	var export malExport
+	if err := xml.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse xml: %w", err)
+	}
+
+	result := newImportBuilder()
+	for _, item := range export.Anime {
+		result.add(Entry{
+			AnimeID:  item.SeriesAnimeDBID,
+			Title:    strings.TrimSpace(item.SeriesTitle.Value),
+			Episodes: item.SeriesEpisodes,
+			Favorite: !droppedStatuses[strings.ToLower(strings.TrimSpace(item.MyStatus))],
+			Score:    item.MyScore,
+			Note:     strings.TrimSpace(item.MyComments.Value),
+		})
+	}
+	return result.build(), nil
+}
+
+func parseLibraryJSON(data []byte) (*Import, error) {
+	var lib Library
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+	if lib.Version == 0 {
+		return nil, ErrUnknownFormat
+	}
+
+	result := newImportBuilder()
+	for _, entry := range lib.Entries {
+		result.add(entry)
+	}
+	return result.build(), nil
+}
+
+func parseShikimoriJSON(data []byte) (*Import, error) {
+	var rates []shikimoriRate
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+
+	result := newImportBuilder()
+	for _, rate := range rates {
+		// shikimori exports may mix anime and manga lists
+		if rate.TargetType != "" && rate.TargetType != "Anime" {
+			result.skipped++
+			continue
+		}
+
+		title := rate.TargetTitle
+		if rate.TargetTitleRu != nil && *rate.TargetTitleRu != "" {
+			title = *rate.TargetTitleRu
+		}
+		note := ""
+		if rate.Text != nil {
+			note = strings.TrimSpace(*rate.Text)
+		}
+
+		result.add(Entry{
+			AnimeID:  rate.TargetID,
+			Title:    title,
+			Favorite: !droppedStatuses[rate.Status],
+			Score:    rate.Score,
+			Note:     note,
+		})
+	}
+	return result.build(), nil
+}
+
+type importBuilder struct {
+	entries []Entry
+	seen    map[int]bool
+	skipped int
+}
+
+func newImportBuilder() *importBuilder {
+	return &importBuilder{seen: make(map[int]bool)}
+}
+
+func (b *importBuilder) add(entry Entry) {
+	if entry.Score < minScore || entry.Score > maxScore {
+		entry.Score = 0
+	}
+	if entry.AnimeID <= 0 || b.seen[entry.AnimeID] || (!entry.Favorite && entry.Score == 0) {
+		b.skipped++
+		return
+	}
+
+	b.seen[entry.AnimeID] = true
+	b.entries = append(b.entries, entry)
+}
+
+func (b *importBuilder) build() *Import {
+	return &Import{Entries: b.entries, Skipped: b.skipped}
+}
@@ -0,0 +1,169 @@
+package library
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+	"time"
+)
+
+const malSample = `<?xml version="1.0" encoding="UTF-8" ?>
+<myanimelist>
+  <myinfo><user_export_type>1</user_export_type></myinfo>
+  <anime>
+    <series_animedb_id>1535</series_animedb_id>
+    <series_title><![CDATA[Death Note]]></series_title>
+    <series_episodes>37</series_episodes>
+    <my_score>9</my_score>
+    <my_status>Completed</my_status>
+    <my_comments><![CDATA[classic]]></my_comments>
+  </anime>
+  <anime>
+    <series_animedb_id>20</series_animedb_id>
+    <series_title><![CDATA[Naruto]]></series_title>
+    <my_score>0</my_score>
+    <my_status>Plan to Watch</my_status>
+  </anime>
+  <anime>
+    <series_animedb_id>21</series_animedb_id>
+    <series_title><![CDATA[One Piece]]></series_title>
+    <my_score>0</my_score>
+    <my_status>Dropped</my_status>
+  </anime>
+  <anime>
+    <series_animedb_id>30</series_animedb_id>
+    <series_title><![CDATA[Evangelion]]></series_title>
+    <my_score>4</my_score>
+    <my_status>Dropped</my_status>
+  </anime>
+</myanimelist>`
+
+func TestParseImport_MAL(t *testing.T) {
+	parsed, err := ParseImport([]byte(malSample))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(parsed.Entries) != 3 || parsed.Skipped != 1 {
+		t.Fatalf("expected 3 entries and 1 skipped, got %d and %d", len(parsed.Entries), parsed.Skipped)
+	}
+
+	first := parsed.Entries[0]
+	if first.AnimeID != 1535 || first.Title != "Death Note" || first.Score != 9 || !first.Favorite || first.Note != "classic" {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if parsed.Entries[1].Score != 0 || !parsed.Entries[1].Favorite {
+		t.Errorf("expected planned favorite, got %+v", parsed.Entries[1])
+	}
+	if parsed.Entries[2].Favorite || parsed.Entries[2].Score != 4 {
+		t.Errorf("expected dropped rating without favorite, got %+v", parsed.Entries[2])
+	}
+}
+
+func TestParseImport_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte(malSample))
+	writer.Close()
+
+	parsed, err := ParseImport(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsed.Entries) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(parsed.Entries))
+	}
+}
+
+func TestParseImport_GzipBomb(t *testing.T) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte("<myanimelist>"))
+	writer.Write(make([]byte, MaxUnpackedSize))
+	writer.Close()
+
+	// zeros compress about a thousand times, the upload itself is small
+	if buf.Len() > 1<<20 {
+		t.Fatalf("expected a small archive, got %d bytes", buf.Len())
+	}
+	if _, err := ParseImport(buf.Bytes()); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestParseImport_ShikimoriJSON(t *testing.T) {
+	data := `[
+		{"target_title": "Death Note", "target_title_ru": "Тетрадь смерти", "target_id": 1535, "target_type": "Anime", "score": 10, "status": "completed", "text": null},
+		{"target_title": "Berserk", "target_title_ru": null, "target_id": 2, "target_type": "Manga", "score": 10, "status": "completed"},
+		{"target_title": "Naruto", "target_id": 20, "target_type": "Anime", "score": 0, "status": "planned"},
+		{"target_title": "Naruto", "target_id": 20, "target_type": "Anime", "score": 0, "status": "planned"}
+	]`
+
+	parsed, err := ParseImport([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(parsed.Entries) != 2 || parsed.Skipped != 2 {
+		t.Fatalf("expected 2 entries and 2 skipped, got %d and %d", len(parsed.Entries), parsed.Skipped)
+	}
+	if parsed.Entries[0].Title != "Тетрадь смерти" || parsed.Entries[0].Score != 10 {
+		t.Errorf("unexpected first entry: %+v", parsed.Entries[0])
+	}
+}
+
+func TestParseImport_OwnExportRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testLibrary()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed, err := ParseImport(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(parsed.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(parsed.Entries))
+	}
+	if parsed.Entries[0].RatedAt == nil || !parsed.Entries[0].RatedAt.Equal(time.Date(2024, 4, 2, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected rated_at to survive round trip, got %v", parsed.Entries[0].RatedAt)
+	}
+}
+
+func TestParseImport_MALExportRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMAL(&buf, testLibrary()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed, err := ParseImport(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(parsed.Entries) != 2 || parsed.Entries[0].Score != 9 {
+		t.Errorf("unexpected entries: %+v", parsed.Entries)
+	}
+}
+
+func TestParseImport_InvalidScoreIsDropped(t *testing.T) {
+	data := `[{"target_id": 5, "target_type": "Anime", "score": 42, "status": "watching"}]`
+
+	parsed, err := ParseImport([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsed.Entries) != 1 || parsed.Entries[0].Score != 0 {
+		t.Errorf("expected score to be dropped, got %+v", parsed.Entries)
+	}
+}
+
+func TestParseImport_UnknownFormat(t *testing.T) {
+	for _, data := range []string{"", "hello", `{"foo": 1}`, "<rss></rss>"} {
+		if _, err := ParseImport([]byte(data)); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("ParseImport(%q): expected ErrUnknownFormat, got %v", data, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ type Anime struct {
 	Score       string     `json:"score"`
 	Status      string     `json:"status"`
 	Episodes    int        `json:"episodes"`
+	Duration    int        `json:"duration"`
 	AiredOn     string     `json:"aired_on"`
 	ReleasedOn  string     `json:"released_on"`
 	Description string     `json:"description"`
@@ -18,6 +19,16 @@ type Anime struct {
 type AnimeImage struct {
 	Original string `json:"original"`
 	Preview  string `json:"preview"`
+	X96      string `json:"x96"`
+}
+
+// PosterFile is a poster telegram already has, Path is the shikimori image it
+// was uploaded from
+type PosterFile struct {
+	AnimeID int    `db:"anime_id"`
+	Size    string `db:"size"`
+	Path    string `db:"path"`
+	FileID  string `db:"file_id"`
 }
 
 type Genre struct {
@@ -27,3 +38,10 @@ type Genre struct {
 	Kind      string `json:"kind"`
 	EntryType string `json:"entry_type"`
 }
+
+type GenreCount struct {
+	GenreID int    `db:"genre_id"`
+	Name    string `db:"name"`
+	Russian string `db:"russian"`
+	Count   int    `db:"count"`
+}
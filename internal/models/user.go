@@ -8,6 +8,29 @@ type User struct {
 	CreatedAt time.Time `db:"created_at"`
 }
 
+// TitleDisplay values of UserSettings; auto shows both titles in Russian and
+// the romaji one otherwise
+const (
+	TitleDisplayAuto    = "auto"
+	TitleDisplayBoth    = "both"
+	TitleDisplayRomaji  = "romaji"
+	TitleDisplayRussian = "russian"
+)
+
+// UserSettings are a user's preferences; Language "" follows the Telegram
+// client and TimeZone "" is the server's zone
+type UserSettings struct {
+	UserID        int64  `db:"user_id"`
+	Language      string `db:"language"`
+	TitleDisplay  string `db:"title_display"`
+	TimeZone      string `db:"time_zone"`
+	HideNSFW      bool   `db:"hide_nsfw"`
+	NotifyFollows bool   `db:"notify_follows"`
+	NotifyWrapped bool   `db:"notify_wrapped"`
+	HideSpoilers  bool   `db:"hide_spoilers"`
+	PageSize      int    `db:"page_size"`
+}
+
 type Favorite struct {
 	ID        int       `db:"id"`
 	UserID    int64     `db:"user_id"`
@@ -24,3 +47,163 @@ type Rating struct {
 	Score   int       `db:"score"`
 	RatedAt time.Time `db:"rated_at"`
 }
+
+type Note struct {
+	ID        int       `db:"id"`
+	UserID    int64     `db:"user_id"`
+	AnimeID   int       `db:"anime_id"`
+	Text      string    `db:"text"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type Collection struct {
+	ID        int       `db:"id"`
+	UserID    int64     `db:"user_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	ItemCount int       `db:"item_count"`
+}
+
+type CollectionItem struct {
+	CollectionID int       `db:"collection_id"`
+	AnimeID      int       `db:"anime_id"`
+	Title        string    `db:"title"`
+	PosterURL    string    `db:"poster_url"`
+	AddedAt      time.Time `db:"added_at"`
+}
+
+type UserRating struct {
+	AnimeID int       `db:"anime_id"`
+	Title   string    `db:"title"`
+	Score   int       `db:"score"`
+	RatedAt time.Time `db:"rated_at"`
+}
+
+type FavoriteListItem struct {
+	AnimeID    int       `db:"anime_id"`
+	Title      string    `db:"title"`
+	AddedAt    time.Time `db:"added_at"`
+	MyScore    int       `db:"my_score"`
+	AnimeScore float64   `db:"anime_score"`
+}
+
+type CommunityRating struct {
+	AnimeID      int
+	Title        string
+	Votes        int
+	Average      float64
+	Distribution map[int]int
+}
+
+type StatsEntry struct {
+	AnimeID  int    `db:"anime_id"`
+	Kind     string `db:"kind"`
+	Episodes int    `db:"episodes"`
+	Duration int    `db:"duration"`
+	Favorite bool   `db:"favorite"`
+	Score    *int   `db:"score"`
+	// Cataloged is false when the title has no catalog snapshot yet
+	Cataloged bool `db:"cataloged"`
+}
+
+type ActivityEvent struct {
+	AnimeID  int       `db:"anime_id"`
+	Title    string    `db:"title"`
+	Name     string    `db:"name"`
+	Episodes int       `db:"episodes"`
+	Duration int       `db:"duration"`
+	Score    *int      `db:"score"`
+	At       time.Time `db:"at"`
+}
+
+// TasteSignal is one title the user has expressed an opinion on
+type TasteSignal struct {
+	AnimeID  int     `db:"anime_id"`
+	Title    string  `db:"title"`
+	Favorite bool    `db:"favorite"`
+	Score    *int    `db:"score"`
+	Liked    *bool   `db:"liked"`
+	Genres   []Genre `db:"-"`
+}
+
+type AnimeNeighbor struct {
+	AnimeID     int     `db:"anime_id"`
+	NeighborID  int     `db:"neighbor_id"`
+	Title       string  `db:"title"`
+	Similarity  float64 `db:"similarity"`
+	CommonUsers int     `db:"common_users"`
+}
+
+// Friend is a user someone follows
+type Friend struct {
+	UserID     int64     `db:"user_id"`
+	Username   string    `db:"username"`
+	FollowedAt time.Time `db:"followed_at"`
+}
+
+// FriendActivity is a favorite (Score is nil) or a rating by a followed user
+type FriendActivity struct {
+	UserID   int64     `db:"user_id"`
+	Username string    `db:"username"`
+	AnimeID  int       `db:"anime_id"`
+	Title    string    `db:"title"`
+	Score    *int      `db:"score"`
+	At       time.Time `db:"at"`
+}
+
+type FriendRating struct {
+	UserID   int64  `db:"user_id"`
+	Username string `db:"username"`
+	Score    int    `db:"score"`
+}
+
+// ChatWatchlistItem is an entry of a group chat's shared list; AddedBy is nil
+// once the member who added it deleted their data
+type ChatWatchlistItem struct {
+	ChatID      int64     `db:"chat_id"`
+	AnimeID     int       `db:"anime_id"`
+	Title       string    `db:"title"`
+	AddedBy     *int64    `db:"added_by"`
+	AddedByName string    `db:"added_by_name"`
+	AddedAt     time.Time `db:"added_at"`
+}
+
+type VoteOption struct {
+	AnimeID int    `db:"anime_id"`
+	Title   string `db:"title"`
+}
+
+// VoteSession is a /vote poll; Options are in poll order
+type VoteSession struct {
+	PollID        string       `db:"poll_id"`
+	ChatID        int64        `db:"chat_id"`
+	MessageID     int          `db:"message_id"`
+	StartedBy     *int64       `db:"started_by"`
+	CreatedAt     time.Time    `db:"created_at"`
+	ClosedAt      *time.Time   `db:"closed_at"`
+	WinnerAnimeID *int         `db:"winner_anime_id"`
+	Options       []VoteOption `db:"-"`
+}
+
+type RatingChange struct {
+	Score   int       `db:"score"`
+	RatedAt time.Time `db:"rated_at"`
+}
+
+type LibraryEntry struct {
+	AnimeID  int        `db:"anime_id"`
+	Title    string     `db:"title"`
+	Kind     string     `db:"kind"`
+	Episodes int        `db:"episodes"`
+	AddedAt  *time.Time `db:"added_at"`
+	Score    *int       `db:"score"`
+	RatedAt  *time.Time `db:"rated_at"`
+	Note     string     `db:"note"`
+}
+
+type ImportResult struct {
+	Imported  int
+	Skipped   int
+	Conflicts int
+}
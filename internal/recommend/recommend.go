@@ -0,0 +1,242 @@
+package recommend
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const (
+	// a favorite without a rating counts like a solid 8
+	favoriteWeight = 2.5
+	likedWeight    = 2.0
+	dislikedWeight = -3.0
+	// ratings above neutralScore pull genres up, below it push them down
+	neutralScore = 5.5
+
+	similarBonus = 0.5
+	qualityBonus = 0.1
+)
+
+// Profile maps genre ids to an affinity, positive for genres the user enjoys
+type Profile struct {
+	Genres  map[int]float64
+	signals []models.TasteSignal
+	known   map[int]bool
+}
+
+func Weight(signal models.TasteSignal) float64 {
+	weight := 0.0
+	switch {
+	case signal.Score != nil:
+		weight = float64(*signal.Score) - neutralScore
+	case signal.Favorite:
+		weight = favoriteWeight
+	}
+
+	if signal.Liked != nil {
+		if *signal.Liked {
+			weight += likedWeight
+		} else {
+			weight += dislikedWeight
+		}
+	}
+	return weight
+}
+
+func BuildProfile(signals []models.TasteSignal) Profile {
+	profile := Profile{Genres: make(map[int]float64), signals: signals, known: make(map[int]bool)}
+	if len(signals) == 0 {
+		return profile
+	}
+
+	for _, signal := range signals {
+		profile.known[signal.AnimeID] = true
+		weight := Weight(signal)
+		for _, genre := range signal.Genres {
+			profile.Genres[genre.ID] += weight
+		}
+	}
+	// normalize so long histories don't drown out the similarity bonus
+	for id := range profile.Genres {
+		profile.Genres[id] /= float64(len(signals))
+	}
+	return profile
+}
+
+// TopGenres returns up to n genre ids with a positive affinity, strongest first
+func (p Profile) TopGenres(n int) []int {
+	var ids []int
+	for id, affinity := range p.Genres {
+		if affinity > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if p.Genres[ids[i]] != p.Genres[ids[j]] {
+			return p.Genres[ids[i]] > p.Genres[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return ids
+}
+
+// Seeds returns up to n titles the user liked most, for similar-title lookups
+func (p Profile) Seeds(n int) []models.TasteSignal {
+	var seeds []models.TasteSignal
+	for _, signal := range p.signals {
+		if Weight(signal) > 0 {
+			seeds = append(seeds, signal)
+		}
+	}
+	sort.SliceStable(seeds, func(i, j int) bool {
+		return Weight(seeds[i]) > Weight(seeds[j])
+	})
+	if len(seeds) > n {
+		seeds = seeds[:n]
+	}
+	return seeds
+}
+
+// Genre looks up a genre the user has seen by id
+func (p Profile) Genre(id int) (models.Genre, bool) {
+	for _, signal := range p.signals {
+		for _, genre := range signal.Genres {
+			if genre.ID == id {
+				return genre, true
+			}
+		}
+	}
+	return models.Genre{}, false
+}
+
+// Known reports whether the user already has an opinion on the title
+func (p Profile) Known(animeID int) bool {
+	return p.known[animeID]
+}
+
+// Candidate is a title to rank; SimilarTo is set when it came from the
+// similar list of one of the user's titles
+type Candidate struct {
+	Anime     models.Anime
+	SimilarTo *models.TasteSignal
+}
+
+// Reason points at the user's own title that explains a pick; Genre is set
+// when the match is by genre rather than by similarity
+type Reason struct {
+	Signal models.TasteSignal
+	Genre  *models.Genre
+}
+
+type Pick struct {
+	Anime  models.Anime
+	Score  float64
+	Reason Reason
+}
+
+// Rank merges duplicate candidates, drops titles the user already knows and
+// returns the best limit picks; picks that can't be explained are skipped
+func Rank(profile Profile, candidates []Candidate, limit int) []Pick {
+	merged := make(map[int]*Candidate)
+	var order []int
+	for _, candidate := range candidates {
+		id := candidate.Anime.ID
+		if profile.Known(id) {
+			continue
+		}
+
+		existing, ok := merged[id]
+		if !ok {
+			c := candidate
+			merged[id] = &c
+			order = append(order, id)
+			continue
+		}
+		// keep whichever copy carries more detail and the strongest similarity
+		if len(candidate.Anime.Genres) > len(existing.Anime.Genres) {
+			existing.Anime = candidate.Anime
+		}
+		if candidate.SimilarTo != nil && (existing.SimilarTo == nil || Weight(*candidate.SimilarTo) > Weight(*existing.SimilarTo)) {
+			existing.SimilarTo = candidate.SimilarTo
+		}
+	}
+
+	var picks []Pick
+	for _, id := range order {
+		candidate := merged[id]
+		score := 0.0
+		for _, genre := range candidate.Anime.Genres {
+			score += profile.Genres[genre.ID]
+		}
+		if candidate.SimilarTo != nil {
+			score += similarBonus * Weight(*candidate.SimilarTo)
+		}
+		if rating, err := strconv.ParseFloat(candidate.Anime.Score, 64); err == nil {
+			score += qualityBonus * rating
+		}
+
+		reason, ok := profile.explain(*candidate)
+		if !ok || score <= 0 {
+			continue
+		}
+		picks = append(picks, Pick{Anime: candidate.Anime, Score: score, Reason: reason})
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		return picks[i].Score > picks[j].Score
+	})
+	if len(picks) > limit {
+		picks = picks[:limit]
+	}
+	return picks
+}
+
+func (p Profile) explain(candidate Candidate) (Reason, bool) {
+	if candidate.SimilarTo != nil {
+		return Reason{Signal: *candidate.SimilarTo}, true
+	}
+
+	// the candidate's genre the user likes most, then the user's strongest title in it
+	var best *models.Genre
+	for i, genre := range candidate.Anime.Genres {
+		if p.Genres[genre.ID] <= 0 {
+			continue
+		}
+		if best == nil || p.Genres[genre.ID] > p.Genres[best.ID] {
+			best = &candidate.Anime.Genres[i]
+		}
+	}
+	if best == nil {
+		return Reason{}, false
+	}
+
+	var signal *models.TasteSignal
+	for i, s := range p.signals {
+		if Weight(s) <= 0 || !hasGenre(s.Genres, best.ID) {
+			continue
+		}
+		if signal == nil || Weight(s) > Weight(*signal) {
+			signal = &p.signals[i]
+		}
+	}
+	if signal == nil {
+		return Reason{}, false
+	}
+
+	genre := *best
+	return Reason{Signal: *signal, Genre: &genre}, true
+}
+
+func hasGenre(genres []models.Genre, id int) bool {
+	for _, genre := range genres {
+		if genre.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package recommend
+
+import (
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+var (
+	scifi   = models.Genre{ID: 24, Name: "Sci-Fi", Russian: "Фантастика"}
+	drama   = models.Genre{ID: 8, Name: "Drama", Russian: "Драма"}
+	romance = models.Genre{ID: 22, Name: "Romance", Russian: "Романтика"}
+)
+
+func score(n int) *int { return &n }
+
+func liked(v bool) *bool { return &v }
+
+func testSignals() []models.TasteSignal {
+	return []models.TasteSignal{
+		{AnimeID: 9253, Title: "Steins;Gate", Score: score(10), Genres: []models.Genre{scifi, drama}},
+		{AnimeID: 1, Title: "Romance show", Score: score(2), Genres: []models.Genre{romance}},
+		{AnimeID: 2, Title: "Favorite drama", Favorite: true, Genres: []models.Genre{drama}},
+	}
+}
+
+func TestWeight(t *testing.T) {
+	tests := []struct {
+		signal models.TasteSignal
+		want   float64
+	}{
+		{models.TasteSignal{Score: score(10)}, 4.5},
+		{models.TasteSignal{Score: score(3)}, -2.5},
+		{models.TasteSignal{Favorite: true}, favoriteWeight},
+		{models.TasteSignal{Favorite: true, Score: score(6)}, 0.5},
+		{models.TasteSignal{Liked: liked(true)}, likedWeight},
+		{models.TasteSignal{Liked: liked(false)}, dislikedWeight},
+	}
+	for _, tt := range tests {
+		if got := Weight(tt.signal); got != tt.want {
+			t.Errorf("Weight(%+v) = %v, want %v", tt.signal, got, tt.want)
+		}
+	}
+}
+
+func TestBuildProfile(t *testing.T) {
+	profile := BuildProfile(testSignals())
+
+	if profile.Genres[romance.ID] >= 0 {
+		t.Errorf("expected a negative affinity for a low-rated genre, got %v", profile.Genres[romance.ID])
+	}
+	top := profile.TopGenres(5)
+	if len(top) != 2 || top[0] != drama.ID || top[1] != scifi.ID {
+		t.Errorf("unexpected top genres: %v", top)
+	}
+	seeds := profile.Seeds(1)
+	if len(seeds) != 1 || seeds[0].AnimeID != 9253 {
+		t.Errorf("unexpected seeds: %+v", seeds)
+	}
+	if !profile.Known(1) || profile.Known(3) {
+		t.Error("unexpected known titles")
+	}
+}
+
+func TestRank(t *testing.T) {
+	profile := BuildProfile(testSignals())
+	seed := testSignals()[0]
+
+	candidates := []Candidate{
+		{Anime: models.Anime{ID: 9253, Genres: []models.Genre{scifi}}},
+		{Anime: models.Anime{ID: 10, Name: "Romance only", Genres: []models.Genre{romance}}},
+		{Anime: models.Anime{ID: 11, Name: "Space drama", Score: "8.5", Genres: []models.Genre{scifi, drama}}},
+		{Anime: models.Anime{ID: 12, Name: "Similar"}, SimilarTo: &seed},
+		// the same title from the catalog brings its genres along
+		{Anime: models.Anime{ID: 12, Name: "Similar", Genres: []models.Genre{scifi}}},
+	}
+
+	picks := Rank(profile, candidates, 5)
+	if len(picks) != 2 {
+		t.Fatalf("expected 2 picks, got %+v", picks)
+	}
+	// two liked genres plus a good Shikimori score outweigh one genre and similarity
+	if picks[0].Anime.ID != 11 || picks[0].Reason.Genre == nil || picks[0].Reason.Genre.ID != drama.ID || picks[0].Reason.Signal.AnimeID != 9253 {
+		t.Errorf("unexpected genre pick: %+v", picks[0])
+	}
+	if picks[1].Anime.ID != 12 || picks[1].Reason.Genre != nil || picks[1].Reason.Signal.AnimeID != 9253 {
+		t.Errorf("expected the similar title to be explained by its seed: %+v", picks[1])
+	}
+	if len(picks[1].Anime.Genres) != 1 {
+		t.Errorf("expected merged genres, got %+v", picks[1].Anime)
+	}
+
+	if picks := Rank(profile, candidates, 1); len(picks) != 1 {
+		t.Errorf("expected the limit to apply, got %d picks", len(picks))
+	}
+}
+
+func TestRank_DislikeLowersGenre(t *testing.T) {
+	signals := append(testSignals(), models.TasteSignal{AnimeID: 3, Liked: liked(false), Genres: []models.Genre{drama}})
+	before := BuildProfile(testSignals())
+	after := BuildProfile(signals)
+
+	if after.Genres[drama.ID] >= before.Genres[drama.ID] {
+		t.Errorf("expected a dislike to lower the genre, got %v -> %v", before.Genres[drama.ID], after.Genres[drama.ID])
+	}
+	if !after.Known(3) {
+		t.Error("expected disliked titles to be excluded")
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+)
+
+const (
+	DeletionSourceBot = "bot"
+	DeletionSourceCLI = "cli"
+)
+
+// DeleteUserData removes everything stored in the database. Redis only caches
+// shikimori responses, which hold nothing about the user; dialog state and the
+// language cache live in the bot's memory, so /deleteme clears them itself and
+// the deleteuser command needs a bot restart to do the same
+func (s *AnimeService) DeleteUserData(userID int64, source string) (bool, error) {
+	return s.repository.DeleteUserContext(context.Background(), userID, source)
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/cache"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
@@ -10,15 +14,23 @@ import (
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/shikimori"
 )
 
+// catalog snapshots older than this are refreshed from Shikimori
+const catalogRefreshAge = 7 * 24 * time.Hour
+
+const maxNoteLength = 1000
+
 type AnimeService struct {
 	shikimoriClient shikimoriClientInterface
-	repository      *database.Repository
+	repository      database.Repo
 	cache           cacheInterface
 }
 
 type shikimoriClientInterface interface {
-	SearchAnime(query string, limit int) ([]models.Anime, error)
+	SearchAnime(query string, limit int, censored bool) ([]models.Anime, error)
 	GetAnimeById(id int) (*models.Anime, error)
+	GetSimilarAnime(id int) ([]models.Anime, error)
+	GetTopAnime(genreID int, limit int) ([]models.Anime, error)
+	FetchImage(path string) ([]byte, error)
 }
 
 type cacheInterface interface {
@@ -28,7 +40,7 @@ type cacheInterface interface {
 	SetAnimeDetails(id int, anime *models.Anime, duration time.Duration) error
 }
 
-func NewAnimeService(client *shikimori.Client, repo *database.Repository, cache *cache.Cache) *AnimeService {
+func NewAnimeService(client *shikimori.Client, repo database.Repo, cache *cache.Cache) *AnimeService {
 	return &AnimeService{
 		shikimoriClient: client,
 		repository:      repo,
@@ -41,27 +53,70 @@ func (s *AnimeService) SetShikimoriClient(client shikimoriClientInterface) {
 }
 
 func (s *AnimeService) SearchAnime(query string) ([]models.Anime, error) {
+	return s.searchAnime(query, DefaultSettings(0))
+}
+
+// SearchAnimeFor searches with the user's page size and NSFW filter
+func (s *AnimeService) SearchAnimeFor(userID int64, query string) ([]models.Anime, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchAnime(query, settings)
+}
+
+func (s *AnimeService) searchAnime(query string, settings models.UserSettings) ([]models.Anime, error) {
+	// searches with the default settings keep the plain query as their cache key
+	cacheKey := query
+	if settings.PageSize != DefaultPageSize || !settings.HideNSFW {
+		cacheKey = fmt.Sprintf("%s|%d|%t", query, settings.PageSize, settings.HideNSFW)
+	}
+
 	if s.cache != nil {
-		cached, err := s.cache.GetAnimeSearch(query)
+		cached, err := s.cache.GetAnimeSearch(cacheKey)
 		if err == nil && cached != nil {
 			return cached, nil
 		}
 	}
 
-	animes, err := s.shikimoriClient.SearchAnime(query, 10)
+	animes, err := s.shikimoriClient.SearchAnime(query, settings.PageSize, settings.HideNSFW)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search anime: %w", err)
 	}
 
 	enrichedAnimes := s.enrichSearchResults(animes)
+	if settings.HideNSFW {
+		enrichedAnimes = filterNSFW(enrichedAnimes)
+	}
 
 	if s.cache != nil {
-		_ = s.cache.SetAnimeSearch(query, enrichedAnimes, time.Hour)
+		_ = s.cache.SetAnimeSearch(cacheKey, enrichedAnimes, time.Hour)
 	}
 
 	return enrichedAnimes, nil
 }
 
+// filterNSFW drops what Shikimori's censored flag lets through: it hides
+// hentai but not erotica
+func filterNSFW(animes []models.Anime) []models.Anime {
+	filtered := animes[:0]
+	for _, anime := range animes {
+		if !isNSFW(anime) {
+			filtered = append(filtered, anime)
+		}
+	}
+	return filtered
+}
+
+func isNSFW(anime models.Anime) bool {
+	for _, genre := range anime.Genres {
+		if genre.Name == "Hentai" || genre.Name == "Erotica" {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *AnimeService) enrichSearchResults(animes []models.Anime) []models.Anime {
 	for i := range animes {
 		if animes[i].Description == "" {
@@ -82,11 +137,30 @@ func (s *AnimeService) GetAnimeByID(id int) (*models.Anime, error) {
 		}
 	}
 
+	stored, fetchedAt, err := s.repository.GetCatalogAnimeContext(context.Background(), id)
+	if err != nil {
+		stored = nil
+	}
+	if stored != nil && time.Since(fetchedAt) < catalogRefreshAge {
+		if s.cache != nil {
+			_ = s.cache.SetAnimeDetails(id, stored, 24*time.Hour)
+		}
+		return stored, nil
+	}
+
 	anime, err := s.shikimoriClient.GetAnimeById(id)
 	if err != nil {
+		// serve a stale snapshot rather than nothing while Shikimori is unavailable
+		if stored != nil {
+			return stored, nil
+		}
 		return nil, fmt.Errorf("failed to get anime by id: %w", err)
 	}
 
+	if err := s.repository.UpsertAnimeContext(context.Background(), *anime, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to save anime: %w", err)
+	}
+
 	if s.cache != nil {
 		_ = s.cache.SetAnimeDetails(id, anime, 24*time.Hour)
 	}
@@ -94,42 +168,54 @@ func (s *AnimeService) GetAnimeByID(id int) (*models.Anime, error) {
 	return anime, nil
 }
 
-func (s *AnimeService) AddToFavorites(userID int64, anime models.Anime) error {
-	title := anime.Russian
-	if title == "" {
-		title = anime.Name
+func displayTitle(anime models.Anime) string {
+	if anime.Russian != "" {
+		return anime.Russian
 	}
+	return anime.Name
+}
 
-	posterURL := ""
-	if anime.Image.Preview != "" {
-		posterURL = "https://shikimori.one" + anime.Image.Preview
+func previewURL(anime models.Anime) string {
+	if anime.Image.Preview == "" {
+		return ""
 	}
+	return "https://shikimori.one" + anime.Image.Preview
+}
 
+func (s *AnimeService) AddToFavorites(userID int64, anime models.Anime) error {
 	favorite := models.Favorite{
 		UserID:    userID,
 		AnimeID:   anime.ID,
-		Title:     title,
-		PosterURL: posterURL,
+		Title:     displayTitle(anime),
+		PosterURL: previewURL(anime),
 		AddedAt:   time.Now(),
 	}
 
-	return s.repository.AddFavorite(favorite)
+	return s.repository.AddFavoriteContext(context.Background(), favorite)
 }
 
 func (s *AnimeService) RemoveFromFavorites(userID int64, animeID int) error {
-	return s.repository.RemoveFavorite(userID, animeID)
+	return s.repository.RemoveFavoriteContext(context.Background(), userID, animeID)
 }
 
 func (s *AnimeService) GetUserFavorites(userID int64) ([]models.Favorite, error) {
-	return s.repository.GetFavorites(userID)
+	return s.repository.GetFavoritesContext(context.Background(), userID)
 }
 
 func (s *AnimeService) IsFavorite(userID int64, animeID int) (bool, error) {
-	return s.repository.IsFavorite(userID, animeID)
+	return s.repository.IsFavoriteContext(context.Background(), userID, animeID)
 }
 
 func (s *AnimeService) CountFavorites(userID int64) (int, error) {
-	return s.repository.CountFavorites(userID)
+	return s.repository.CountFavoritesContext(context.Background(), userID)
+}
+
+func (s *AnimeService) GetFavoritesPage(userID int64, query database.FavoritesQuery) ([]models.FavoriteListItem, error) {
+	return s.repository.GetFavoritesPageContext(context.Background(), userID, query)
+}
+
+func (s *AnimeService) CountFavoritesMatching(userID int64, search string) (int, error) {
+	return s.repository.CountFavoritesMatchingContext(context.Background(), userID, search)
 }
 
 func (s *AnimeService) EnsureUserExists(userID int64, username string) error {
@@ -138,7 +224,7 @@ func (s *AnimeService) EnsureUserExists(userID int64, username string) error {
 		Username:  username,
 		CreatedAt: time.Now(),
 	}
-	return s.repository.CreateUser(user)
+	return s.repository.CreateUserContext(context.Background(), user)
 }
 
 func (s *AnimeService) AddRating(userID int64, animeID int, score int) error {
@@ -153,13 +239,62 @@ func (s *AnimeService) AddRating(userID int64, animeID int, score int) error {
 		RatedAt: time.Now(),
 	}
 
-	return s.repository.AddRating(rating)
+	return s.repository.AddRatingContext(context.Background(), rating)
 }
 
 func (s *AnimeService) GetUserRating(userID int64, animeID int) (*models.Rating, error) {
-	return s.repository.GetRating(userID, animeID)
+	rating, err := s.repository.GetRatingContext(context.Background(), userID, animeID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return rating, err
 }
 
 func (s *AnimeService) DeleteRating(userID int64, animeID int) error {
-	return s.repository.DeleteRating(userID, animeID)
+	return s.repository.DeleteRatingContext(context.Background(), userID, animeID)
+}
+
+func (s *AnimeService) GetUserRatings(userID int64, query database.RatingsQuery) ([]models.UserRating, error) {
+	return s.repository.GetUserRatingsContext(context.Background(), userID, query)
+}
+
+func (s *AnimeService) GetRatingDistribution(userID int64) (map[int]int, error) {
+	return s.repository.CountRatingsByScoreContext(context.Background(), userID)
+}
+
+func (s *AnimeService) GetRatingHistory(userID int64, animeID int) ([]models.RatingChange, error) {
+	return s.repository.GetRatingHistoryContext(context.Background(), userID, animeID)
+}
+
+func (s *AnimeService) SaveNote(userID int64, animeID int, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("note must not be empty")
+	}
+	if utf8.RuneCountInString(text) > maxNoteLength {
+		return fmt.Errorf("note must be at most %d characters", maxNoteLength)
+	}
+
+	now := time.Now()
+	note := models.Note{
+		UserID:    userID,
+		AnimeID:   animeID,
+		Text:      text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return s.repository.SaveNoteContext(context.Background(), note)
+}
+
+func (s *AnimeService) GetUserNote(userID int64, animeID int) (*models.Note, error) {
+	note, err := s.repository.GetNoteContext(context.Background(), userID, animeID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return note, err
+}
+
+func (s *AnimeService) DeleteNote(userID int64, animeID int) error {
+	return s.repository.DeleteNoteContext(context.Background(), userID, animeID)
 }
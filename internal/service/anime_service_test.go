@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,13 +14,23 @@ import (
 )
 
 type mockShikimoriClient struct {
-	searchAnimeFunc func(query string, limit int) ([]models.Anime, error)
+	searchAnimeFunc func(query string, limit int, censored bool) ([]models.Anime, error)
 	getAnimeFunc    func(id int) (*models.Anime, error)
+	getSimilarFunc  func(id int) ([]models.Anime, error)
+	getTopFunc      func(genreID int, limit int) ([]models.Anime, error)
+	fetchImageFunc  func(path string) ([]byte, error)
 }
 
-func (m *mockShikimoriClient) SearchAnime(query string, limit int) ([]models.Anime, error) {
+func (m *mockShikimoriClient) FetchImage(path string) ([]byte, error) {
+	if m.fetchImageFunc != nil {
+		return m.fetchImageFunc(path)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockShikimoriClient) SearchAnime(query string, limit int, censored bool) ([]models.Anime, error) {
 	if m.searchAnimeFunc != nil {
-		return m.searchAnimeFunc(query, limit)
+		return m.searchAnimeFunc(query, limit, censored)
 	}
 	return nil, errors.New("not implemented")
 }
@@ -31,6 +42,20 @@ func (m *mockShikimoriClient) GetAnimeById(id int) (*models.Anime, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockShikimoriClient) GetSimilarAnime(id int) ([]models.Anime, error) {
+	if m.getSimilarFunc != nil {
+		return m.getSimilarFunc(id)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockShikimoriClient) GetTopAnime(genreID int, limit int) ([]models.Anime, error) {
+	if m.getTopFunc != nil {
+		return m.getTopFunc(genreID, limit)
+	}
+	return nil, errors.New("not implemented")
+}
+
 type mockCache struct {
 	getAnimeSearchFunc  func(query string) ([]models.Anime, error)
 	setAnimeSearchFunc  func(query string, animes []models.Anime, duration time.Duration) error
@@ -175,7 +200,7 @@ func TestSearchAnime_CacheMiss(t *testing.T) {
 		return nil, errors.New("cache miss")
 	}
 
-	shikimoriMock.searchAnimeFunc = func(query string, limit int) ([]models.Anime, error) {
+	shikimoriMock.searchAnimeFunc = func(query string, limit int, censored bool) ([]models.Anime, error) {
 		return expectedAnimes, nil
 	}
 
@@ -204,7 +229,7 @@ func TestSearchAnime_ShikimoriError(t *testing.T) {
 		return nil, errors.New("cache miss")
 	}
 
-	shikimoriMock.searchAnimeFunc = func(query string, limit int) ([]models.Anime, error) {
+	shikimoriMock.searchAnimeFunc = func(query string, limit int, censored bool) ([]models.Anime, error) {
 		return nil, errors.New("api error")
 	}
 
@@ -264,6 +289,16 @@ func TestGetAnimeByID_CacheMiss(t *testing.T) {
 		return nil
 	}
 
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM animes`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO animes`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM anime_genres`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
 	result, err := service.GetAnimeByID(1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -299,6 +334,125 @@ func TestGetAnimeByID_ShikimoriError(t *testing.T) {
 	}
 }
 
+func TestGetAnimeByID_FreshCatalogSkipsAPI(t *testing.T) {
+	service, mock, shikimoriMock, cacheMock := newTestServiceWithMocks(t)
+
+	cacheMock.getAnimeDetailsFunc = func(id int) (*models.Anime, error) {
+		return nil, nil
+	}
+
+	apiCalled := false
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		apiCalled = true
+		return nil, errors.New("should not be called")
+	}
+
+	expectCatalogRow(mock, 1, "Naruto", time.Now().Add(-time.Hour))
+
+	result, err := service.GetAnimeByID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Name != "Naruto" {
+		t.Errorf("expected catalog anime, got %+v", result)
+	}
+
+	if apiCalled {
+		t.Error("expected fresh catalog entry to be served without API call")
+	}
+}
+
+func TestGetAnimeByID_StaleCatalogFallback(t *testing.T) {
+	service, mock, shikimoriMock, cacheMock := newTestServiceWithMocks(t)
+
+	cacheMock.getAnimeDetailsFunc = func(id int) (*models.Anime, error) {
+		return nil, nil
+	}
+
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		return nil, errors.New("api error")
+	}
+
+	expectCatalogRow(mock, 1, "Naruto", time.Now().Add(-2*catalogRefreshAge))
+
+	result, err := service.GetAnimeByID(1)
+	if err != nil {
+		t.Fatalf("expected stale catalog entry, got error: %v", err)
+	}
+
+	if result.Name != "Naruto" {
+		t.Errorf("expected catalog anime, got %+v", result)
+	}
+}
+
+func TestGetAnimeByID_WritesThroughCatalog(t *testing.T) {
+	service, mock, shikimoriMock, cacheMock := newTestServiceWithMocks(t)
+
+	cacheMock.getAnimeDetailsFunc = func(id int) (*models.Anime, error) {
+		return nil, nil
+	}
+
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		return &models.Anime{ID: id, Name: "Naruto"}, nil
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM animes`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO animes`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM anime_genres`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if _, err := service.GetAnimeByID(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetAnimeByID_CatalogWriteError(t *testing.T) {
+	service, mock, shikimoriMock, cacheMock := newTestServiceWithMocks(t)
+
+	cacheMock.getAnimeDetailsFunc = func(id int) (*models.Anime, error) {
+		return nil, nil
+	}
+
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		return &models.Anime{ID: id, Name: "Naruto"}, nil
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM animes`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin().WillReturnError(errors.New("database is locked"))
+
+	if _, err := service.GetAnimeByID(1); err == nil {
+		t.Fatal("expected the catalog write error to be returned")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func expectCatalogRow(mock sqlmock.Sqlmock, id int, name string, fetchedAt time.Time) {
+	rows := sqlmock.NewRows([]string{"id", "name", "russian", "kind", "score", "status", "episodes", "duration",
+		"aired_on", "released_on", "description", "image_original", "image_preview", "fetched_at"}).
+		AddRow(id, name, "", "tv", "8.0", "released", 12, 24, "", "", "", "", "", fetchedAt)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM animes`)).
+		WithArgs(id).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM genres g`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "russian", "kind", "entry_type"}))
+}
+
 func TestSearchAnime_EnrichError(t *testing.T) {
 	service, mock, shikimoriMock, cacheMock := newTestServiceWithMocks(t)
 
@@ -310,7 +464,7 @@ func TestSearchAnime_EnrichError(t *testing.T) {
 		{ID: 1, Name: "Naruto", Description: ""},
 	}
 
-	shikimoriMock.searchAnimeFunc = func(query string, limit int) ([]models.Anime, error) {
+	shikimoriMock.searchAnimeFunc = func(query string, limit int, censored bool) ([]models.Anime, error) {
 		return animes, nil
 	}
 
@@ -554,3 +708,78 @@ func TestAddToFavorites_WithEnglishName(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestSaveNote_TrimsText(t *testing.T) {
+	service, mock := newTestService(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO notes`)).
+		WithArgs(int64(123), 1, "dub is bad", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := service.SaveNote(123, 1, "  dub is bad \n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSaveNote_Empty(t *testing.T) {
+	service, _ := newTestService(t)
+
+	if err := service.SaveNote(123, 1, "   "); err == nil {
+		t.Error("expected error for empty note")
+	}
+}
+
+func TestSaveNote_TooLong(t *testing.T) {
+	service, _ := newTestService(t)
+
+	text := strings.Repeat("я", maxNoteLength+1)
+	if err := service.SaveNote(123, 1, text); err == nil {
+		t.Error("expected error for too long note")
+	}
+}
+
+func TestGetRatingDistribution(t *testing.T) {
+	service, mock := newTestService(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT score, COUNT(*) AS count FROM ratings WHERE user_id = $1 GROUP BY score`)).
+		WithArgs(int64(123)).
+		WillReturnRows(sqlmock.NewRows([]string{"score", "count"}).AddRow(9, 4))
+
+	distribution, err := service.GetRatingDistribution(123)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if distribution[9] != 4 {
+		t.Errorf("expected 4 ratings of 9, got %v", distribution)
+	}
+}
+
+func TestGetUserRating_NotFoundIsNil(t *testing.T) {
+	service, mock := newTestService(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM ratings WHERE user_id = $1 AND anime_id = $2`)).
+		WithArgs(int64(123), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "anime_id", "score", "rated_at"}))
+
+	rating, err := service.GetUserRating(123, 1)
+	if err != nil || rating != nil {
+		t.Errorf("expected nil rating without error, got %+v (%v)", rating, err)
+	}
+}
+
+func TestGetUserRating_DatabaseError(t *testing.T) {
+	service, mock := newTestService(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM ratings WHERE user_id = $1 AND anime_id = $2`)).
+		WithArgs(int64(123), 1).
+		WillReturnError(errors.New("connection reset"))
+
+	if _, err := service.GetUserRating(123, 1); err == nil {
+		t.Error("expected database error to be returned")
+	}
+}
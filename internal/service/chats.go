@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// AddToChatWatchlist puts anime on a group chat's shared list on behalf of
+// userID; it reports false when someone already added it
+func (s *AnimeService) AddToChatWatchlist(chatID int64, userID int64, anime *models.Anime) (bool, error) {
+	title := anime.Russian
+	if title == "" {
+		title = anime.Name
+	}
+
+	item := models.ChatWatchlistItem{
+		ChatID:  chatID,
+		AnimeID: anime.ID,
+		Title:   title,
+		AddedBy: &userID,
+		AddedAt: time.Now(),
+	}
+	return s.repository.AddToChatWatchlistContext(context.Background(), item)
+}
+
+func (s *AnimeService) RemoveFromChatWatchlist(chatID int64, animeID int) error {
+	return s.repository.RemoveFromChatWatchlistContext(context.Background(), chatID, animeID)
+}
+
+func (s *AnimeService) GetChatWatchlist(chatID int64) ([]models.ChatWatchlistItem, error) {
+	return s.repository.GetChatWatchlistContext(context.Background(), chatID)
+}
+
+func (s *AnimeService) GetChatWatchlistItem(chatID int64, animeID int) (*models.ChatWatchlistItem, error) {
+	item, err := s.repository.GetChatWatchlistItemContext(context.Background(), chatID, animeID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return item, err
+}
+
+func (s *AnimeService) MoveChatWatchlist(fromChatID int64, toChatID int64) error {
+	return s.repository.MoveChatWatchlistContext(context.Background(), fromChatID, toChatID)
+}
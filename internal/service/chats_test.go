@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestAddToChatWatchlist(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+	const chatID = int64(-42)
+
+	if err := service.EnsureUserExists(1, "alice"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	added, err := service.AddToChatWatchlist(chatID, 1, &models.Anime{ID: 5, Name: "Mushishi"})
+	if err != nil || !added {
+		t.Fatalf("expected anime added, got %v (%v)", added, err)
+	}
+	if added, _ := service.AddToChatWatchlist(chatID, 1, &models.Anime{ID: 5, Name: "Mushishi"}); added {
+		t.Error("expected the second add to be a no-op")
+	}
+
+	item, err := service.GetChatWatchlistItem(chatID, 5)
+	if err != nil || item == nil || item.Title != "Mushishi" || item.AddedByName != "alice" {
+		t.Errorf("unexpected item: %+v (%v)", item, err)
+	}
+	if item, err := service.GetChatWatchlistItem(chatID, 6); item != nil || err != nil {
+		t.Errorf("expected nil for a missing entry, got %+v (%v)", item, err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const maxCollectionNameLength = 64
+
+func validateCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("collection name must not be empty")
+	}
+	if utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "", fmt.Errorf("collection name must be at most %d characters", maxCollectionNameLength)
+	}
+	return name, nil
+}
+
+func (s *AnimeService) CreateCollection(userID int64, name string) (*models.Collection, error) {
+	name, err := validateCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := models.Collection{
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+
+	id, err := s.repository.CreateCollectionContext(context.Background(), collection)
+	if err != nil {
+		return nil, err
+	}
+	collection.ID = id
+
+	return &collection, nil
+}
+
+func (s *AnimeService) RenameCollection(userID int64, collectionID int, name string) error {
+	name, err := validateCollectionName(name)
+	if err != nil {
+		return err
+	}
+	return s.repository.RenameCollectionContext(context.Background(), userID, collectionID, name)
+}
+
+func (s *AnimeService) DeleteCollection(userID int64, collectionID int) error {
+	return s.repository.DeleteCollectionContext(context.Background(), userID, collectionID)
+}
+
+func (s *AnimeService) GetUserCollections(userID int64) ([]models.Collection, error) {
+	return s.repository.GetCollectionsContext(context.Background(), userID)
+}
+
+func (s *AnimeService) GetCollection(userID int64, collectionID int) (*models.Collection, error) {
+	collection, err := s.repository.GetCollectionContext(context.Background(), userID, collectionID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return collection, err
+}
+
+func getOwnedCollection(ctx context.Context, repo database.Repo, userID int64, collectionID int) (*models.Collection, error) {
+	collection, err := repo.GetCollectionContext(ctx, userID, collectionID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("collection %d: %w", collectionID, database.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *AnimeService) AddToCollection(userID int64, collectionID int, animeID int) error {
+	ctx := context.Background()
+	if _, err := getOwnedCollection(ctx, s.repository, userID, collectionID); err != nil {
+		return err
+	}
+
+	// the API lookup stays outside the transaction
+	anime, err := s.GetAnimeByID(animeID)
+	if err != nil {
+		return err
+	}
+
+	item := models.CollectionItem{
+		CollectionID: collectionID,
+		AnimeID:      anime.ID,
+		Title:        displayTitle(*anime),
+		PosterURL:    previewURL(*anime),
+		AddedAt:      time.Now(),
+	}
+
+	return s.repository.WithTx(ctx, func(tx database.Repo) error {
+		// the collection may have been deleted while the anime was loading
+		if _, err := getOwnedCollection(ctx, tx, userID, collectionID); err != nil {
+			return err
+		}
+		return tx.AddToCollectionContext(ctx, item)
+	})
+}
+
+func (s *AnimeService) RemoveFromCollection(userID int64, collectionID int, animeID int) error {
+	ctx := context.Background()
+	return s.repository.WithTx(ctx, func(tx database.Repo) error {
+		if _, err := getOwnedCollection(ctx, tx, userID, collectionID); err != nil {
+			return err
+		}
+		return tx.RemoveFromCollectionContext(ctx, collectionID, animeID)
+	})
+}
+
+func (s *AnimeService) GetCollectionItemsPage(userID int64, collectionID int, after *database.CollectionCursor, limit int) ([]models.CollectionItem, error) {
+	ctx := context.Background()
+	if _, err := getOwnedCollection(ctx, s.repository, userID, collectionID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetCollectionItemsPageContext(ctx, collectionID, after, limit)
+}
+
+func (s *AnimeService) GetAnimeCollectionIDs(userID int64, animeID int) ([]int, error) {
+	return s.repository.GetAnimeCollectionIDsContext(context.Background(), userID, animeID)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestCreateCollection_Success(t *testing.T) {
+	service, mock := newTestService(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO collections`)).
+		WithArgs(int64(123), "comfort rewatch", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	collection, err := service.CreateCollection(123, "  comfort rewatch ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if collection.ID != 4 || collection.Name != "comfort rewatch" {
+		t.Errorf("unexpected collection: %+v", collection)
+	}
+}
+
+func TestCreateCollection_InvalidName(t *testing.T) {
+	service, _ := newTestService(t)
+
+	if _, err := service.CreateCollection(123, " "); err == nil {
+		t.Error("expected error for empty name")
+	}
+
+	if _, err := service.CreateCollection(123, strings.Repeat("x", maxCollectionNameLength+1)); err == nil {
+		t.Error("expected error for too long name")
+	}
+}
+
+func TestAddToCollection_UsesAnimeDetails(t *testing.T) {
+	service, mock, _, cacheMock := newTestServiceWithMocks(t)
+
+	cacheMock.getAnimeDetailsFunc = func(id int) (*models.Anime, error) {
+		return &models.Anime{ID: id, Name: "Naruto", Russian: "Наруто", Image: models.AnimeImage{Preview: "/n.jpg"}}, nil
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM collections c`)).
+		WithArgs(1, int64(123)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "created_at", "item_count"}).
+			AddRow(1, int64(123), "friends", time.Now(), 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM collections c`)).
+		WithArgs(1, int64(123)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "created_at", "item_count"}).
+			AddRow(1, int64(123), "friends", time.Now(), 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO collection_items`)).
+		WithArgs(1, 5, "Наруто", "https://shikimori.one/n.jpg", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	if err := service.AddToCollection(123, 1, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestAddToCollection_ForeignCollection(t *testing.T) {
+	service, mock := newTestService(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM collections c`)).
+		WithArgs(1, int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "created_at", "item_count"}))
+
+	if err := service.AddToCollection(999, 1, 5); err == nil {
+		t.Error("expected error for collection owned by another user")
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const (
+	CommunityTopMinVotes = 3
+	CommunityTopLimit    = 10
+)
+
+func (s *AnimeService) GetCommunityRating(animeID int) (*models.CommunityRating, error) {
+	rating, err := s.repository.GetCommunityRatingContext(context.Background(), animeID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return rating, err
+}
+
+func (s *AnimeService) GetCommunityTop(minVotes int, limit int) ([]models.CommunityRating, error) {
+	if minVotes < 1 {
+		minVotes = 1
+	}
+	return s.repository.GetCommunityTopContext(context.Background(), minVotes, limit)
+}
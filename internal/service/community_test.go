@@ -0,0 +1,39 @@
+package service
+
+import "testing"
+
+func TestGetCommunityRating_NoVotes(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	rating, err := service.GetCommunityRating(1)
+	if err != nil || rating != nil {
+		t.Fatalf("expected nil rating without votes, got %+v (%v)", rating, err)
+	}
+}
+
+func TestGetCommunityTop_MinVotes(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	for _, userID := range []int64{1, 2} {
+		if err := service.EnsureUserExists(userID, "u"); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		if err := service.AddRating(userID, 1, 8); err != nil {
+			t.Fatalf("failed to rate: %v", err)
+		}
+	}
+	if err := service.AddRating(1, 2, 10); err != nil {
+		t.Fatalf("failed to rate: %v", err)
+	}
+
+	top, err := service.GetCommunityTop(2, CommunityTopLimit)
+	if err != nil || len(top) != 1 || top[0].AnimeID != 1 || top[0].Votes != 2 {
+		t.Errorf("unexpected top: %+v (%v)", top, err)
+	}
+
+	// a non-positive minimum still requires at least one vote
+	top, err = service.GetCommunityTop(0, CommunityTopLimit)
+	if err != nil || len(top) != 2 || top[0].AnimeID != 2 {
+		t.Errorf("unexpected top: %+v (%v)", top, err)
+	}
+}
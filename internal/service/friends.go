@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const (
+	FriendsActivityLimit = 10
+	FriendRatingsLimit   = 5
+
+	friendCodeLength = 8
+	// no 0/o or 1/l/i, so codes survive being read out loud
+	friendCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	ErrUnknownFriendCode = errors.New("unknown friend code")
+	ErrCannotFollowSelf  = errors.New("cannot follow yourself")
+)
+
+func newFriendCode() (string, error) {
+	buf := make([]byte, friendCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate friend code: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = friendCodeAlphabet[int(b)%len(friendCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// GetFriendCode returns the user's shareable code, creating it on first use
+func (s *AnimeService) GetFriendCode(userID int64) (string, error) {
+	code, err := newFriendCode()
+	if err != nil {
+		return "", err
+	}
+	return s.repository.EnsureFriendCodeContext(context.Background(), userID, code)
+}
+
+// FollowByCode makes userID follow the owner of code and returns who that is
+func (s *AnimeService) FollowByCode(userID int64, code string) (*models.User, error) {
+	ctx := context.Background()
+
+	friend, err := s.repository.GetUserByFriendCodeContext(ctx, strings.ToLower(strings.TrimSpace(code)))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrUnknownFriendCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if friend.ID == userID {
+		return nil, ErrCannotFollowSelf
+	}
+
+	if err := s.repository.FollowContext(ctx, userID, friend.ID); err != nil {
+		return nil, err
+	}
+	return friend, nil
+}
+
+func (s *AnimeService) Unfollow(userID int64, friendID int64) error {
+	return s.repository.UnfollowContext(context.Background(), userID, friendID)
+}
+
+func (s *AnimeService) GetFollowing(userID int64) ([]models.Friend, error) {
+	return s.repository.GetFollowingContext(context.Background(), userID)
+}
+
+func (s *AnimeService) GetFriendsActivity(userID int64) ([]models.FriendActivity, error) {
+	return s.repository.GetFriendsActivityContext(context.Background(), userID, FriendsActivityLimit)
+}
+
+func (s *AnimeService) GetFriendRatings(userID int64, animeID int) ([]models.FriendRating, error) {
+	return s.repository.GetFriendRatingsContext(context.Background(), userID, animeID, FriendRatingsLimit)
+}
+
+func (s *AnimeService) GetShareList(userID int64) (bool, error) {
+	return s.repository.GetShareListContext(context.Background(), userID)
+}
+
+func (s *AnimeService) SetShareList(userID int64, share bool) error {
+	return s.repository.SetShareListContext(context.Background(), userID, share)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFollowByCode(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	for _, userID := range []int64{1, 2} {
+		if err := service.EnsureUserExists(userID, "u"); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	code, err := service.GetFriendCode(2)
+	if err != nil || len(code) != friendCodeLength {
+		t.Fatalf("unexpected code %q (%v)", code, err)
+	}
+	if again, _ := service.GetFriendCode(2); again != code {
+		t.Errorf("expected a stable code, got %q and %q", code, again)
+	}
+
+	if _, err := service.FollowByCode(2, code); !errors.Is(err, ErrCannotFollowSelf) {
+		t.Errorf("expected ErrCannotFollowSelf, got %v", err)
+	}
+	if _, err := service.FollowByCode(1, "nope2345"); !errors.Is(err, ErrUnknownFriendCode) {
+		t.Errorf("expected ErrUnknownFriendCode, got %v", err)
+	}
+
+	// codes are forgiving about case and stray spaces
+	friend, err := service.FollowByCode(1, " "+strings.ToUpper(code)+" ")
+	if err != nil || friend.ID != 2 {
+		t.Fatalf("expected to follow user 2, got %+v (%v)", friend, err)
+	}
+	following, err := service.GetFollowing(1)
+	if err != nil || len(following) != 1 || following[0].UserID != 2 {
+		t.Errorf("unexpected following: %+v (%v)", following, err)
+	}
+}
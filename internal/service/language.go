@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+)
+
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// GetLanguage returns the language the user picked in the bot, or "" when the
+// bot should follow their Telegram client
+func (s *AnimeService) GetLanguage(userID int64) (i18n.Lang, error) {
+	code, err := s.repository.GetUserLanguageContext(context.Background(), userID)
+	if errors.Is(err, database.ErrNotFound) || code == "" {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	lang, ok := i18n.Parse(code)
+	if !ok {
+		return "", nil
+	}
+	return lang, nil
+}
+
+// SetLanguage stores a language override; an empty lang clears it
+func (s *AnimeService) SetLanguage(userID int64, lang i18n.Lang) error {
+	if lang != "" {
+		if _, ok := i18n.Parse(string(lang)); !ok {
+			return ErrUnsupportedLanguage
+		}
+	}
+	return s.repository.SetUserLanguageContext(context.Background(), userID, string(lang))
+}
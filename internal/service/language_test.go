@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+)
+
+func TestLanguageOverride(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	if lang, err := service.GetLanguage(1); lang != "" || err != nil {
+		t.Fatalf("expected no override for an unknown user, got %q (%v)", lang, err)
+	}
+	if err := service.EnsureUserExists(1, "alice"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if lang, err := service.GetLanguage(1); lang != "" || err != nil {
+		t.Fatalf("expected no override by default, got %q (%v)", lang, err)
+	}
+
+	if err := service.SetLanguage(1, i18n.English); err != nil {
+		t.Fatalf("failed to set language: %v", err)
+	}
+	if lang, err := service.GetLanguage(1); lang != i18n.English || err != nil {
+		t.Errorf("expected english, got %q (%v)", lang, err)
+	}
+
+	if err := service.SetLanguage(1, "xx"); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("expected ErrUnsupportedLanguage, got %v", err)
+	}
+
+	if err := service.SetLanguage(1, ""); err != nil {
+		t.Fatalf("failed to reset language: %v", err)
+	}
+	if lang, _ := service.GetLanguage(1); lang != "" {
+		t.Errorf("expected the override cleared, got %q", lang)
+	}
+}
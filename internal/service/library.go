@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/library"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+const maxTitleLength = 500
+
+func (s *AnimeService) GetLibrary(userID int64) (library.Library, error) {
+	entries, err := s.repository.GetLibraryContext(context.Background(), userID)
+	if err != nil {
+		return library.Library{}, err
+	}
+	return library.New(entries, time.Now()), nil
+}
+
+func (s *AnimeService) ImportLibrary(userID int64, data []byte) (models.ImportResult, error) {
+	parsed, err := library.ParseImport(data)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	now := time.Now()
+	entries := make([]models.LibraryEntry, 0, len(parsed.Entries))
+	for _, e := range parsed.Entries {
+		title := e.Title
+		if title == "" {
+			title = fmt.Sprintf("ID %d", e.AnimeID)
+		}
+
+		entry := models.LibraryEntry{
+			AnimeID: e.AnimeID,
+			Title:   utils.TruncateRunes(title, maxTitleLength),
+			Note:    utils.TruncateRunes(e.Note, maxNoteLength),
+		}
+		if e.Favorite {
+			entry.AddedAt = timeOrNow(e.AddedAt, now)
+		}
+		if e.Score > 0 {
+			score := e.Score
+			entry.Score = &score
+			entry.RatedAt = timeOrNow(e.RatedAt, now)
+		}
+		entries = append(entries, entry)
+	}
+
+	result, err := s.repository.ImportLibraryContext(context.Background(), userID, entries)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+	result.Skipped += parsed.Skipped
+	return result, nil
+}
+
+func timeOrNow(t *time.Time, now time.Time) *time.Time {
+	if t != nil {
+		// stored like the bot's own timestamps, as the server's wall clock
+		local := t.In(now.Location())
+		return &local
+	}
+	return &now
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/library"
+)
+
+func TestImportLibrary_AddsParserSkips(t *testing.T) {
+	service, mock := newTestService(t)
+
+	data := `[
+		{"target_title": "Death Note", "target_id": 1535, "target_type": "Anime", "score": 9, "status": "completed"},
+		{"target_title": "Berserk", "target_id": 2, "target_type": "Manga", "score": 10, "status": "completed"}
+	]`
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM favorites`)).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id, score FROM ratings`)).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id", "score"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT anime_id FROM notes`)).
+		WillReturnRows(sqlmock.NewRows([]string{"anime_id"}))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO favorites`)).
+		ExpectExec().
+		WithArgs(int64(123), 1535, "Death Note", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO ratings`)).
+		ExpectExec().
+		WithArgs(int64(123), 1535, 9, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := service.ImportLibrary(123, []byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Imported != 1 || result.Skipped != 1 || result.Conflicts != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestImportLibrary_UnknownFormat(t *testing.T) {
+	service, _ := newTestService(t)
+
+	_, err := service.ImportLibrary(123, []byte("not an export"))
+	if !errors.Is(err, library.ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// poster sizes as shikimori names them in the anime image
+const (
+	PosterOriginal = "original"
+	PosterPreview  = "preview"
+	PosterX96      = "x96"
+)
+
+type PosterSource struct {
+	Size string
+	Path string
+}
+
+// PosterSources lists the anime's posters from best to worst; anime without a
+// poster point at shikimori's missing_*.jpg stubs, those are skipped
+func PosterSources(image models.AnimeImage) []PosterSource {
+	var sources []PosterSource
+	for _, source := range []PosterSource{
+		{PosterOriginal, image.Original},
+		{PosterPreview, image.Preview},
+		{PosterX96, image.X96},
+	} {
+		if source.Path == "" || strings.Contains(source.Path, "/missing_") {
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// FetchPoster downloads the poster so it can be uploaded instead of letting
+// telegram fetch the url, which shikimori sometimes refuses
+func (s *AnimeService) FetchPoster(path string) ([]byte, error) {
+	return s.shikimoriClient.FetchImage(path)
+}
+
+// PosterFileID returns the telegram file_id of the poster uploaded from path,
+// "" when it was never uploaded or the anime got a new poster since
+func (s *AnimeService) PosterFileID(animeID int, size string, path string) (string, error) {
+	file, err := s.repository.GetPosterFileContext(context.Background(), animeID, size)
+	if errors.Is(err, database.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if file.Path != path {
+		return "", nil
+	}
+	return file.FileID, nil
+}
+
+func (s *AnimeService) SavePosterFileID(animeID int, size string, path string, fileID string) error {
+	return s.repository.SavePosterFileContext(context.Background(), models.PosterFile{
+		AnimeID: animeID,
+		Size:    size,
+		Path:    path,
+		FileID:  fileID,
+	})
+}
+
+// ForgetPosterFileID drops a file_id telegram no longer accepts
+func (s *AnimeService) ForgetPosterFileID(animeID int, size string) error {
+	return s.repository.DeletePosterFileContext(context.Background(), animeID, size)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestPosterSources(t *testing.T) {
+	sources := PosterSources(models.AnimeImage{
+		Original: "/assets/globals/missing_original.jpg",
+		Preview:  "/system/animes/preview/1.jpg",
+		X96:      "/system/animes/x96/1.jpg",
+	})
+	want := []PosterSource{
+		{PosterPreview, "/system/animes/preview/1.jpg"},
+		{PosterX96, "/system/animes/x96/1.jpg"},
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("expected %v, got %v", want, sources)
+	}
+
+	if sources := PosterSources(models.AnimeImage{}); len(sources) != 0 {
+		t.Errorf("expected no sources without images, got %v", sources)
+	}
+}
+
+func TestPosterFileID(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	if fileID, err := service.PosterFileID(1, PosterOriginal, "/a.jpg"); fileID != "" || err != nil {
+		t.Fatalf("expected no file_id before the first upload, got %q (%v)", fileID, err)
+	}
+
+	if err := service.SavePosterFileID(1, PosterOriginal, "/a.jpg", "file-a"); err != nil {
+		t.Fatalf("failed to save file_id: %v", err)
+	}
+	if fileID, _ := service.PosterFileID(1, PosterOriginal, "/a.jpg"); fileID != "file-a" {
+		t.Errorf("expected the cached file_id, got %q", fileID)
+	}
+	if fileID, _ := service.PosterFileID(1, PosterPreview, "/a.jpg"); fileID != "" {
+		t.Errorf("expected sizes cached separately, got %q", fileID)
+	}
+	// shikimori replaced the poster
+	if fileID, _ := service.PosterFileID(1, PosterOriginal, "/b.jpg"); fileID != "" {
+		t.Errorf("expected a stale file_id ignored, got %q", fileID)
+	}
+
+	if err := service.SavePosterFileID(1, PosterOriginal, "/b.jpg", "file-b"); err != nil {
+		t.Fatalf("failed to replace file_id: %v", err)
+	}
+	if fileID, _ := service.PosterFileID(1, PosterOriginal, "/b.jpg"); fileID != "file-b" {
+		t.Errorf("expected the new file_id, got %q", fileID)
+	}
+
+	if err := service.ForgetPosterFileID(1, PosterOriginal); err != nil {
+		t.Fatalf("failed to forget file_id: %v", err)
+	}
+	if fileID, _ := service.PosterFileID(1, PosterOriginal, "/b.jpg"); fileID != "" {
+		t.Errorf("expected the file_id forgotten, got %q", fileID)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/recommend"
+)
+
+const (
+	RecommendLimit = 5
+
+	recommendSeeds        = 3
+	recommendGenres       = 2
+	recommendTopLimit     = 20
+	recommendCatalogLimit = 50
+)
+
+var ErrNotEnoughTaste = errors.New("not enough ratings or favorites to recommend from")
+
+// Recommend ranks titles from the local catalog and Shikimori's similar and top
+// lists; Shikimori is best effort, so an outage only narrows the choice
+func (s *AnimeService) Recommend(userID int64, limit int) ([]recommend.Pick, error) {
+	ctx := context.Background()
+
+	signals, err := s.repository.GetTasteSignalsContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile := recommend.BuildProfile(signals)
+	seeds := profile.Seeds(recommendSeeds)
+	if len(seeds) == 0 {
+		return nil, ErrNotEnoughTaste
+	}
+
+	topGenres := profile.TopGenres(recommendGenres)
+	catalog, err := s.repository.GetCatalogCandidatesContext(ctx, userID, topGenres, recommendCatalogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []recommend.Candidate
+	for _, anime := range catalog {
+		candidates = append(candidates, recommend.Candidate{Anime: anime})
+	}
+
+	for i := range seeds {
+		similar, err := s.shikimoriClient.GetSimilarAnime(seeds[i].AnimeID)
+		if err != nil {
+			continue
+		}
+		for _, anime := range similar {
+			candidates = append(candidates, recommend.Candidate{Anime: anime, SimilarTo: &seeds[i]})
+		}
+	}
+
+	for _, genreID := range topGenres {
+		top, err := s.shikimoriClient.GetTopAnime(genreID, recommendTopLimit)
+		if err != nil {
+			continue
+		}
+		// list entries come without genres, but they were filtered by this one
+		genre, _ := profile.Genre(genreID)
+		for _, anime := range top {
+			if len(anime.Genres) == 0 {
+				anime.Genres = []models.Genre{genre}
+			}
+			candidates = append(candidates, recommend.Candidate{Anime: anime})
+		}
+	}
+
+	return recommend.Rank(profile, candidates, limit), nil
+}
+
+func (s *AnimeService) SaveRecommendationFeedback(userID int64, animeID int, liked bool) error {
+	return s.repository.SaveRecommendationFeedbackContext(context.Background(), userID, animeID, liked)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestRecommend_NotEnoughTaste(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	if _, err := service.Recommend(1, RecommendLimit); !errors.Is(err, ErrNotEnoughTaste) {
+		t.Fatalf("expected ErrNotEnoughTaste, got %v", err)
+	}
+}
+
+func TestRecommend_ShikimoriCandidates(t *testing.T) {
+	service, shikimoriMock := newSQLiteTestService(t)
+
+	scifi := models.Genre{ID: 24, Name: "Sci-Fi", Russian: "Фантастика"}
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		return &models.Anime{ID: id, Name: "Steins;Gate", Genres: []models.Genre{scifi}}, nil
+	}
+	shikimoriMock.getSimilarFunc = func(id int) ([]models.Anime, error) {
+		return []models.Anime{{ID: 9253}, {ID: 30484, Name: "Steins;Gate 0"}}, nil
+	}
+	shikimoriMock.getTopFunc = func(genreID int, limit int) ([]models.Anime, error) {
+		if genreID != scifi.ID {
+			t.Errorf("expected the top list for the liked genre, got %d", genreID)
+		}
+		return nil, errors.New("shikimori is down")
+	}
+
+	if err := service.EnsureUserExists(1, "u"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	// caches the title with its genres in the catalog
+	if _, err := service.GetAnimeByID(9253); err != nil {
+		t.Fatalf("failed to load anime: %v", err)
+	}
+	if err := service.AddRating(1, 9253, 10); err != nil {
+		t.Fatalf("failed to rate: %v", err)
+	}
+
+	picks, err := service.Recommend(1, RecommendLimit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(picks) != 1 || picks[0].Anime.ID != 30484 || picks[0].Reason.Signal.AnimeID != 9253 {
+		t.Fatalf("unexpected picks: %+v", picks)
+	}
+
+	if err := service.SaveRecommendationFeedback(1, 30484, false); err != nil {
+		t.Fatalf("failed to save feedback: %v", err)
+	}
+	picks, err = service.Recommend(1, RecommendLimit)
+	if err != nil || len(picks) != 0 {
+		t.Errorf("expected disliked picks to disappear, got %+v (%v)", picks, err)
+	}
+}
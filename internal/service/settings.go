@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const DefaultPageSize = 10
+
+var ErrInvalidSetting = errors.New("invalid setting")
+
+// PageSizes are the choices of results per page offered in /settings
+var PageSizes = []int{5, 10, 20}
+
+var TitleDisplays = []string{
+	models.TitleDisplayAuto,
+	models.TitleDisplayBoth,
+	models.TitleDisplayRomaji,
+	models.TitleDisplayRussian,
+}
+
+// TimeZones are offered as buttons, any other IANA zone can be typed in
+var TimeZones = []string{
+	"",
+	"Europe/Kaliningrad",
+	"Europe/Moscow",
+	"Europe/Samara",
+	"Asia/Yekaterinburg",
+	"Asia/Novosibirsk",
+	"Asia/Vladivostok",
+	"Europe/Berlin",
+	"Europe/London",
+	"America/New_York",
+	"America/Los_Angeles",
+	"Asia/Tokyo",
+}
+
+func DefaultSettings(userID int64) models.UserSettings {
+	return models.UserSettings{
+		UserID:        userID,
+		TitleDisplay:  models.TitleDisplayAuto,
+		HideNSFW:      true,
+		NotifyFollows: true,
+		NotifyWrapped: true,
+		PageSize:      DefaultPageSize,
+	}
+}
+
+func validateSettings(settings models.UserSettings) error {
+	if settings.Language != "" {
+		if _, ok := i18n.Parse(settings.Language); !ok {
+			return fmt.Errorf("language %q: %w", settings.Language, ErrInvalidSetting)
+		}
+	}
+	if !slices.Contains(TitleDisplays, settings.TitleDisplay) {
+		return fmt.Errorf("title display %q: %w", settings.TitleDisplay, ErrInvalidSetting)
+	}
+	if !slices.Contains(PageSizes, settings.PageSize) {
+		return fmt.Errorf("page size %d: %w", settings.PageSize, ErrInvalidSetting)
+	}
+	if _, err := time.LoadLocation(settings.TimeZone); err != nil {
+		return fmt.Errorf("time zone %q: %w", settings.TimeZone, ErrInvalidSetting)
+	}
+	return nil
+}
+
+// GetSettings returns the defaults for users who never opened /settings
+func (s *AnimeService) GetSettings(userID int64) (models.UserSettings, error) {
+	settings, err := s.repository.GetUserSettingsContext(context.Background(), userID)
+	if errors.Is(err, database.ErrNotFound) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return DefaultSettings(userID), err
+	}
+	return *settings, nil
+}
+
+func (s *AnimeService) SaveSettings(settings models.UserSettings) error {
+	if err := validateSettings(settings); err != nil {
+		return err
+	}
+	return s.repository.SaveUserSettingsContext(context.Background(), settings)
+}
+
+// UserLocation is the zone dates are shown and periods are counted in
+func (s *AnimeService) UserLocation(userID int64) *time.Location {
+	settings, err := s.GetSettings(userID)
+	if err != nil || settings.TimeZone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestSettingsDefaultsAndSave(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+	if err := service.EnsureUserExists(1, "alice"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	settings, err := service.GetSettings(1)
+	if err != nil || settings != DefaultSettings(1) {
+		t.Fatalf("expected defaults, got %+v (%v)", settings, err)
+	}
+
+	settings.TitleDisplay = models.TitleDisplayBoth
+	settings.TimeZone = "Asia/Tokyo"
+	settings.HideNSFW = false
+	settings.PageSize = 5
+	if err := service.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+	if stored, err := service.GetSettings(1); err != nil || stored != settings {
+		t.Errorf("expected %+v, got %+v (%v)", settings, stored, err)
+	}
+	if loc := service.UserLocation(1); loc.String() != "Asia/Tokyo" {
+		t.Errorf("expected Asia/Tokyo, got %s", loc)
+	}
+	if loc := service.UserLocation(2); loc != time.Local {
+		t.Errorf("expected the server zone for a user without settings, got %s", loc)
+	}
+
+	invalid := []func(*models.UserSettings){
+		func(s *models.UserSettings) { s.TimeZone = "Mars/Olympus" },
+		func(s *models.UserSettings) { s.PageSize = 7 },
+		func(s *models.UserSettings) { s.TitleDisplay = "kanji" },
+		func(s *models.UserSettings) { s.Language = "xx" },
+	}
+	for i, change := range invalid {
+		broken := settings
+		change(&broken)
+		if err := service.SaveSettings(broken); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("case %d: expected ErrInvalidSetting, got %v", i, err)
+		}
+	}
+}
+
+func TestSearchAnimeForAppliesSettings(t *testing.T) {
+	service, shikimoriMock := newSQLiteTestService(t)
+	if err := service.EnsureUserExists(1, "alice"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	var gotLimit int
+	var gotCensored bool
+	shikimoriMock.searchAnimeFunc = func(query string, limit int, censored bool) ([]models.Anime, error) {
+		gotLimit, gotCensored = limit, censored
+		return []models.Anime{
+			{ID: 1, Name: "Monster", Description: "d"},
+			{ID: 2, Name: "Lewd", Description: "d", Genres: []models.Genre{{Name: "Hentai"}}},
+		}, nil
+	}
+
+	result, err := service.SearchAnimeFor(1, "m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotLimit != DefaultPageSize || !gotCensored || len(result) != 1 || result[0].ID != 1 {
+		t.Errorf("expected censored search of %d, got limit %d censored %t: %+v", DefaultPageSize, gotLimit, gotCensored, result)
+	}
+
+	settings := DefaultSettings(1)
+	settings.HideNSFW = false
+	settings.PageSize = 20
+	if err := service.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+	result, err = service.SearchAnimeFor(1, "m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotLimit != 20 || gotCensored || len(result) != 2 {
+		t.Errorf("expected uncensored search of 20, got limit %d censored %t: %+v", gotLimit, gotCensored, result)
+	}
+}
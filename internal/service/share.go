@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+var (
+	ErrListHidden             = errors.New("list is hidden")
+	ErrSharedCollectionAbsent = errors.New("shared collection not found")
+)
+
+// sharedListOwner resolves a share link's friend code to a user who still
+// shows their list to others
+func (s *AnimeService) sharedListOwner(ctx context.Context, code string) (*models.User, error) {
+	owner, err := s.repository.GetUserByFriendCodeContext(ctx, strings.ToLower(strings.TrimSpace(code)))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrUnknownFriendCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.repository.GetShareListContext(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !share {
+		return nil, ErrListHidden
+	}
+	return owner, nil
+}
+
+// GetSharedFavorites returns the favorites behind a list share link
+func (s *AnimeService) GetSharedFavorites(code string) (*models.User, []models.Favorite, error) {
+	ctx := context.Background()
+	owner, err := s.sharedListOwner(ctx, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	favorites, err := s.repository.GetFavoritesContext(ctx, owner.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return owner, favorites, nil
+}
+
+// GetSharedCollection returns a collection behind a share link. The link
+// carries the owner's code, so collection ids alone can't be guessed
+func (s *AnimeService) GetSharedCollection(code string, collectionID int) (*models.User, *models.Collection, []models.CollectionItem, error) {
+	ctx := context.Background()
+	owner, err := s.sharedListOwner(ctx, code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	collection, err := s.repository.GetCollectionContext(ctx, owner.ID, collectionID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil, nil, fmt.Errorf("collection %d: %w", collectionID, ErrSharedCollectionAbsent)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	items, err := s.repository.GetCollectionItemsContext(ctx, collectionID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return owner, collection, items, nil
+}
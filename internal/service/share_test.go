@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestSharedLists(t *testing.T) {
+	service, shikimoriMock := newSQLiteTestService(t)
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		return &models.Anime{ID: id, Name: "Mushishi"}, nil
+	}
+
+	if err := service.EnsureUserExists(1, "alice"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := service.AddToFavorites(1, models.Anime{ID: 5, Name: "Mushishi"}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	collection, err := service.CreateCollection(1, "Осень")
+	if err != nil {
+		t.Fatalf("failed to create collection: %v", err)
+	}
+	if err := service.AddToCollection(1, collection.ID, 5); err != nil {
+		t.Fatalf("failed to add to collection: %v", err)
+	}
+	code, err := service.GetFriendCode(1)
+	if err != nil {
+		t.Fatalf("failed to get code: %v", err)
+	}
+
+	owner, favorites, err := service.GetSharedFavorites(" " + code + " ")
+	if err != nil || owner.ID != 1 || len(favorites) != 1 || favorites[0].AnimeID != 5 {
+		t.Fatalf("expected alice's favorites, got %+v %+v (%v)", owner, favorites, err)
+	}
+	_, shared, items, err := service.GetSharedCollection(code, collection.ID)
+	if err != nil || shared.Name != "Осень" || len(items) != 1 {
+		t.Fatalf("expected the shared collection, got %+v %+v (%v)", shared, items, err)
+	}
+
+	if _, _, _, err := service.GetSharedCollection(code, collection.ID+1); !errors.Is(err, ErrSharedCollectionAbsent) {
+		t.Errorf("expected ErrSharedCollectionAbsent, got %v", err)
+	}
+	if _, _, err := service.GetSharedFavorites("zzzzzzzz"); !errors.Is(err, ErrUnknownFriendCode) {
+		t.Errorf("expected ErrUnknownFriendCode, got %v", err)
+	}
+
+	if err := service.SetShareList(1, false); err != nil {
+		t.Fatalf("failed to hide list: %v", err)
+	}
+	if _, _, err := service.GetSharedFavorites(code); !errors.Is(err, ErrListHidden) {
+		t.Errorf("expected ErrListHidden, got %v", err)
+	}
+	if _, _, _, err := service.GetSharedCollection(code, collection.ID); !errors.Is(err, ErrListHidden) {
+		t.Errorf("expected ErrListHidden for the collection, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/similarity"
+)
+
+const AlsoLikedLimit = 3
+
+// RefreshSimilarity rebuilds neighbour lists for anime touched by rating changes
+// since the last run and returns how many lists were rewritten. Only the writes
+// are incremental: the model still needs every rating to centre users on their
+// means, so each run with dirty anime loads the whole ratings table
+func (s *AnimeService) RefreshSimilarity(ctx context.Context) (int, error) {
+	dirty, err := s.repository.GetSimilarityDirtyContext(ctx)
+	if err != nil || len(dirty) == 0 {
+		return 0, err
+	}
+
+	changed := make([]int, 0, len(dirty))
+	for animeID := range dirty {
+		changed = append(changed, animeID)
+	}
+	// titles that lost their last rating drop out of the model, so find the
+	// lists still pointing at them through the stored neighbours
+	owners, err := s.repository.GetNeighborOwnersContext(ctx, changed)
+	if err != nil {
+		return 0, err
+	}
+
+	ratings, err := s.repository.GetAllRatingsContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	model := similarity.NewModel(ratings)
+	affected := model.Affected(append(changed, owners...))
+
+	err = s.repository.WithTx(ctx, func(tx database.Repo) error {
+		for _, animeID := range affected {
+			neighbors := model.Neighbors(animeID, similarity.TopNeighbors)
+			if err := tx.ReplaceAnimeNeighborsContext(ctx, animeID, neighbors); err != nil {
+				return err
+			}
+		}
+		return tx.ClearSimilarityDirtyContext(ctx, dirty)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(affected), nil
+}
+
+func (s *AnimeService) GetAlsoLiked(animeID int) ([]models.AnimeNeighbor, error) {
+	return s.repository.GetAnimeNeighborsContext(context.Background(), animeID, AlsoLikedLimit)
+}
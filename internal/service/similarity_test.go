@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/similarity"
+)
+
+func TestRefreshSimilarity_Incremental(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+	ctx := context.Background()
+
+	if err := service.repository.UpsertAnimeContext(ctx, models.Anime{ID: 2, Name: "FMA", Russian: "Стальной алхимик"}, time.Now()); err != nil {
+		t.Fatalf("failed to upsert anime: %v", err)
+	}
+	rate := func(userID int64, animeID, score int) {
+		t.Helper()
+		if err := service.AddRating(userID, animeID, score); err != nil {
+			t.Fatalf("failed to rate: %v", err)
+		}
+	}
+	for userID := int64(1); userID <= 4; userID++ {
+		if err := service.EnsureUserExists(userID, "u"); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		bias := int(userID % 2)
+		rate(userID, 1, 9+bias)
+		rate(userID, 2, 8+bias)
+		rate(userID, 3, 2)
+	}
+
+	updated, err := service.RefreshSimilarity(ctx)
+	if err != nil || updated != 3 {
+		t.Fatalf("expected 3 lists on first run, got %d (%v)", updated, err)
+	}
+	alsoLiked, err := service.GetAlsoLiked(1)
+	if err != nil || len(alsoLiked) != 1 || alsoLiked[0].NeighborID != 2 || alsoLiked[0].Title != "Стальной алхимик" {
+		t.Fatalf("expected anime 2 as the neighbor of 1, got %+v (%v)", alsoLiked, err)
+	}
+
+	if updated, err := service.RefreshSimilarity(ctx); err != nil || updated != 0 {
+		t.Errorf("expected nothing to do without changes, got %d (%v)", updated, err)
+	}
+
+	// raters of unrelated titles leave existing lists alone
+	if err := service.EnsureUserExists(5, "u"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	rate(5, 50, 8)
+	rate(5, 51, 6)
+	if updated, err := service.RefreshSimilarity(ctx); err != nil || updated != 2 {
+		t.Errorf("expected only the new titles, got %d (%v)", updated, err)
+	}
+
+	for userID := int64(1); userID <= 4; userID++ {
+		if err := service.DeleteRating(userID, 2); err != nil {
+			t.Fatalf("failed to delete rating: %v", err)
+		}
+	}
+	if _, err := service.RefreshSimilarity(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if alsoLiked, err := service.GetAlsoLiked(1); err != nil || len(alsoLiked) != 0 {
+		t.Errorf("expected stale neighbor removed, got %+v (%v)", alsoLiked, err)
+	}
+}
+
+// deleting a user's only rating of a title moves their mean, so their other
+// titles must be rebuilt even though the user is gone from the deleted one
+func TestRefreshSimilarity_DeletedRatingShiftsMean(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+	ctx := context.Background()
+
+	rate := func(userID int64, animeID, score int) {
+		t.Helper()
+		if err := service.AddRating(userID, animeID, score); err != nil {
+			t.Fatalf("failed to rate: %v", err)
+		}
+	}
+	for userID := int64(1); userID <= 3; userID++ {
+		if err := service.EnsureUserExists(userID, "u"); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	rate(1, 1, 9)
+	rate(1, 2, 6)
+	rate(1, 3, 8)
+	rate(1, 4, 1)
+	rate(2, 1, 8)
+	rate(2, 2, 7)
+	rate(2, 3, 4)
+	rate(3, 1, 9)
+	rate(3, 2, 8)
+	rate(3, 3, 3)
+
+	if _, err := service.RefreshSimilarity(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if err := service.DeleteRating(1, 4); err != nil {
+		t.Fatalf("failed to delete rating: %v", err)
+	}
+	if _, err := service.RefreshSimilarity(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+
+	ratings, err := service.repository.GetAllRatingsContext(ctx)
+	if err != nil {
+		t.Fatalf("failed to get ratings: %v", err)
+	}
+	model := similarity.NewModel(ratings)
+	for animeID := 1; animeID <= 3; animeID++ {
+		want := model.Neighbors(animeID, similarity.TopNeighbors)
+		got, err := service.repository.GetAnimeNeighborsContext(ctx, animeID, similarity.TopNeighbors)
+		if err != nil {
+			t.Fatalf("failed to get neighbors: %v", err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("anime %d: expected %d neighbors, got %+v", animeID, len(want), got)
+		}
+		for i := range want {
+			if got[i].NeighborID != want[i].NeighborID || math.Abs(got[i].Similarity-want[i].Similarity) > 1e-9 {
+				t.Errorf("anime %d: expected %+v, got %+v", animeID, want[i], got[i])
+			}
+		}
+	}
+}
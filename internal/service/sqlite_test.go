@@ -0,0 +1,92 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/logger"
+)
+
+func newSQLiteTestService(t *testing.T) (*AnimeService, *mockShikimoriClient) {
+	db, err := database.Connect("sqlite://"+filepath.Join(t.TempDir(), "bot.db"), logger.New())
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Migrate("up"); err != nil {
+		t.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	shikimoriMock := &mockShikimoriClient{}
+	service := &AnimeService{
+		shikimoriClient: shikimoriMock,
+		repository:      database.NewRepository(db),
+	}
+	return service, shikimoriMock
+}
+
+func TestSQLite_LibraryFlow(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	if err := service.EnsureUserExists(123, "test"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	anime := models.Anime{ID: 1, Name: "Death Note", Russian: "Тетрадь смерти"}
+	if err := service.AddToFavorites(123, anime); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	if err := service.AddRating(123, 1, 9); err != nil {
+		t.Fatalf("failed to rate: %v", err)
+	}
+	if err := service.SaveNote(123, 1, "  rewatch  "); err != nil {
+		t.Fatalf("failed to save note: %v", err)
+	}
+
+	lib, err := service.GetLibrary(123)
+	if err != nil {
+		t.Fatalf("failed to get library: %v", err)
+	}
+	if len(lib.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(lib.Entries))
+	}
+	entry := lib.Entries[0]
+	if !entry.Favorite || entry.Score != 9 || entry.Note != "rewatch" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	deleted, err := service.DeleteUserData(123, DeletionSourceCLI)
+	if err != nil || !deleted {
+		t.Fatalf("expected user to be deleted, got %v (%v)", deleted, err)
+	}
+	isFav, _ := service.IsFavorite(123, 1)
+	if isFav {
+		t.Error("expected favorites to be removed with the user")
+	}
+}
+
+func TestSQLite_GetAnimeByIDUsesCatalog(t *testing.T) {
+	service, shikimoriMock := newSQLiteTestService(t)
+
+	calls := 0
+	shikimoriMock.getAnimeFunc = func(id int) (*models.Anime, error) {
+		calls++
+		return &models.Anime{ID: id, Name: "Naruto", Genres: []models.Genre{{ID: 1, Name: "Action"}}}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		anime, err := service.GetAnimeByID(20)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if anime.Name != "Naruto" || len(anime.Genres) != 1 {
+			t.Errorf("unexpected anime: %+v", anime)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected the second lookup to hit the catalog, got %d API calls", calls)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+// statsBackfillLimit caps the shikimori requests one /stats call makes, the
+// rest of the titles are filled on the next calls
+const statsBackfillLimit = 20
+
+func (s *AnimeService) GetUserStats(userID int64, period stats.Period) (stats.Summary, error) {
+	ctx := context.Background()
+	q := database.StatsQuery{From: period.From, To: period.To}
+
+	entries, err := s.repository.GetStatsEntriesContext(ctx, userID, q)
+	if err != nil {
+		return stats.Summary{}, err
+	}
+	if len(entries) == 0 {
+		return stats.Summary{}, nil
+	}
+
+	if s.backfillCatalog(ctx, entries) > 0 {
+		entries, err = s.repository.GetStatsEntriesContext(ctx, userID, q)
+		if err != nil {
+			return stats.Summary{}, err
+		}
+	}
+
+	genres, err := s.repository.CountStatsGenresContext(ctx, userID, q, stats.TopGenres)
+	if err != nil {
+		return stats.Summary{}, err
+	}
+	return stats.Compute(entries, genres), nil
+}
+
+// backfillCatalog snapshots titles favorited before the catalog existed or
+// imported from a file, so their kind and runtime count. It returns how many
+// snapshots were added
+func (s *AnimeService) backfillCatalog(ctx context.Context, entries []models.StatsEntry) int {
+	filled, tried := 0, 0
+	for _, entry := range entries {
+		if entry.Cataloged {
+			continue
+		}
+		if tried == statsBackfillLimit {
+			break
+		}
+		tried++
+
+		anime, err := s.shikimoriClient.GetAnimeById(entry.AnimeID)
+		if err != nil {
+			continue
+		}
+		if err := s.repository.UpsertAnimeContext(ctx, *anime, time.Now()); err != nil {
+			continue
+		}
+		filled++
+	}
+	return filled
+}
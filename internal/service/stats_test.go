@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+func TestGetUserStats(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	summary, err := service.GetUserStats(1, stats.Period{Name: stats.PeriodAll})
+	if err != nil || summary.Titles != 0 {
+		t.Fatalf("expected empty stats, got %+v (%v)", summary, err)
+	}
+
+	if err := service.EnsureUserExists(1, "u"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := service.AddRating(1, 1, 8); err != nil {
+		t.Fatalf("failed to rate: %v", err)
+	}
+	if err := service.AddRating(1, 2, 6); err != nil {
+		t.Fatalf("failed to rate: %v", err)
+	}
+
+	summary, err = service.GetUserStats(1, stats.Period{Name: stats.PeriodAll})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Titles != 2 || summary.Rated != 2 || summary.AverageScore != 7 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	future := stats.Period{Name: "future", From: time.Now().Add(time.Hour)}
+	summary, err = service.GetUserStats(1, future)
+	if err != nil || summary.Titles != 0 {
+		t.Errorf("expected nothing in a future period, got %+v (%v)", summary, err)
+	}
+}
+
+func TestGetUserStats_BackfillsCatalog(t *testing.T) {
+	service, client := newSQLiteTestService(t)
+
+	if err := service.EnsureUserExists(1, "u"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	// ratings imported from a file have no catalog snapshot
+	for id, score := range map[int]int{1: 8, 2: 6} {
+		if err := service.AddRating(1, id, score); err != nil {
+			t.Fatalf("failed to rate: %v", err)
+		}
+	}
+
+	client.getAnimeFunc = func(id int) (*models.Anime, error) {
+		if id == 2 {
+			return nil, errors.New("shikimori is down")
+		}
+		return &models.Anime{ID: id, Name: "Anime", Kind: "tv", Episodes: 12, Duration: 24}, nil
+	}
+
+	summary, err := service.GetUserStats(1, stats.Period{Name: stats.PeriodAll})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.MinutesWatched != 12*24 {
+		t.Errorf("expected the backfilled title to count, got %d minutes", summary.MinutesWatched)
+	}
+	if summary.Uncataloged != 1 {
+		t.Errorf("expected 1 uncataloged title, got %d", summary.Uncataloged)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+const (
+	VoteDefaultOptions = 4
+	VoteMinOptions     = 2
+	// Telegram allows at most 10 poll options
+	VoteMaxOptions = 10
+
+	// telegram rejects longer poll options
+	voteOptionMaxLength = 100
+	// polls don't close by themselves, so an abandoned one stops blocking
+	// new votes after a day
+	voteSessionTTL = 24 * time.Hour
+)
+
+var (
+	ErrNotEnoughVoteCandidates = errors.New("not enough vote candidates")
+	ErrVoteInProgress          = errors.New("vote already in progress")
+)
+
+// VoteSource narrows where /vote takes its candidates from
+type VoteSource int
+
+const (
+	VoteSourceAny VoteSource = iota
+	VoteSourceWatchlist
+	VoteSourceFavorites
+)
+
+// VoteResult is the outcome of a finished poll; Winner is nil when nobody voted
+type VoteResult struct {
+	Session *models.VoteSession
+	Winner  *models.VoteOption
+	Votes   int
+}
+
+// pickVoteCandidates takes the chat's list in random order first and tops it up
+// with members' favorites, skipping titles already picked
+func pickVoteCandidates(watchlist []models.VoteOption, favorites []models.VoteOption, n int, rng *rand.Rand) []models.VoteOption {
+	shuffled := append([]models.VoteOption(nil), watchlist...)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	picked := make([]models.VoteOption, 0, n)
+	seen := make(map[int]bool)
+	for _, option := range append(shuffled, favorites...) {
+		if len(picked) == n {
+			break
+		}
+		if seen[option.AnimeID] {
+			continue
+		}
+		seen[option.AnimeID] = true
+		option.Title = utils.TruncateRunes(option.Title, voteOptionMaxLength)
+		picked = append(picked, option)
+	}
+	return picked
+}
+
+// pickVoteWinner returns the index of the option with most votes, breaking ties
+// at random, or -1 when nobody voted
+func pickVoteWinner(counts []int, rng *rand.Rand) int {
+	best := 0
+	var leaders []int
+	for i, count := range counts {
+		switch {
+		case count > best:
+			best = count
+			leaders = []int{i}
+		case count == best && count > 0:
+			leaders = append(leaders, i)
+		}
+	}
+	if len(leaders) == 0 {
+		return -1
+	}
+	return leaders[rng.IntN(len(leaders))]
+}
+
+// PrepareVote picks up to n candidates for a chat poll
+func (s *AnimeService) PrepareVote(chatID int64, n int, source VoteSource) ([]models.VoteOption, error) {
+	ctx := context.Background()
+
+	open, err := s.repository.GetOpenVoteSessionContext(ctx, chatID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, err
+	}
+	if open != nil && time.Since(open.CreatedAt) < voteSessionTTL {
+		return nil, ErrVoteInProgress
+	}
+
+	n = max(VoteMinOptions, min(n, VoteMaxOptions))
+
+	var watchlist []models.VoteOption
+	if source != VoteSourceFavorites {
+		items, err := s.repository.GetChatWatchlistContext(ctx, chatID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			watchlist = append(watchlist, models.VoteOption{AnimeID: item.AnimeID, Title: item.Title})
+		}
+	}
+
+	var favorites []models.VoteOption
+	if source != VoteSourceWatchlist {
+		favorites, err = s.repository.GetMemberFavoritesContext(ctx, chatID, n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options := pickVoteCandidates(watchlist, favorites, n, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+	if len(options) < VoteMinOptions {
+		return nil, ErrNotEnoughVoteCandidates
+	}
+	return options, nil
+}
+
+// StartVote remembers a poll the bot has just posted
+func (s *AnimeService) StartVote(pollID string, chatID int64, messageID int, startedBy int64, options []models.VoteOption) error {
+	session := models.VoteSession{
+		PollID:    pollID,
+		ChatID:    chatID,
+		MessageID: messageID,
+		StartedBy: &startedBy,
+		CreatedAt: time.Now(),
+		Options:   options,
+	}
+	return s.repository.CreateVoteSessionContext(context.Background(), session)
+}
+
+func (s *AnimeService) GetVoteSession(pollID string) (*models.VoteSession, error) {
+	session, err := s.repository.GetVoteSessionContext(context.Background(), pollID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return session, err
+}
+
+// FinishVote closes a poll given its per-option vote counts. It returns nil for
+// unknown polls and for polls that were already finished, so a winner is only
+// announced once
+func (s *AnimeService) FinishVote(pollID string, counts []int) (*VoteResult, error) {
+	ctx := context.Background()
+
+	session, err := s.repository.GetVoteSessionContext(ctx, pollID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.ClosedAt != nil {
+		return nil, nil
+	}
+
+	result := &VoteResult{Session: session}
+	var winnerID *int
+	if len(counts) > len(session.Options) {
+		counts = counts[:len(session.Options)]
+	}
+	if i := pickVoteWinner(counts, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))); i >= 0 {
+		result.Winner = &session.Options[i]
+		result.Votes = counts[i]
+		winnerID = &session.Options[i].AnimeID
+	}
+
+	closed, err := s.repository.CloseVoteSessionContext(ctx, pollID, winnerID)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (s *AnimeService) TouchChatMember(chatID int64, userID int64) error {
+	return s.repository.TouchChatMemberContext(context.Background(), chatID, userID)
+}
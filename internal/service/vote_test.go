@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestPickVoteCandidates(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	watchlist := []models.VoteOption{{AnimeID: 1, Title: "Monster"}, {AnimeID: 2, Title: "Mushishi"}}
+	favorites := []models.VoteOption{{AnimeID: 2, Title: "Mushishi"}, {AnimeID: 3, Title: "Bebop"}, {AnimeID: 4, Title: "Trigun"}}
+
+	picked := pickVoteCandidates(watchlist, favorites, 3, rng)
+	if len(picked) != 3 {
+		t.Fatalf("expected 3 candidates, got %+v", picked)
+	}
+	seen := map[int]bool{}
+	for _, option := range picked {
+		if seen[option.AnimeID] {
+			t.Errorf("duplicate candidate %d in %+v", option.AnimeID, picked)
+		}
+		seen[option.AnimeID] = true
+	}
+	if !seen[1] || !seen[2] || !seen[3] {
+		t.Errorf("expected the watchlist first, then the most liked favorite, got %+v", picked)
+	}
+
+	long := []models.VoteOption{{AnimeID: 5, Title: string(make([]rune, 150))}}
+	if got := pickVoteCandidates(long, nil, 2, rng); len([]rune(got[0].Title)) > voteOptionMaxLength {
+		t.Errorf("expected the title truncated to %d runes, got %d", voteOptionMaxLength, len([]rune(got[0].Title)))
+	}
+}
+
+func TestPickVoteWinner(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	if got := pickVoteWinner([]int{0, 0, 0}, rng); got != -1 {
+		t.Errorf("expected no winner without votes, got %d", got)
+	}
+	if got := pickVoteWinner([]int{1, 3, 2}, rng); got != 1 {
+		t.Errorf("expected option 1, got %d", got)
+	}
+
+	wins := map[int]int{}
+	for range 100 {
+		wins[pickVoteWinner([]int{2, 0, 2}, rng)]++
+	}
+	if wins[1] != 0 || wins[0] == 0 || wins[2] == 0 {
+		t.Errorf("expected ties broken between options 0 and 2, got %v", wins)
+	}
+}
+
+func TestVoteFlow(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+	const chatID = int64(-42)
+
+	for _, id := range []int64{1, 2} {
+		if err := service.EnsureUserExists(id, "member"); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	if _, err := service.PrepareVote(chatID, 4, VoteSourceAny); !errors.Is(err, ErrNotEnoughVoteCandidates) {
+		t.Fatalf("expected ErrNotEnoughVoteCandidates for an empty chat, got %v", err)
+	}
+
+	if _, err := service.AddToChatWatchlist(chatID, 1, &models.Anime{ID: 5, Name: "Mushishi"}); err != nil {
+		t.Fatalf("failed to add to watchlist: %v", err)
+	}
+	if err := service.AddToFavorites(2, models.Anime{ID: 6, Name: "Monster"}); err != nil {
+		t.Fatalf("failed to add favorite: %v", err)
+	}
+	// favorites only count once their owner has shown up in the chat
+	if _, err := service.PrepareVote(chatID, 4, VoteSourceAny); !errors.Is(err, ErrNotEnoughVoteCandidates) {
+		t.Fatalf("expected ErrNotEnoughVoteCandidates before the member is seen, got %v", err)
+	}
+	if err := service.TouchChatMember(chatID, 2); err != nil {
+		t.Fatalf("failed to touch member: %v", err)
+	}
+
+	options, err := service.PrepareVote(chatID, 4, VoteSourceAny)
+	if err != nil || len(options) != 2 || options[0].AnimeID != 5 || options[1].AnimeID != 6 {
+		t.Fatalf("expected watchlist then favorites, got %+v (%v)", options, err)
+	}
+	if _, err := service.PrepareVote(chatID, 4, VoteSourceWatchlist); !errors.Is(err, ErrNotEnoughVoteCandidates) {
+		t.Errorf("expected the watchlist alone to be too short, got %v", err)
+	}
+
+	if err := service.StartVote("poll-1", chatID, 100, 1, options); err != nil {
+		t.Fatalf("failed to start vote: %v", err)
+	}
+	if _, err := service.PrepareVote(chatID, 4, VoteSourceAny); !errors.Is(err, ErrVoteInProgress) {
+		t.Errorf("expected ErrVoteInProgress, got %v", err)
+	}
+
+	result, err := service.FinishVote("poll-1", []int{1, 2})
+	if err != nil || result == nil || result.Winner == nil || result.Winner.AnimeID != 6 || result.Votes != 2 {
+		t.Fatalf("expected Monster to win with 2 votes, got %+v (%v)", result, err)
+	}
+	if result, err := service.FinishVote("poll-1", []int{1, 2}); result != nil || err != nil {
+		t.Errorf("expected the second finish to be a no-op, got %+v (%v)", result, err)
+	}
+	if result, err := service.FinishVote("unknown", nil); result != nil || err != nil {
+		t.Errorf("expected nil for an unknown poll, got %+v (%v)", result, err)
+	}
+
+	session, err := service.GetVoteSession("poll-1")
+	if err != nil || session == nil || session.ClosedAt == nil || session.WinnerAnimeID == nil || *session.WinnerAnimeID != 6 {
+		t.Errorf("expected a closed session with the winner, got %+v (%v)", session, err)
+	}
+	if _, err := service.PrepareVote(chatID, 4, VoteSourceAny); err != nil {
+		t.Errorf("expected a new vote after the last one closed, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+func (s *AnimeService) GetWrapped(userID int64, year int) (stats.Wrapped, error) {
+	ctx := context.Background()
+	period := stats.YearPeriod(year, s.UserLocation(userID))
+	q := database.StatsQuery{From: period.From, To: period.To}
+
+	events, err := s.repository.GetActivityContext(ctx, userID, q)
+	if err != nil {
+		return stats.Wrapped{}, err
+	}
+	if len(events) == 0 {
+		return stats.Wrapped{Year: year}, nil
+	}
+
+	genres, err := s.repository.CountStatsGenresContext(ctx, userID, q, 1)
+	if err != nil {
+		return stats.Wrapped{}, err
+	}
+	return stats.ComputeWrapped(year, events, genres), nil
+}
+
+// zones are at most 14 hours away from UTC
+const maxZoneOffset = 14 * time.Hour
+
+// GetWrappedRecipients lists users with activity during year in their own zone.
+// The query takes everyone active in the year widened to fit any zone, then
+// each candidate is checked against the year as their recap counts it
+func (s *AnimeService) GetWrappedRecipients(year int) ([]int64, error) {
+	ctx := context.Background()
+	period := stats.YearPeriod(year, time.UTC)
+
+	candidates, err := s.repository.GetWrappedRecipientsContext(ctx, year, period.From.Add(-maxZoneOffset), period.To.Add(maxZoneOffset))
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]int64, 0, len(candidates))
+	for _, userID := range candidates {
+		own := stats.YearPeriod(year, s.UserLocation(userID))
+		events, err := s.repository.GetActivityContext(ctx, userID, database.StatsQuery{From: own.From, To: own.To})
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			recipients = append(recipients, userID)
+		}
+	}
+	return recipients, nil
+}
+
+func (s *AnimeService) MarkWrappedSent(userID int64, year int) error {
+	return s.repository.MarkWrappedSentContext(context.Background(), userID, year)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGetWrappedRecipients_UserZones(t *testing.T) {
+	service, _ := newSQLiteTestService(t)
+
+	// both ratings fall on new year's night, on different sides of midnight
+	// depending on the zone
+	users := []struct {
+		id      int64
+		zone    string
+		ratedAt string
+	}{
+		{1, "Asia/Tokyo", "2024-12-31T20:00:00Z"},
+		{2, "America/Los_Angeles", "2025-01-01T03:00:00Z"},
+	}
+	for _, u := range users {
+		if err := service.EnsureUserExists(u.id, "u"); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		settings, _ := service.GetSettings(u.id)
+		settings.TimeZone = u.zone
+		if err := service.SaveSettings(settings); err != nil {
+			t.Fatalf("failed to save settings: %v", err)
+		}
+		data := fmt.Sprintf(`{"version":1,"entries":[{"anime_id":1,"title":"A","score":8,"rated_at":%q}]}`, u.ratedAt)
+		if _, err := service.ImportLibrary(u.id, []byte(data)); err != nil {
+			t.Fatalf("failed to import: %v", err)
+		}
+	}
+
+	for year, want := range map[int][]int64{2024: {2}, 2025: {1}} {
+		recipients, err := service.GetWrappedRecipients(year)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(recipients, want) {
+			t.Errorf("%d: expected %v, got %v", year, want, recipients)
+		}
+	}
+}
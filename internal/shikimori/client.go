@@ -4,16 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/ratelimit"
 )
 
+// MaxImageSize is telegram's limit for photos
+const MaxImageSize = 10 << 20
+
 type Client struct {
 	baseURL     string
+	imageURL    string
 	httpClient  *http.Client
 	rateLimiter *ratelimit.RateLimiter
 }
@@ -21,12 +27,23 @@ type Client struct {
 func NewClient(baseURL string) *Client {
 	return &Client{
 		baseURL:     baseURL,
+		imageURL:    imageHost(baseURL),
 		httpClient:  &http.Client{Timeout: 10 * time.Second},
 		rateLimiter: ratelimit.NewRateLimiter(80), // 80 requests/minute (buffer 10)
 	}
 }
 
-func (c *Client) SearchAnime(query string, limit int) ([]models.Anime, error) {
+// imageHost serves poster paths, which are relative to the site root rather than the api
+func imageHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return "https://shikimori.one"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// SearchAnime with censored=false also returns hentai, yaoi and yuri titles
+func (c *Client) SearchAnime(query string, limit int, censored bool) ([]models.Anime, error) {
 	ctx := context.Background()
 
 	// wait for available token
@@ -35,7 +52,7 @@ func (c *Client) SearchAnime(query string, limit int) ([]models.Anime, error) {
 	}
 
 	encodedQuery := url.QueryEscape(query)
-	endpoint := fmt.Sprintf("%s/animes?search=%s&limit=%d", c.baseURL, encodedQuery, limit)
+	endpoint := fmt.Sprintf("%s/animes?search=%s&limit=%d&censored=%t", c.baseURL, encodedQuery, limit, censored)
 
 	req, err := http.NewRequest("GET", endpoint, nil)
 	if err != nil {
@@ -116,3 +133,104 @@ func (c *Client) GetAnimeById(id int) (*models.Anime, error) {
 
 	return &anime, nil
 }
+
+func (c *Client) GetSimilarAnime(id int) ([]models.Anime, error) {
+	return c.getAnimeList(fmt.Sprintf("%s/animes/%d/similar", c.baseURL, id))
+}
+
+// GetTopAnime lists the highest ranked titles, optionally limited to one genre (genreID > 0)
+func (c *Client) GetTopAnime(genreID int, limit int) ([]models.Anime, error) {
+	endpoint := fmt.Sprintf("%s/animes?order=ranked&limit=%d", c.baseURL, limit)
+	if genreID > 0 {
+		endpoint += fmt.Sprintf("&genre=%d", genreID)
+	}
+	return c.getAnimeList(endpoint)
+}
+
+// getAnimeList treats an empty list as a valid answer, unlike SearchAnime
+func (c *Client) getAnimeList(endpoint string) ([]models.Anime, error) {
+	ctx := context.Background()
+
+	// wait for available token
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit wait error: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "TelegramAnimeBot/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting anime list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 429 Too Many Requests
+	if resp.StatusCode == 429 {
+		return nil, fmt.Errorf("rate limit exceeded, try again later")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var animes []models.Anime
+	if err := json.NewDecoder(resp.Body).Decode(&animes); err != nil {
+		return nil, fmt.Errorf("error decoding anime list response: %w", err)
+	}
+
+	return animes, nil
+}
+
+var imageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// FetchImage downloads a poster by its path; images don't count against the
+// api rate limit
+func (c *Client) FetchImage(path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.imageURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "TelegramAnimeBot/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	if !imageTypes[strings.TrimSpace(mediaType)] {
+		return nil, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	if resp.ContentLength > MaxImageSize {
+		return nil, fmt.Errorf("image too large: %d bytes", resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %w", err)
+	}
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("image larger than %d bytes", MaxImageSize)
+	}
+	// the header can't be trusted, error pages are sometimes served as images
+	if sniffed := http.DetectContentType(data); !imageTypes[sniffed] {
+		return nil, fmt.Errorf("image content is %q", sniffed)
+	}
+
+	return data, nil
+}
@@ -45,7 +45,7 @@ func TestSearchAnime_Success(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	result, err := client.SearchAnime("Death Note", 10)
+	result, err := client.SearchAnime("Death Note", 10, true)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -69,7 +69,7 @@ func TestSearchAnime_NoResults(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	_, err := client.SearchAnime("NonExistentAnime", 10)
+	_, err := client.SearchAnime("NonExistentAnime", 10, true)
 
 	if err == nil {
 		t.Error("expected error for no results")
@@ -87,7 +87,7 @@ func TestSearchAnime_RateLimitExceeded(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	_, err := client.SearchAnime("Test", 10)
+	_, err := client.SearchAnime("Test", 10, true)
 
 	if err == nil {
 		t.Error("expected error for rate limit")
@@ -107,7 +107,7 @@ func TestSearchAnime_InvalidJSON(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	_, err := client.SearchAnime("Test", 10)
+	_, err := client.SearchAnime("Test", 10, true)
 
 	if err == nil {
 		t.Error("expected error for invalid JSON")
@@ -125,7 +125,7 @@ func TestSearchAnime_UnexpectedStatusCode(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	_, err := client.SearchAnime("Test", 10)
+	_, err := client.SearchAnime("Test", 10, true)
 
 	if err == nil {
 		t.Error("expected error for unexpected status code")
@@ -149,7 +149,7 @@ func TestSearchAnime_SpecialCharactersInQuery(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	result, err := client.SearchAnime("日本", 10)
+	result, err := client.SearchAnime("日本", 10, true)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -293,7 +293,7 @@ func TestSearchAnime_LargeResponse(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	result, err := client.SearchAnime("Popular", 100)
+	result, err := client.SearchAnime("Popular", 100, true)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -353,3 +353,114 @@ func TestNewClient_Configuration(t *testing.T) {
 		t.Errorf("expected timeout %d seconds, got %v", expectedTimeout, client.httpClient.Timeout)
 	}
 }
+
+func TestGetSimilarAnime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/animes/9253/similar" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]models.Anime{{ID: 28851, Name: "Koe no Katachi"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	result, err := client.GetSimilarAnime(9253)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].ID != 28851 {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestGetTopAnime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("order") != "ranked" || query.Get("limit") != "20" || query.Get("genre") != "7" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]models.Anime{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	result, err := client.GetTopAnime(7, 20)
+	if err != nil {
+		t.Fatalf("an empty list is not an error, got: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected no results, got %v", result)
+	}
+}
+
+func TestGetTopAnime_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "genre=") {
+			t.Errorf("genre filter should be omitted, got %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if _, err := client.GetTopAnime(0, 10); err == nil {
+		t.Error("expected error for server error")
+	}
+}
+
+// a minimal valid jpeg header is enough for content sniffing
+var jpegBytes = []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}
+
+func TestFetchImage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/system/animes/original/1.jpg" {
+			t.Errorf("expected path relative to the site root, got %s", r.URL.Path)
+		}
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("expected User-Agent header")
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(jpegBytes)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/api")
+	data, err := client.FetchImage("/system/animes/original/1.jpg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != len(jpegBytes) {
+		t.Errorf("expected %d bytes, got %d", len(jpegBytes), len(data))
+	}
+}
+
+func TestFetchImage_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        []byte
+	}{
+		{"not found", http.StatusNotFound, "image/jpeg", jpegBytes},
+		{"html page", http.StatusOK, "text/html", []byte("<html>hotlinking is not allowed</html>")},
+		{"html served as jpeg", http.StatusOK, "image/jpeg", []byte("<html>hotlinking is not allowed</html>")},
+		{"too large", http.StatusOK, "image/jpeg", append(append([]byte{}, jpegBytes...), make([]byte, MaxImageSize)...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			if _, err := NewClient(server.URL).FetchImage("/poster.jpg"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const (
+	// TopNeighbors is how many neighbours are kept per anime
+	TopNeighbors = 20
+	// MinCommonUsers filters out pairs that share too few raters to be meaningful
+	MinCommonUsers = 2
+)
+
+// Model holds ratings centred on each user's mean, which is what makes the
+// cosine "adjusted": a 7 from a harsh critic counts more than a 7 from someone
+// who rates everything 9
+type Model struct {
+	byItem map[int]map[int64]float64
+	byUser map[int64][]int
+}
+
+func NewModel(ratings []models.Rating) *Model {
+	sums := make(map[int64]float64)
+	counts := make(map[int64]int)
+	for _, rating := range ratings {
+		sums[rating.UserID] += float64(rating.Score)
+		counts[rating.UserID]++
+	}
+
+	m := &Model{
+		byItem: make(map[int]map[int64]float64),
+		byUser: make(map[int64][]int),
+	}
+	for _, rating := range ratings {
+		mean := sums[rating.UserID] / float64(counts[rating.UserID])
+		if m.byItem[rating.AnimeID] == nil {
+			m.byItem[rating.AnimeID] = make(map[int64]float64)
+		}
+		m.byItem[rating.AnimeID][rating.UserID] = float64(rating.Score) - mean
+		m.byUser[rating.UserID] = append(m.byUser[rating.UserID], rating.AnimeID)
+	}
+	return m
+}
+
+// Affected expands changed anime to every anime whose neighbour list may now be
+// stale: a changed rating moves its user's mean, so all of that user's titles shift
+func (m *Model) Affected(changed []int) []int {
+	seen := make(map[int]bool)
+	for _, animeID := range changed {
+		seen[animeID] = true
+		for userID := range m.byItem[animeID] {
+			for _, other := range m.byUser[userID] {
+				seen[other] = true
+			}
+		}
+	}
+
+	affected := make([]int, 0, len(seen))
+	for animeID := range seen {
+		affected = append(affected, animeID)
+	}
+	sort.Ints(affected)
+	return affected
+}
+
+// Similarity is the adjusted cosine over users who rated both titles
+func (m *Model) Similarity(a, b int) (float64, int) {
+	ratersA, ratersB := m.byItem[a], m.byItem[b]
+	if len(ratersB) < len(ratersA) {
+		ratersA, ratersB = ratersB, ratersA
+	}
+
+	var dot, normA, normB float64
+	common := 0
+	for userID, x := range ratersA {
+		y, ok := ratersB[userID]
+		if !ok {
+			continue
+		}
+		common++
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0, common
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB)), common
+}
+
+// Neighbors returns up to limit positively similar anime, most similar first
+func (m *Model) Neighbors(animeID int, limit int) []models.AnimeNeighbor {
+	candidates := make(map[int]bool)
+	for userID := range m.byItem[animeID] {
+		for _, other := range m.byUser[userID] {
+			if other != animeID {
+				candidates[other] = true
+			}
+		}
+	}
+
+	var neighbors []models.AnimeNeighbor
+	for other := range candidates {
+		similarity, common := m.Similarity(animeID, other)
+		if common < MinCommonUsers || similarity <= 0 {
+			continue
+		}
+		neighbors = append(neighbors, models.AnimeNeighbor{
+			AnimeID:     animeID,
+			NeighborID:  other,
+			Similarity:  similarity,
+			CommonUsers: common,
+		})
+	}
+
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].Similarity != neighbors[j].Similarity {
+			return neighbors[i].Similarity > neighbors[j].Similarity
+		}
+		return neighbors[i].NeighborID < neighbors[j].NeighborID
+	})
+	if len(neighbors) > limit {
+		neighbors = neighbors[:limit]
+	}
+	return neighbors
+}
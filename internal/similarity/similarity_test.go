@@ -0,0 +1,98 @@
+package similarity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// two taste clusters: users 1-4 love action (1, 2, 3) and dislike romance
+// (10, 11), users 5-8 the other way round
+func syntheticRatings() []models.Rating {
+	var ratings []models.Rating
+	rate := func(userID int64, animeID, score int) {
+		ratings = append(ratings, models.Rating{UserID: userID, AnimeID: animeID, Score: score})
+	}
+	for userID := int64(1); userID <= 4; userID++ {
+		bias := int(userID % 2)
+		rate(userID, 1, 9+bias)
+		rate(userID, 2, 8+bias)
+		rate(userID, 3, 9)
+		rate(userID, 10, 3+bias)
+		rate(userID, 11, 4)
+	}
+	for userID := int64(5); userID <= 8; userID++ {
+		bias := int(userID % 2)
+		rate(userID, 1, 2+bias)
+		rate(userID, 2, 3)
+		rate(userID, 10, 9+bias)
+		rate(userID, 11, 8+bias)
+	}
+	// a single rater is not enough to call anything similar
+	rate(9, 1, 10)
+	rate(9, 20, 10)
+	rate(9, 10, 1)
+	return ratings
+}
+
+func TestNeighbors_Clusters(t *testing.T) {
+	model := NewModel(syntheticRatings())
+
+	neighbors := model.Neighbors(1, TopNeighbors)
+	ids := make([]int, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		ids = append(ids, neighbor.NeighborID)
+		if neighbor.AnimeID != 1 || neighbor.Similarity <= 0 || neighbor.CommonUsers < MinCommonUsers {
+			t.Errorf("unexpected neighbor: %+v", neighbor)
+		}
+	}
+	if !reflect.DeepEqual(ids, []int{2, 3}) && !reflect.DeepEqual(ids, []int{3, 2}) {
+		t.Fatalf("expected action titles only, got %v", ids)
+	}
+	if neighbors[0].Similarity < neighbors[1].Similarity {
+		t.Errorf("expected most similar first, got %+v", neighbors)
+	}
+
+	romance := model.Neighbors(10, TopNeighbors)
+	if len(romance) != 1 || romance[0].NeighborID != 11 {
+		t.Errorf("expected 11 as the only neighbor of 10, got %+v", romance)
+	}
+
+	if limited := model.Neighbors(1, 1); len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %+v", limited)
+	}
+}
+
+func TestSimilarity_Symmetric(t *testing.T) {
+	model := NewModel(syntheticRatings())
+
+	ab, commonAB := model.Similarity(1, 10)
+	ba, commonBA := model.Similarity(10, 1)
+	if math.Abs(ab-ba) > 1e-9 || commonAB != commonBA || commonAB != 9 {
+		t.Errorf("expected symmetric similarity, got %f/%d and %f/%d", ab, commonAB, ba, commonBA)
+	}
+	if ab >= 0 {
+		t.Errorf("expected opposite clusters to be dissimilar, got %f", ab)
+	}
+
+	if similarity, common := model.Similarity(1, 99); similarity != 0 || common != 0 {
+		t.Errorf("expected no overlap with unknown anime, got %f/%d", similarity, common)
+	}
+}
+
+func TestAffected(t *testing.T) {
+	model := NewModel(syntheticRatings())
+
+	// 20 was only rated by user 9, whose other titles shift with their mean
+	if affected := model.Affected([]int{20}); !reflect.DeepEqual(affected, []int{1, 10, 20}) {
+		t.Errorf("unexpected affected anime: %v", affected)
+	}
+	if affected := model.Affected([]int{99}); !reflect.DeepEqual(affected, []int{99}) {
+		t.Errorf("expected unknown anime to stay on its own, got %v", affected)
+	}
+	if affected := model.Affected(nil); len(affected) != 0 {
+		t.Errorf("expected nothing affected, got %v", affected)
+	}
+}
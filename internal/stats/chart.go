@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	chartWidth  = 800
+	chartHeight = 460
+	chartMargin = 30
+)
+
+var (
+	chartBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	chartText       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	chartAxis       = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+	chartScores     = color.RGBA{0xf5, 0xa6, 0x23, 0xff}
+	chartGenres     = color.RGBA{0x4a, 0x90, 0xe2, 0xff}
+	chartKinds      = color.RGBA{0x7e, 0xd3, 0x21, 0xff}
+)
+
+// the built-in bitmap font only covers ASCII, so the chart uses English genre
+// names and Shikimori kind codes; localized numbers go into the caption
+func RenderPNG(w io.Writer, s Summary) error {
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	drawScores(img, s, image.Rect(chartMargin, chartMargin, chartWidth/2-chartMargin/2, chartHeight-chartMargin))
+
+	genres := make([]bar, 0, len(s.Genres))
+	for _, genre := range s.Genres {
+		genres = append(genres, bar{label: genre.Name, value: genre.Count})
+	}
+	drawBars(img, "Top genres", genres, chartGenres, image.Rect(chartWidth/2+chartMargin/2, chartMargin, chartWidth-chartMargin, chartHeight/2+chartMargin))
+
+	kinds := make([]bar, 0, len(s.Kinds))
+	for _, kind := range s.Kinds {
+		kinds = append(kinds, bar{label: kind.Kind, value: kind.Count})
+	}
+	drawBars(img, "Kinds", kinds, chartKinds, image.Rect(chartWidth/2+chartMargin/2, chartHeight/2+chartMargin*2, chartWidth-chartMargin, chartHeight-chartMargin))
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return nil
+}
+
+type bar struct {
+	label string
+	value int
+}
+
+func drawScores(img *image.RGBA, s Summary, area image.Rectangle) {
+	drawText(img, area.Min.X, area.Min.Y+10, fmt.Sprintf("Scores (avg %.2f)", s.AverageScore))
+
+	maxCount := 0
+	for _, count := range s.Scores {
+		maxCount = max(maxCount, count)
+	}
+
+	top := area.Min.Y + 40
+	baseline := area.Max.Y - 20
+	fill(img, image.Rect(area.Min.X, baseline, area.Max.X, baseline+1), chartAxis)
+
+	slot := area.Dx() / len(s.Scores)
+	for i, count := range s.Scores {
+		x := area.Min.X + i*slot
+		height := 0
+		if maxCount > 0 {
+			height = count * (baseline - top) / maxCount
+		}
+		fill(img, image.Rect(x+4, baseline-height, x+slot-4, baseline), chartScores)
+
+		label := strconv.Itoa(i + 1)
+		drawText(img, x+slot/2-TextWidth(label)/2, baseline+15, label)
+		if count > 0 {
+			value := strconv.Itoa(count)
+			drawText(img, x+slot/2-TextWidth(value)/2, baseline-height-4, value)
+		}
+	}
+}
+
+func drawBars(img *image.RGBA, title string, bars []bar, c color.Color, area image.Rectangle) {
+	drawText(img, area.Min.X, area.Min.Y+10, title)
+	if len(bars) == 0 {
+		drawText(img, area.Min.X, area.Min.Y+35, "no data")
+		return
+	}
+
+	const labelWidth = 110
+	const rowHeight = 20
+
+	maxValue := 0
+	for _, b := range bars {
+		maxValue = max(maxValue, b.value)
+	}
+
+	rows := min(len(bars), (area.Dy()-20)/rowHeight)
+	barSpace := area.Dx() - labelWidth - 40
+	for i, b := range bars[:rows] {
+		y := area.Min.Y + 20 + i*rowHeight
+		drawText(img, area.Min.X, y+13, truncateLabel(b.label, labelWidth))
+
+		width := b.value * barSpace / maxValue
+		x := area.Min.X + labelWidth
+		fill(img, image.Rect(x, y+3, x+width, y+rowHeight-3), c)
+		drawText(img, x+width+5, y+13, strconv.Itoa(b.value))
+	}
+}
+
+func fill(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+func drawText(img *image.RGBA, x, y int, text string) {
+	DrawColoredText(img, x, y, text, chartText)
+}
+
+// DrawColoredText writes ASCII text in the bitmap font with its baseline at y
+func DrawColoredText(img *image.RGBA, x, y int, text string, c color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{c},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+// TextWidth is how many pixels DrawColoredText needs for text
+func TextWidth(text string) int {
+	return font.MeasureString(basicfont.Face7x13, text).Round()
+}
+
+func truncateLabel(label string, width int) string {
+	runes := []rune(label)
+	for len(runes) > 0 && TextWidth(string(runes)) > width-5 {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
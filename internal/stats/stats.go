@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const TopGenres = 8
+
+var ErrInvalidPeriod = errors.New("invalid stats period")
+
+const (
+	PeriodAll   = "all"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// Period is a half-open [From, To) range; zero bounds are open
+type Period struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+func ParsePeriod(arg string, now time.Time) (Period, error) {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	loc := now.Location()
+
+	switch arg {
+	case "", PeriodAll:
+		return Period{Name: PeriodAll}, nil
+	case PeriodMonth:
+		return Period{Name: PeriodMonth, From: now.AddDate(0, -1, 0)}, nil
+	case PeriodYear:
+		return Period{Name: PeriodYear, From: now.AddDate(-1, 0, 0)}, nil
+	}
+
+	if from, to, ok := strings.Cut(arg, ".."); ok {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return Period{}, ErrInvalidPeriod
+		}
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil || end.Before(start) {
+			return Period{}, ErrInvalidPeriod
+		}
+		// the end date is inclusive
+		return Period{Name: arg, From: start, To: end.AddDate(0, 0, 1)}, nil
+	}
+
+	if month, err := time.ParseInLocation("2006-01", arg, loc); err == nil {
+		return Period{Name: arg, From: month, To: month.AddDate(0, 1, 0)}, nil
+	}
+	if year, err := time.ParseInLocation("2006", arg, loc); err == nil {
+		return Period{Name: arg, From: year, To: year.AddDate(1, 0, 0)}, nil
+	}
+	return Period{}, ErrInvalidPeriod
+}
+
+type KindCount struct {
+	Kind  string
+	Count int
+}
+
+type Summary struct {
+	Titles         int
+	Favorites      int
+	Rated          int
+	AverageScore   float64
+	Scores         [10]int
+	Kinds          []KindCount
+	Genres         []models.GenreCount
+	MinutesWatched int
+	// Uncataloged titles have no kind or runtime, so they are left out of
+	// the hours and counted as "unknown" kind
+	Uncataloged int
+}
+
+func (s Summary) HoursWatched() float64 {
+	return float64(s.MinutesWatched) / 60
+}
+
+func Compute(entries []models.StatsEntry, genres []models.GenreCount) Summary {
+	summary := Summary{Titles: len(entries), Genres: genres}
+	if len(summary.Genres) > TopGenres {
+		summary.Genres = summary.Genres[:TopGenres]
+	}
+
+	kinds := make(map[string]int)
+	total := 0
+	for _, entry := range entries {
+		if entry.Favorite {
+			summary.Favorites++
+		}
+		if !entry.Cataloged {
+			summary.Uncataloged++
+		}
+		kind := entry.Kind
+		if kind == "" {
+			kind = "unknown"
+		}
+		kinds[kind]++
+
+		if entry.Score == nil || *entry.Score < 1 || *entry.Score > 10 {
+			continue
+		}
+		summary.Rated++
+		summary.Scores[*entry.Score-1]++
+		total += *entry.Score
+		// a rating is the only signal that a title was watched
+		summary.MinutesWatched += entry.Episodes * entry.Duration
+	}
+
+	if summary.Rated > 0 {
+		summary.AverageScore = float64(total) / float64(summary.Rated)
+	}
+
+	for kind, count := range kinds {
+		summary.Kinds = append(summary.Kinds, KindCount{Kind: kind, Count: count})
+	}
+	sort.Slice(summary.Kinds, func(i, j int) bool {
+		if summary.Kinds[i].Count != summary.Kinds[j].Count {
+			return summary.Kinds[i].Count > summary.Kinds[j].Count
+		}
+		return summary.Kinds[i].Kind < summary.Kinds[j].Kind
+	})
+
+	return summary
+}
+
+func FileName(period Period) string {
+	return fmt.Sprintf("anime-stats-%s.png", strings.ReplaceAll(period.Name, "..", "_"))
+}
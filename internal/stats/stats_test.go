@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		arg  string
+		from time.Time
+		to   time.Time
+	}{
+		{"", time.Time{}, time.Time{}},
+		{"all", time.Time{}, time.Time{}},
+		{"month", time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC), time.Time{}},
+		{"Year", time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC), time.Time{}},
+		{"2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-02", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-01..2024-01-31", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		period, err := ParsePeriod(tt.arg, now)
+		if err != nil {
+			t.Errorf("ParsePeriod(%q) returned error: %v", tt.arg, err)
+			continue
+		}
+		if !period.From.Equal(tt.from) || !period.To.Equal(tt.to) {
+			t.Errorf("ParsePeriod(%q) = %v..%v, want %v..%v", tt.arg, period.From, period.To, tt.from, tt.to)
+		}
+	}
+
+	for _, arg := range []string{"week", "2024-13", "2024-02-01..2024-01-01", "2024-01-01.."} {
+		if _, err := ParsePeriod(arg, now); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("ParsePeriod(%q): expected ErrInvalidPeriod, got %v", arg, err)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	score := func(n int) *int { return &n }
+	entries := []models.StatsEntry{
+		{AnimeID: 1, Kind: "tv", Episodes: 12, Duration: 24, Favorite: true, Score: score(8), Cataloged: true},
+		{AnimeID: 2, Kind: "tv", Episodes: 24, Duration: 24, Favorite: true, Cataloged: true},
+		{AnimeID: 3, Kind: "movie", Episodes: 1, Duration: 120, Score: score(10), Cataloged: true},
+		{AnimeID: 4, Score: score(6)},
+	}
+
+	s := Compute(entries, nil)
+	if s.Titles != 4 || s.Favorites != 2 || s.Rated != 3 {
+		t.Errorf("unexpected totals: %+v", s)
+	}
+	if s.AverageScore != 8 {
+		t.Errorf("expected average 8, got %v", s.AverageScore)
+	}
+	if s.Scores[7] != 1 || s.Scores[9] != 1 || s.Scores[5] != 1 {
+		t.Errorf("unexpected histogram: %v", s.Scores)
+	}
+	// unrated titles don't count towards watch time
+	if s.MinutesWatched != 12*24+120 {
+		t.Errorf("unexpected minutes: %d", s.MinutesWatched)
+	}
+	if len(s.Kinds) != 3 || s.Kinds[0] != (KindCount{Kind: "tv", Count: 2}) {
+		t.Errorf("unexpected kinds: %+v", s.Kinds)
+	}
+	if s.Uncataloged != 1 {
+		t.Errorf("expected 1 uncataloged title, got %d", s.Uncataloged)
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	s := Summary{
+		Titles:       2,
+		Rated:        2,
+		AverageScore: 8.5,
+		Scores:       [10]int{7: 1, 8: 1},
+		Kinds:        []KindCount{{Kind: "tv", Count: 2}},
+		Genres:       []models.GenreCount{{Name: "Slice of Life and very long genre", Count: 2}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderPNG(&buf, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("chart is not a valid png: %v", err)
+	}
+	if img.Bounds().Dx() != chartWidth || img.Bounds().Dy() != chartHeight {
+		t.Errorf("unexpected size: %v", img.Bounds())
+	}
+
+	// an empty summary still renders
+	if err := RenderPNG(&bytes.Buffer{}, Summary{}); err != nil {
+		t.Errorf("unexpected error for empty summary: %v", err)
+	}
+}
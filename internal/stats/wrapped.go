@@ -0,0 +1,176 @@
+package stats
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+const WrappedTopRated = 3
+
+type WrappedTitle struct {
+	AnimeID int
+	Title   string
+	Name    string
+	Score   int
+	Minutes int
+}
+
+type Wrapped struct {
+	Year        int
+	Added       int
+	Rated       int
+	TopRated    []WrappedTitle
+	Genre       *models.GenreCount
+	ActiveMonth time.Month
+	MonthEvents int
+	Longest     *WrappedTitle
+}
+
+func (w Wrapped) Empty() bool {
+	return w.Added == 0 && w.Rated == 0
+}
+
+func YearPeriod(year int, loc *time.Location) Period {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return Period{Name: strconv.Itoa(year), From: from, To: from.AddDate(1, 0, 0)}
+}
+
+// WrappedDue tells which year's recap the broadcast should go out for at now:
+// from noon on December 31 through the first week of January, so a bot that
+// was down on new year's eve still sends it
+func WrappedDue(now time.Time) (int, bool) {
+	switch {
+	case now.Month() == time.December && now.Day() == 31 && now.Hour() >= 12:
+		return now.Year(), true
+	case now.Month() == time.January && now.Day() <= 7:
+		return now.Year() - 1, true
+	}
+	return 0, false
+}
+
+// ComputeWrapped treats a rating as a finished title, the same way Compute
+// counts watch time
+func ComputeWrapped(year int, events []models.ActivityEvent, genres []models.GenreCount) Wrapped {
+	w := Wrapped{Year: year}
+	if len(genres) > 0 {
+		w.Genre = &genres[0]
+	}
+
+	var months [13]int
+	var rated []WrappedTitle
+	for _, event := range events {
+		months[event.At.Month()]++
+
+		if event.Score == nil {
+			w.Added++
+			continue
+		}
+		w.Rated++
+
+		title := WrappedTitle{
+			AnimeID: event.AnimeID,
+			Title:   event.Title,
+			Name:    event.Name,
+			Score:   *event.Score,
+			Minutes: event.Episodes * event.Duration,
+		}
+		rated = append(rated, title)
+		if title.Minutes > 0 && (w.Longest == nil || title.Minutes > w.Longest.Minutes) {
+			longest := title
+			w.Longest = &longest
+		}
+	}
+
+	for month := time.January; month <= time.December; month++ {
+		if months[month] > w.MonthEvents {
+			w.ActiveMonth = month
+			w.MonthEvents = months[month]
+		}
+	}
+
+	// events arrive in time order, so a stable sort keeps the earliest of equal scores first
+	sort.SliceStable(rated, func(i, j int) bool {
+		return rated[i].Score > rated[j].Score
+	})
+	if len(rated) > WrappedTopRated {
+		rated = rated[:WrappedTopRated]
+	}
+	w.TopRated = rated
+
+	return w
+}
+
+func WrappedFileName(year int) string {
+	return fmt.Sprintf("anime-wrapped-%d.png", year)
+}
+
+var (
+	wrappedHeader = color.RGBA{0x4a, 0x90, 0xe2, 0xff}
+	wrappedWhite  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// RenderWrappedPNG draws a share card; like RenderPNG it sticks to ASCII, so
+// titles use their romaji names
+func RenderWrappedPNG(w io.Writer, wrapped Wrapped) error {
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	fill(img, image.Rect(0, 0, chartWidth, 70), wrappedHeader)
+	DrawColoredText(img, chartMargin, 42, fmt.Sprintf("MY %d IN ANIME", wrapped.Year), wrappedWhite)
+
+	lines := []string{
+		fmt.Sprintf("Titles added: %d", wrapped.Added),
+		fmt.Sprintf("Titles rated: %d", wrapped.Rated),
+	}
+	if wrapped.Genre != nil {
+		lines = append(lines, "Favorite genre: "+wrapped.Genre.Name)
+	}
+	if wrapped.MonthEvents > 0 {
+		lines = append(lines, fmt.Sprintf("Most active month: %s (%d)", wrapped.ActiveMonth, wrapped.MonthEvents))
+	}
+	if wrapped.Longest != nil {
+		lines = append(lines, fmt.Sprintf("Longest finished: %s (%.1f h)", ASCIILabel(wrapped.Longest.Name, wrapped.Longest.AnimeID), float64(wrapped.Longest.Minutes)/60))
+	}
+	if len(wrapped.TopRated) > 0 {
+		lines = append(lines, "", "Top rated:")
+		for i, title := range wrapped.TopRated {
+			lines = append(lines, fmt.Sprintf("  %d. %s - %d/10", i+1, ASCIILabel(title.Name, title.AnimeID), title.Score))
+		}
+	}
+
+	y := 110
+	for _, line := range lines {
+		drawText(img, chartMargin, y, truncateLabel(line, chartWidth-chartMargin*2))
+		y += 26
+	}
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode wrapped card: %w", err)
+	}
+	return nil
+}
+
+// ASCIILabel drops characters the bitmap font can't draw and falls back to the id
+func ASCIILabel(name string, animeID int) string {
+	label := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, name)
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return fmt.Sprintf("Anime #%d", animeID)
+	}
+	return label
+}
@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func TestComputeWrapped(t *testing.T) {
+	score := func(n int) *int { return &n }
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 12, 0, 0, 0, time.UTC)
+	}
+	events := []models.ActivityEvent{
+		{AnimeID: 1, Title: "Монстр", At: at(time.March, 1)},
+		{AnimeID: 2, Title: "Акира", At: at(time.March, 5)},
+		{AnimeID: 1, Title: "Монстр", Name: "Monster", Episodes: 74, Duration: 24, Score: score(10), At: at(time.March, 20)},
+		{AnimeID: 3, Title: "Тетрадь смерти", Episodes: 37, Duration: 23, Score: score(9), At: at(time.May, 2)},
+		{AnimeID: 2, Title: "Акира", Episodes: 1, Duration: 124, Score: score(10), At: at(time.June, 1)},
+		{AnimeID: 4, Score: score(5), At: at(time.July, 1)},
+	}
+	genres := []models.GenreCount{{Name: "Mystery", Count: 2}}
+
+	w := ComputeWrapped(2025, events, genres)
+	if w.Added != 2 || w.Rated != 4 {
+		t.Errorf("unexpected totals: %+v", w)
+	}
+	if w.ActiveMonth != time.March || w.MonthEvents != 3 {
+		t.Errorf("unexpected active month: %v (%d)", w.ActiveMonth, w.MonthEvents)
+	}
+	if len(w.TopRated) != WrappedTopRated || w.TopRated[0].AnimeID != 1 || w.TopRated[1].AnimeID != 2 || w.TopRated[2].AnimeID != 3 {
+		t.Errorf("unexpected top rated: %+v", w.TopRated)
+	}
+	if w.Longest == nil || w.Longest.AnimeID != 1 || w.Longest.Minutes != 74*24 {
+		t.Errorf("unexpected longest: %+v", w.Longest)
+	}
+	if w.Genre == nil || w.Genre.Name != "Mystery" {
+		t.Errorf("unexpected genre: %+v", w.Genre)
+	}
+}
+
+func TestComputeWrapped_Empty(t *testing.T) {
+	w := ComputeWrapped(2025, nil, nil)
+	if !w.Empty() || w.Longest != nil || w.Genre != nil || w.MonthEvents != 0 {
+		t.Errorf("expected empty recap, got %+v", w)
+	}
+}
+
+func TestYearPeriod(t *testing.T) {
+	period := YearPeriod(2025, time.UTC)
+	if !period.From.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !period.To.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected period: %+v", period)
+	}
+}
+
+func TestWrappedDue(t *testing.T) {
+	cases := []struct {
+		now  time.Time
+		year int
+		due  bool
+	}{
+		{time.Date(2025, 12, 31, 11, 59, 0, 0, time.UTC), 0, false},
+		{time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC), 2025, true},
+		{time.Date(2026, 1, 7, 23, 0, 0, 0, time.UTC), 2025, true},
+		{time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC), 0, false},
+		{time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC), 0, false},
+	}
+	for _, c := range cases {
+		if year, due := WrappedDue(c.now); year != c.year || due != c.due {
+			t.Errorf("WrappedDue(%v): expected %d %v, got %d %v", c.now, c.year, c.due, year, due)
+		}
+	}
+}
+
+func TestRenderWrappedPNG(t *testing.T) {
+	w := Wrapped{
+		Year:        2025,
+		Added:       2,
+		Rated:       1,
+		TopRated:    []WrappedTitle{{AnimeID: 1, Name: "Monster", Score: 10}, {AnimeID: 2, Name: "Тетрадь", Score: 9}},
+		ActiveMonth: time.March,
+		MonthEvents: 3,
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWrappedPNG(&buf, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Fatalf("wrapped card is not a valid png: %v", err)
+	}
+}
+
+func TestASCIILabel(t *testing.T) {
+	if got := ASCIILabel("Shingeki no Kyojin", 1); got != "Shingeki no Kyojin" {
+		t.Errorf("unexpected label: %q", got)
+	}
+	if got := ASCIILabel("Тетрадь смерти", 7); got != "Anime #7" {
+		t.Errorf("expected id fallback, got %q", got)
+	}
+}
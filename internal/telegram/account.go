@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+func (b *Bot) handleDeleteMe(chatID int64) {
+	tr := b.tr(chatID)
+	msg := tgbotapi.NewMessage(chatID, tr.T("account.delete_confirm"))
+	msg.ReplyMarkup = b.createDeleteAccountKeyboard(tr)
+	b.api.Send(msg)
+}
+
+func (b *Bot) handleAccountCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	tr := b.tr(userID)
+
+	switch callback.Data {
+	case "deleteme_cancel":
+		b.api.Send(tgbotapi.NewEditMessageText(chatID, messageID, tr.T("account.delete_cancelled")))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "deleteme_ok":
+		_, err := b.animeService.DeleteUserData(userID, service.DeletionSourceBot)
+		if err != nil {
+			b.logger.Error("Failed to delete user data: %v", err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("account.delete_failed")))
+			return true
+		}
+
+		b.clearState(userID)
+		b.forgetLanguage(userID)
+		b.logger.Info("User data deleted on request")
+
+		b.api.Send(tgbotapi.NewEditMessageText(chatID, messageID, tr.T("account.deleted")))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	return false
+}
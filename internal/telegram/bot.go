@@ -4,7 +4,9 @@ import (
 	"sync"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/recommend"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
 	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/logger"
 )
@@ -14,7 +16,10 @@ type Bot struct {
 	animeService *service.AnimeService
 	logger       *logger.Logger
 
+	// keyed by user id; group chats keep their shared search under the
+	// (negative) chat id
 	userStates map[int64]*UserState
+	languages  map[int64]userLanguage
 	mu         sync.RWMutex
 }
 
@@ -22,9 +27,31 @@ type UserState struct {
 	SearchResults    []models.Anime
 	CurrentIndex     int
 	WaitingForSearch bool
-	FavoritesPage    int
 	RatingAnimeID    int
 	WaitingForRating bool
+	NoteAnimeID      int
+	WaitingForNote   bool
+
+	CollectionID             int
+	CollectionCursors        []*database.CollectionCursor
+	CollectionNext           *database.CollectionCursor
+	CollectionAnimeID        int
+	WaitingForCollectionName bool
+	CollectionNameAction     string
+	PendingAnimeID           int
+
+	RatingsScore int
+	RatingsSort  string
+	RatingsPage  int
+	RatedAnimeID int
+
+	FavoritesSort             string
+	FavoritesSearch           string
+	FavoritesCursors          []*database.FavoriteCursor
+	FavoritesNext             *database.FavoriteCursor
+	WaitingForFavoritesSearch bool
+
+	Recommendations []recommend.Pick
 }
 
 func NewBot(token string, animeService *service.AnimeService, logger *logger.Logger) (*Bot, error) {
@@ -38,6 +65,7 @@ func NewBot(token string, animeService *service.AnimeService, logger *logger.Log
 		animeService: animeService,
 		logger:       logger,
 		userStates:   make(map[int64]*UserState),
+		languages:    make(map[int64]userLanguage),
 	}, nil
 }
 
@@ -47,6 +75,7 @@ func NewBotWithAPI(api *tgbotapi.BotAPI, animeService *service.AnimeService, log
 		animeService: animeService,
 		logger:       logger,
 		userStates:   make(map[int64]*UserState),
+		languages:    make(map[int64]userLanguage),
 	}, nil
 }
 
@@ -58,6 +87,14 @@ func (b *Bot) HandleUpdate(update *tgbotapi.Update) {
 	if update.CallbackQuery != nil {
 		b.handleCallback(update.CallbackQuery)
 	}
+
+	if update.Poll != nil {
+		b.handlePoll(update.Poll)
+	}
+
+	if update.PollAnswer != nil {
+		b.handlePollAnswer(update.PollAnswer)
+	}
 }
 
 func (b *Bot) saveState(userID int64, state *UserState) {
@@ -72,6 +109,12 @@ func (b *Bot) getState(userID int64) *UserState {
 	return b.userStates[userID]
 }
 
+func (b *Bot) clearState(userID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.userStates, userID)
+}
+
 func (b *Bot) getCurrentAnime(userID int64) *models.Anime {
 	state := b.getState(userID)
 	if state == nil || state.CurrentIndex >= len(state.SearchResults) {
@@ -88,13 +131,7 @@ func (b *Bot) Start() error {
 	updates := b.api.GetUpdatesChan(u)
 
 	for update := range updates {
-		if update.Message != nil {
-			b.handleMessage(update.Message)
-		}
-
-		if update.CallbackQuery != nil {
-			b.handleCallback(update.CallbackQuery)
-		}
+		b.HandleUpdate(&update)
 	}
 
 	return nil
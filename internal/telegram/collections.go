@@ -0,0 +1,475 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+const (
+	collectionActionCreate = "create"
+	collectionActionRename = "rename"
+)
+
+func clampPage(page, totalPages int) int {
+	if page >= totalPages {
+		page = totalPages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	return page
+}
+
+func (b *Bot) handleCollections(userID int64, chatID int64) {
+	collections, err := b.animeService.GetUserCollections(userID)
+	if err != nil {
+		b.logger.Error("Failed to get collections for user %d: %v", userID, err)
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("collections.load_failed"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+		b.api.Send(msg)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, collectionsText(b.tr(chatID), collections))
+	msg.ReplyMarkup = b.createCollectionsKeyboard(b.tr(chatID), collections)
+	b.api.Send(msg)
+}
+
+func (b *Bot) editCollections(chatID int64, messageID int, userID int64) {
+	collections, err := b.animeService.GetUserCollections(userID)
+	if err != nil {
+		b.logger.Error("Failed to get collections for user %d: %v", userID, err)
+		return
+	}
+
+	keyboard := b.createCollectionsKeyboard(b.tr(chatID), collections)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, collectionsText(b.tr(chatID), collections))
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+func collectionsText(tr i18n.Localizer, collections []models.Collection) string {
+	if len(collections) == 0 {
+		return tr.T("collections.empty")
+	}
+	return tr.T("collections.title", len(collections))
+}
+
+func (b *Bot) collectionView(userID int64, collectionID int) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	collection, err := b.animeService.GetCollection(userID, collectionID)
+	if err != nil {
+		return "", nil, err
+	}
+	if collection == nil {
+		return "", nil, fmt.Errorf("collection %d not found", collectionID)
+	}
+
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	if state.CollectionID != collectionID {
+		state.CollectionCursors = nil
+	}
+	state.CollectionID = collectionID
+
+	pageSize := b.settings(userID).PageSize
+
+	// the collection may have shrunk since the cursors were taken
+	totalPages := int(math.Ceil(float64(collection.ItemCount) / float64(pageSize)))
+	for len(state.CollectionCursors) > 0 && len(state.CollectionCursors) >= totalPages {
+		state.CollectionCursors = state.CollectionCursors[:len(state.CollectionCursors)-1]
+	}
+
+	var after *database.CollectionCursor
+	if len(state.CollectionCursors) > 0 {
+		after = state.CollectionCursors[len(state.CollectionCursors)-1]
+	}
+	items, err := b.animeService.GetCollectionItemsPage(userID, collectionID, after, pageSize)
+	if err != nil {
+		return "", nil, err
+	}
+
+	state.CollectionNext = nil
+	if len(items) == pageSize {
+		state.CollectionNext = database.CollectionCursorAfter(items[len(items)-1])
+	}
+	b.saveState(userID, state)
+
+	tr := b.tr(userID)
+	text := tr.T("collections.items", collection.Name, collection.ItemCount)
+	if collection.ItemCount == 0 {
+		text = tr.T("collections.items_empty", collection.Name, tr.T("button.to_collection"))
+	}
+
+	keyboard := b.createCollectionKeyboard(tr, collectionID, items, len(state.CollectionCursors), totalPages)
+	return text, &keyboard, nil
+}
+
+func (b *Bot) showCollection(chatID int64, userID int64, collectionID int) {
+	text, keyboard, err := b.collectionView(userID, collectionID)
+	if err != nil {
+		b.logger.Error("Failed to show collection %d for user %d: %v", collectionID, userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("collections.not_found")))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	b.api.Send(msg)
+}
+
+func (b *Bot) editCollection(chatID int64, messageID int, userID int64, collectionID int) {
+	text, keyboard, err := b.collectionView(userID, collectionID)
+	if err != nil {
+		b.logger.Error("Failed to show collection %d for user %d: %v", collectionID, userID, err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ReplyMarkup = keyboard
+	b.api.Send(edit)
+}
+
+func (b *Bot) showCollectionAnime(chatID int64, userID int64, collectionID int, animeID int) {
+	anime, err := b.animeService.GetAnimeByID(animeID)
+	if err != nil {
+		b.logger.Error("Failed to get anime details: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("anime.load_failed")))
+		return
+	}
+
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.CollectionID = collectionID
+	state.CollectionAnimeID = animeID
+	state.RatedAnimeID = 0
+	b.saveState(userID, state)
+
+	isFav, _ := b.animeService.IsFavorite(userID, animeID)
+	userRating, _ := b.animeService.GetUserRating(userID, animeID)
+	note, _ := b.animeService.GetUserNote(userID, animeID)
+	community, _ := b.animeService.GetCommunityRating(animeID)
+	alsoLiked, _ := b.animeService.GetAlsoLiked(animeID)
+	friends, _ := b.animeService.GetFriendRatings(userID, animeID)
+
+	text := utils.FormatAnimeMessageWithRating(b.tr(chatID), b.settings(userID), anime, isFav, userRating, note, community, alsoLiked, friends)
+	keyboard := b.createCollectionAnimeKeyboard(b.tr(chatID), collectionID, animeID, userRating)
+
+	b.sendAnimeCard(chatID, anime, text, keyboard)
+}
+
+func (b *Bot) showCollectionPicker(chatID int64, userID int64, animeID int) {
+	collections, err := b.animeService.GetUserCollections(userID)
+	if err != nil {
+		b.logger.Error("Failed to get collections for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("collections.load_failed")))
+		return
+	}
+	memberOf, _ := b.animeService.GetAnimeCollectionIDs(userID, animeID)
+
+	tr := b.tr(chatID)
+	text := tr.T("collections.pick")
+	if len(collections) == 0 {
+		text = tr.T("collections.empty")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = b.createCollectionPickerKeyboard(tr, animeID, collections, memberOf)
+	b.api.Send(msg)
+}
+
+func (b *Bot) editCollectionPicker(chatID int64, messageID int, userID int64, animeID int) {
+	collections, err := b.animeService.GetUserCollections(userID)
+	if err != nil {
+		b.logger.Error("Failed to get collections for user %d: %v", userID, err)
+		return
+	}
+	memberOf, _ := b.animeService.GetAnimeCollectionIDs(userID, animeID)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID,
+		b.createCollectionPickerKeyboard(b.tr(chatID), animeID, collections, memberOf))
+	b.api.Send(edit)
+}
+
+func (b *Bot) promptCollectionName(chatID int64, userID int64, action string, collectionID int, animeID int) {
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.WaitingForCollectionName = true
+	state.CollectionNameAction = action
+	state.CollectionID = collectionID
+	state.PendingAnimeID = animeID
+	b.saveState(userID, state)
+
+	tr := b.tr(chatID)
+	text := tr.T("collections.name_prompt")
+	if action == collectionActionRename {
+		text = tr.T("collections.rename_prompt")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = b.createCancelKeyboard(tr)
+	b.api.Send(msg)
+}
+
+func (b *Bot) handleCollectionNameInput(userID int64, chatID int64, text string) {
+	state := b.getState(userID)
+	action := state.CollectionNameAction
+	collectionID := state.CollectionID
+	animeID := state.PendingAnimeID
+	state.WaitingForCollectionName = false
+	state.CollectionNameAction = ""
+	state.PendingAnimeID = 0
+	b.saveState(userID, state)
+
+	tr := b.tr(chatID)
+	if isCancel(text) {
+		msg := tgbotapi.NewMessage(chatID, tr.T("cancelled"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
+		b.api.Send(msg)
+		return
+	}
+
+	var reply string
+	var err error
+	switch action {
+	case collectionActionRename:
+		err = b.animeService.RenameCollection(userID, collectionID, text)
+		reply = tr.T("collections.renamed")
+	default:
+		var collection *models.Collection
+		collection, err = b.animeService.CreateCollection(userID, text)
+		if err == nil {
+			collectionID = collection.ID
+			reply = tr.T("collections.created", collection.Name)
+			if animeID != 0 {
+				if err = b.animeService.AddToCollection(userID, collectionID, animeID); err == nil {
+					reply = tr.T("collections.created_with_anime", collection.Name)
+				}
+			}
+		}
+	}
+
+	if err != nil {
+		b.logger.Error("Failed to %s collection for user %d: %v", action, userID, err)
+		reply = tr.T("error.generic", err)
+		if errors.Is(err, database.ErrCollectionExists) {
+			reply = tr.T("collections.exists")
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, reply)
+	msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
+	b.api.Send(msg)
+
+	if err == nil {
+		b.showCollection(chatID, userID, collectionID)
+	}
+}
+
+func (b *Bot) handleCollectionCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	data := callback.Data
+	tr := b.tr(userID)
+
+	switch data {
+	case "collections":
+		b.editCollections(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "col_new":
+		b.promptCollectionName(chatID, userID, collectionActionCreate, 0, 0)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "col_close":
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "col_page":
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "col_next":
+		state := b.getState(userID)
+		if state != nil && state.CollectionID != 0 && state.CollectionNext != nil {
+			state.CollectionCursors = append(state.CollectionCursors, state.CollectionNext)
+			b.saveState(userID, state)
+			b.editCollection(chatID, messageID, userID, state.CollectionID)
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "col_prev":
+		state := b.getState(userID)
+		if state != nil && state.CollectionID != 0 && len(state.CollectionCursors) > 0 {
+			state.CollectionCursors = state.CollectionCursors[:len(state.CollectionCursors)-1]
+			b.saveState(userID, state)
+			b.editCollection(chatID, messageID, userID, state.CollectionID)
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 4 && data[:4] == "col:" {
+		collectionID := 0
+		fmt.Sscanf(data, "col:%d", &collectionID)
+
+		b.editCollection(chatID, messageID, userID, collectionID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 9 && data[:9] == "col_back:" {
+		collectionID := 0
+		fmt.Sscanf(data, "col_back:%d", &collectionID)
+
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.showCollection(chatID, userID, collectionID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 9 && data[:9] == "col_item:" {
+		animeID := 0
+		fmt.Sscanf(data, "col_item:%d", &animeID)
+
+		state := b.getState(userID)
+		if state == nil || state.CollectionID == 0 {
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.short")))
+			return true
+		}
+
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.showCollectionAnime(chatID, userID, state.CollectionID, animeID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 8 && data[:8] == "col_new:" {
+		animeID := 0
+		fmt.Sscanf(data, "col_new:%d", &animeID)
+
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.promptCollectionName(chatID, userID, collectionActionCreate, 0, animeID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 8 && data[:8] == "col_ren:" {
+		collectionID := 0
+		fmt.Sscanf(data, "col_ren:%d", &collectionID)
+
+		b.promptCollectionName(chatID, userID, collectionActionRename, collectionID, 0)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 10 && data[:10] == "col_delok:" {
+		collectionID := 0
+		fmt.Sscanf(data, "col_delok:%d", &collectionID)
+
+		if err := b.animeService.DeleteCollection(userID, collectionID); err != nil {
+			b.logger.Error("Failed to delete collection %d for user %d: %v", collectionID, userID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.delete")))
+			return true
+		}
+
+		b.logger.Info("User %d deleted collection %d", userID, collectionID)
+		state := b.getState(userID)
+		if state != nil && state.CollectionID == collectionID {
+			state.CollectionID = 0
+			state.CollectionCursors = nil
+			b.saveState(userID, state)
+		}
+
+		b.editCollections(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("collections.deleted")))
+		return true
+	}
+
+	if len(data) > 8 && data[:8] == "col_del:" {
+		collectionID := 0
+		fmt.Sscanf(data, "col_del:%d", &collectionID)
+
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, tr.T("collections.delete_confirm"))
+		keyboard := b.createDeleteCollectionKeyboard(tr, collectionID)
+		edit.ReplyMarkup = &keyboard
+		b.api.Send(edit)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 7 && data[:7] == "col_rm:" {
+		collectionID, animeID := 0, 0
+		fmt.Sscanf(data, "col_rm:%d:%d", &collectionID, &animeID)
+
+		if err := b.animeService.RemoveFromCollection(userID, collectionID, animeID); err != nil {
+			b.logger.Error("Failed to remove anime %d from collection %d: %v", animeID, collectionID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.delete")))
+			return true
+		}
+
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("collections.item_removed")))
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.showCollection(chatID, userID, collectionID)
+		return true
+	}
+
+	if len(data) > 7 && data[:7] == "addcol:" {
+		animeID := 0
+		fmt.Sscanf(data, "addcol:%d", &animeID)
+
+		b.showCollectionPicker(chatID, userID, animeID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 7 && data[:7] == "colput:" {
+		collectionID, animeID := 0, 0
+		fmt.Sscanf(data, "colput:%d:%d", &collectionID, &animeID)
+
+		memberOf, _ := b.animeService.GetAnimeCollectionIDs(userID, animeID)
+		inCollection := false
+		for _, id := range memberOf {
+			if id == collectionID {
+				inCollection = true
+			}
+		}
+
+		var err error
+		answer := tr.T("collections.item_added")
+		if inCollection {
+			err = b.animeService.RemoveFromCollection(userID, collectionID, animeID)
+			answer = tr.T("collections.item_removed")
+		} else {
+			err = b.animeService.AddToCollection(userID, collectionID, animeID)
+		}
+		if err != nil {
+			b.logger.Error("Failed to update collection %d for user %d: %v", collectionID, userID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.short")))
+			return true
+		}
+
+		b.editCollectionPicker(chatID, messageID, userID, animeID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, answer))
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+func communityTopText(tr i18n.Localizer, top []models.CommunityRating, minVotes int) string {
+	votes := tr.N("community.min_votes", minVotes, minVotes)
+	if len(top) == 0 {
+		return tr.T("community.empty", votes)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("community.title", votes) + "\n")
+	for i, rating := range top {
+		title := rating.Title
+		if title == "" {
+			title = tr.T("anime.number", rating.AnimeID)
+		}
+		fmt.Fprintf(&sb, "\n%d. %s — %.1f (%d)", i+1, title, rating.Average, rating.Votes)
+	}
+	return sb.String()
+}
+
+func (b *Bot) handleCommunityTop(chatID int64, args string) {
+	minVotes := service.CommunityTopMinVotes
+	if n, err := strconv.Atoi(strings.TrimSpace(args)); err == nil && n > 0 {
+		minVotes = n
+	}
+
+	top, err := b.animeService.GetCommunityTop(minVotes, service.CommunityTopLimit)
+	if err != nil {
+		b.logger.Error("Failed to get community top: %v", err)
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("community.load_failed"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+		b.api.Send(msg)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, communityTopText(b.tr(chatID), top, minVotes))
+	msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+	b.api.Send(msg)
+}
@@ -0,0 +1,73 @@
+package telegram
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+// telegram answers 400 to edits that change nothing, e.g. a double tap
+func isNotModified(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "message is not modified")
+}
+
+// the card was deleted by the user or is too old to be edited
+func isMessageGone(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "message to edit not found") ||
+		strings.Contains(err.Error(), "message can't be edited"))
+}
+
+// cardEdit turns a photo card into another one; the photo is only replaced
+// when file is set, i.e. the anime changed
+func cardEdit(message *tgbotapi.Message, file tgbotapi.RequestFileData, text string, keyboard tgbotapi.InlineKeyboardMarkup) tgbotapi.Chattable {
+	chatID := message.Chat.ID
+	if file == nil {
+		edit := tgbotapi.NewEditMessageCaption(chatID, message.MessageID, text)
+		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = &keyboard
+		return edit
+	}
+
+	media := tgbotapi.NewInputMediaPhoto(file)
+	media.Caption = text
+	media.ParseMode = "Markdown"
+	return tgbotapi.EditMessageMediaConfig{
+		BaseEdit: tgbotapi.BaseEdit{ChatID: chatID, MessageID: message.MessageID, ReplyMarkup: &keyboard},
+		Media:    media,
+	}
+}
+
+// editAnimeCard updates the card in place and falls back to delete and resend
+// when that isn't possible
+func (b *Bot) editAnimeCard(message *tgbotapi.Message, anime *models.Anime, text string, keyboard tgbotapi.InlineKeyboardMarkup, newPhoto bool) {
+	chatID := message.Chat.ID
+
+	// a text card is left from a failed send, telegram can't add a photo to it
+	if len(message.Photo) == 0 {
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, message.MessageID))
+		b.sendAnimeCard(chatID, anime, text, keyboard)
+		return
+	}
+
+	var err error
+	if newPhoto {
+		err = b.deliverPoster(anime, func(file tgbotapi.RequestFileData) (tgbotapi.Message, error) {
+			return b.api.Send(cardEdit(message, file, text, keyboard))
+		})
+	} else {
+		_, err = b.api.Send(cardEdit(message, nil, text, keyboard))
+	}
+
+	switch {
+	case err == nil, isNotModified(err):
+		return
+	case isMessageGone(err):
+		b.sendAnimeCard(chatID, anime, text, keyboard)
+		return
+	}
+	b.logger.Error("Failed to edit card for anime ID %d: %v", anime.ID, err)
+
+	b.api.Send(tgbotapi.NewDeleteMessage(chatID, message.MessageID))
+	b.sendAnimeCard(chatID, anime, text, keyboard)
+}
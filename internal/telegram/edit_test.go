@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestCardEdit(t *testing.T) {
+	message := &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 1}, Photo: []tgbotapi.PhotoSize{{FileID: "p"}}}
+	poster := tgbotapi.FileID("cached")
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+
+	media, ok := cardEdit(message, poster, "text", keyboard).(tgbotapi.EditMessageMediaConfig)
+	if !ok {
+		t.Fatal("expected the photo replaced when the anime changed")
+	}
+	if photo := media.Media.(tgbotapi.InputMediaPhoto); photo.Caption != "text" || photo.Media != poster {
+		t.Errorf("unexpected media: %+v", photo)
+	}
+	if media.ReplyMarkup == nil || media.MessageID != 5 {
+		t.Errorf("unexpected edit: %+v", media.BaseEdit)
+	}
+
+	caption, ok := cardEdit(message, nil, "text", keyboard).(tgbotapi.EditMessageCaptionConfig)
+	if !ok || caption.Caption != "text" || caption.ReplyMarkup == nil {
+		t.Errorf("expected only the caption edited for the same anime, got %+v", caption)
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	notModified := &tgbotapi.Error{Code: 400, Message: "Bad Request: message is not modified: specified new message content and reply markup are exactly the same"}
+	gone := &tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"}
+
+	if !isNotModified(notModified) || isNotModified(gone) || isNotModified(nil) {
+		t.Error("unexpected isNotModified result")
+	}
+	if !isMessageGone(gone) || isMessageGone(notModified) || isMessageGone(errors.New("timeout")) {
+		t.Error("unexpected isMessageGone result")
+	}
+
+	// a bad poster is retried with the next one, problems with the message are not
+	badFile := &tgbotapi.Error{Code: 400, Message: "Bad Request: wrong file identifier/HTTP URL specified"}
+	longCaption := &tgbotapi.Error{Code: 400, Message: "Bad Request: message caption is too long"}
+	if isMessageError(badFile) || !isMessageError(longCaption) || !isMessageError(gone) || isMessageError(nil) {
+		t.Error("unexpected isMessageError result")
+	}
+
+	// only a rejected file_id is forgotten, not a failed request
+	remote := &tgbotapi.Error{Code: 400, Message: "Bad Request: wrong remote file identifier specified: Wrong string length"}
+	serverErr := &tgbotapi.Error{Code: 502, Message: "Bad Gateway"}
+	if !isWrongFileID(badFile) || !isWrongFileID(remote) || isWrongFileID(serverErr) || isWrongFileID(longCaption) ||
+		isWrongFileID(errors.New("context deadline exceeded")) || isWrongFileID(nil) {
+		t.Error("unexpected isWrongFileID result")
+	}
+}
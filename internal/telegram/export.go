@@ -0,0 +1,73 @@
+package telegram
+
+import (
+	"bytes"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/library"
+)
+
+func (b *Bot) handleExport(userID int64, chatID int64, args string) {
+	format, ok := library.ParseFormat(strings.ToLower(strings.TrimSpace(args)))
+	if !ok {
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("export.pick_format"))
+		msg.ReplyMarkup = b.createExportKeyboard()
+		b.api.Send(msg)
+		return
+	}
+
+	b.sendExport(userID, chatID, format)
+}
+
+func (b *Bot) sendExport(userID int64, chatID int64, format library.Format) {
+	tr := b.tr(userID)
+	lib, err := b.animeService.GetLibrary(userID)
+	if err != nil {
+		b.logger.Error("Failed to get library for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("export.failed")))
+		return
+	}
+
+	if len(lib.Entries) == 0 {
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("export.empty")))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := library.Export(&buf, lib, format); err != nil {
+		b.logger.Error("Failed to export library for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("export.failed")))
+		return
+	}
+
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  library.FileName(format, lib.ExportedAt),
+		Bytes: buf.Bytes(),
+	})
+	doc.Caption = tr.T("export.caption")
+	if _, err := b.api.Send(doc); err != nil {
+		b.logger.Error("Failed to send export to user %d: %v", userID, err)
+		return
+	}
+
+	b.logger.Info("User %d exported %d entries as %s", userID, len(lib.Entries), format)
+}
+
+func (b *Bot) handleExportCallback(callback *tgbotapi.CallbackQuery) bool {
+	data := callback.Data
+	if len(data) <= 7 || data[:7] != "export:" {
+		return false
+	}
+
+	tr := b.tr(callback.From.ID)
+	format, ok := library.ParseFormat(data[7:])
+	if !ok {
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("export.unknown_format")))
+		return true
+	}
+
+	b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("export.preparing")))
+	b.sendExport(callback.From.ID, callback.Message.Chat.ID, format)
+	return true
+}
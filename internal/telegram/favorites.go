@@ -0,0 +1,230 @@
+package telegram
+
+import (
+	"math"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+const maxFavoritesSearchLength = 100
+
+var favoriteSortKeys = map[database.FavoriteSort]string{
+	database.FavoriteSortDate:   "sort.date",
+	database.FavoriteSortTitle:  "sort.title",
+	database.FavoriteSortRating: "sort.rating",
+	database.FavoriteSortScore:  "sort.score",
+}
+
+func favoriteSortKey(sort database.FavoriteSort) string {
+	if key, ok := favoriteSortKeys[sort]; ok {
+		return key
+	}
+	return favoriteSortKeys[database.FavoriteSortDate]
+}
+
+func nextFavoriteSort(current database.FavoriteSort) database.FavoriteSort {
+	for i, sort := range database.FavoriteSorts {
+		if sort == current {
+			return database.FavoriteSorts[(i+1)%len(database.FavoriteSorts)]
+		}
+	}
+	return database.FavoriteSorts[1]
+}
+
+// favoritesListView returns a nil keyboard when the user has no favorites at all
+func (b *Bot) favoritesListView(userID int64) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	tr := b.tr(userID)
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+
+	total, err := b.animeService.CountFavoritesMatching(userID, state.FavoritesSearch)
+	if err != nil {
+		return "", nil, err
+	}
+	if total == 0 && state.FavoritesSearch == "" {
+		return tr.T("favorites.empty"), nil, nil
+	}
+
+	pageSize := b.settings(userID).PageSize
+
+	// the list may have shrunk since the cursors were taken
+	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
+	for len(state.FavoritesCursors) > 0 && len(state.FavoritesCursors) >= totalPages {
+		state.FavoritesCursors = state.FavoritesCursors[:len(state.FavoritesCursors)-1]
+	}
+
+	query := database.FavoritesQuery{
+		Sort:   database.FavoriteSort(state.FavoritesSort),
+		Search: state.FavoritesSearch,
+		Limit:  pageSize,
+	}
+	if len(state.FavoritesCursors) > 0 {
+		query.After = state.FavoritesCursors[len(state.FavoritesCursors)-1]
+	}
+
+	favorites, err := b.animeService.GetFavoritesPage(userID, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	state.FavoritesNext = nil
+	if len(favorites) == pageSize {
+		state.FavoritesNext = database.CursorAfter(favorites[len(favorites)-1])
+	}
+	b.saveState(userID, state)
+
+	text := tr.T("favorites.title", total)
+	switch {
+	case state.FavoritesSearch != "" && total == 0:
+		text = tr.T("favorites.search_none", state.FavoritesSearch)
+	case state.FavoritesSearch != "":
+		text = tr.T("favorites.search_found", state.FavoritesSearch, total)
+	}
+
+	keyboard := b.createFavoritesKeyboard(tr, favorites, query.Sort, state.FavoritesSearch != "", len(state.FavoritesCursors), totalPages)
+	return text, &keyboard, nil
+}
+
+func (b *Bot) handleFavorites(userID int64, chatID int64) {
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.CollectionAnimeID = 0
+	state.RatedAnimeID = 0
+	b.saveState(userID, state)
+
+	text, keyboard, err := b.favoritesListView(userID)
+	if err != nil {
+		b.logger.Error("Failed to get favorites for user %d: %v", userID, err)
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("favorites.load_failed"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+		b.api.Send(msg)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	if keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	} else {
+		msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+	}
+	b.api.Send(msg)
+}
+
+func (b *Bot) editFavoritesPage(chatID int64, messageID int, userID int64) {
+	text, keyboard, err := b.favoritesListView(userID)
+	if err != nil {
+		b.logger.Error("Failed to get favorites for user %d: %v", userID, err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ReplyMarkup = keyboard
+	b.api.Send(edit)
+}
+
+func (b *Bot) handleFavoritesSearchInput(userID int64, chatID int64, text string) {
+	state := b.getState(userID)
+	state.WaitingForFavoritesSearch = false
+
+	text = strings.TrimSpace(text)
+	if isCancel(text) || text == "" {
+		b.saveState(userID, state)
+
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("search.cancelled"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+		b.api.Send(msg)
+		return
+	}
+
+	state.FavoritesSearch = utils.TruncateRunes(text, maxFavoritesSearchLength)
+	state.FavoritesCursors = nil
+	b.saveState(userID, state)
+
+	msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("favorites.searching"))
+	msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+	b.api.Send(msg)
+
+	b.handleFavorites(userID, chatID)
+}
+
+func (b *Bot) handleFavoritesCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	switch callback.Data {
+	case "fav_page":
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "fav_next":
+		state := b.getState(userID)
+		if state != nil && state.FavoritesNext != nil {
+			state.FavoritesCursors = append(state.FavoritesCursors, state.FavoritesNext)
+			b.saveState(userID, state)
+			b.editFavoritesPage(chatID, messageID, userID)
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "fav_prev":
+		state := b.getState(userID)
+		if state != nil && len(state.FavoritesCursors) > 0 {
+			state.FavoritesCursors = state.FavoritesCursors[:len(state.FavoritesCursors)-1]
+			b.saveState(userID, state)
+			b.editFavoritesPage(chatID, messageID, userID)
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "fav_sort":
+		state := b.getState(userID)
+		if state == nil {
+			state = &UserState{}
+		}
+		sort := nextFavoriteSort(database.FavoriteSort(state.FavoritesSort))
+		state.FavoritesSort = string(sort)
+		state.FavoritesCursors = nil
+		b.saveState(userID, state)
+
+		b.editFavoritesPage(chatID, messageID, userID)
+		tr := b.tr(userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("sort.changed", strings.ToLower(tr.T(favoriteSortKey(sort))))))
+		return true
+
+	case "fav_search":
+		state := b.getState(userID)
+		if state == nil {
+			state = &UserState{}
+		}
+		state.WaitingForFavoritesSearch = true
+		b.saveState(userID, state)
+
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("favorites.search_prompt"))
+		msg.ReplyMarkup = b.createCancelKeyboard(b.tr(chatID))
+		b.api.Send(msg)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "fav_search_clear":
+		state := b.getState(userID)
+		if state != nil {
+			state.FavoritesSearch = ""
+			state.FavoritesCursors = nil
+			b.saveState(userID, state)
+		}
+
+		b.editFavoritesPage(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	return false
+}
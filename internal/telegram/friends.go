@@ -0,0 +1,192 @@
+package telegram
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+const friendStartPrefix = "friend_"
+
+func friendDeepLink(botName string, code string) string {
+	return startLink(botName, friendStartPrefix+code)
+}
+
+func friendActivityLine(tr i18n.Localizer, event models.FriendActivity, loc *time.Location) string {
+	name := utils.FriendName(event.Username, event.UserID)
+	title := event.Title
+	if title == "" {
+		title = tr.T("anime.number", event.AnimeID)
+	}
+
+	date := event.At.In(loc).Format("02.01")
+	if event.Score == nil {
+		return tr.T("friends.activity.favorite", date, name, title)
+	}
+	return tr.T("friends.activity.rated", date, name, title, *event.Score)
+}
+
+func friendsText(tr i18n.Localizer, code string, link string, following []models.Friend, activity []models.FriendActivity, share bool, loc *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString(tr.T("friends.title", code, link, code) + "\n\n")
+
+	if len(following) == 0 {
+		sb.WriteString(tr.T("friends.following_none") + "\n")
+	} else {
+		names := make([]string, 0, len(following))
+		for _, friend := range following {
+			names = append(names, utils.FriendName(friend.Username, friend.UserID))
+		}
+		sb.WriteString(tr.T("friends.following", strings.Join(names, ", ")) + "\n")
+	}
+
+	if len(activity) > 0 {
+		sb.WriteString("\n" + tr.T("friends.activity") + "\n")
+		for _, event := range activity {
+			sb.WriteString(friendActivityLine(tr, event, loc) + "\n")
+		}
+	} else if len(following) > 0 {
+		sb.WriteString("\n" + tr.T("friends.activity_none") + "\n")
+	}
+
+	if share {
+		sb.WriteString("\n" + tr.T("friends.shared"))
+	} else {
+		sb.WriteString("\n" + tr.T("friends.hidden"))
+	}
+	return sb.String()
+}
+
+func (b *Bot) friendsView(userID int64) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	code, err := b.animeService.GetFriendCode(userID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	following, err := b.animeService.GetFollowing(userID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	activity, err := b.animeService.GetFriendsActivity(userID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	share, err := b.animeService.GetShareList(userID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
+	tr := b.tr(userID)
+	text := friendsText(tr, code, friendDeepLink(b.api.Self.UserName, code), following, activity, share, b.animeService.UserLocation(userID))
+	return text, b.createFriendsKeyboard(tr, following, share), nil
+}
+
+func (b *Bot) handleFriends(userID int64, chatID int64) {
+	text, keyboard, err := b.friendsView(userID)
+	if err != nil {
+		b.logger.Error("Failed to load friends for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(userID).T("friends.load_failed")))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	msg.DisableWebPagePreview = true
+	b.api.Send(msg)
+}
+
+func (b *Bot) editFriends(chatID int64, messageID int, userID int64) {
+	text, keyboard, err := b.friendsView(userID)
+	if err != nil {
+		b.logger.Error("Failed to load friends for user %d: %v", userID, err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	edit.DisableWebPagePreview = true
+	b.api.Send(edit)
+}
+
+func (b *Bot) handleFollow(userID int64, chatID int64, username string, code string) {
+	tr := b.tr(userID)
+	if code == "" {
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("friends.follow_prompt")))
+		return
+	}
+
+	friend, err := b.animeService.FollowByCode(userID, code)
+	switch {
+	case errors.Is(err, service.ErrUnknownFriendCode):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("friends.unknown_code")))
+		return
+	case errors.Is(err, service.ErrCannotFollowSelf):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("friends.own_code")))
+		return
+	case err != nil:
+		b.logger.Error("Failed to follow by code for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("friends.follow_failed")))
+		return
+	}
+
+	name := utils.FriendName(friend.Username, friend.ID)
+	b.api.Send(tgbotapi.NewMessage(chatID, tr.T("friends.followed", name)))
+
+	// let the friend know someone can now see their list, unless they opted out
+	if !b.settings(friend.ID).NotifyFollows {
+		return
+	}
+	notice := b.tr(friend.ID).T("friends.new_follower", utils.FriendName(username, userID))
+	if _, err := b.api.Send(tgbotapi.NewMessage(friend.ID, notice)); err != nil {
+		b.logger.Error("Failed to notify user %d about a new follower: %v", friend.ID, err)
+	}
+}
+
+func (b *Bot) handleFriendsCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	data := callback.Data
+	tr := b.tr(userID)
+
+	switch {
+	case data == "friends_share:on" || data == "friends_share:off":
+		share := data == "friends_share:on"
+		if err := b.animeService.SetShareList(userID, share); err != nil {
+			b.logger.Error("Failed to update privacy for user %d: %v", userID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.save")))
+			return true
+		}
+
+		b.editFriends(chatID, messageID, userID)
+		answer := tr.T("friends.list_opened")
+		if !share {
+			answer = tr.T("friends.list_closed")
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, answer))
+		return true
+
+	case strings.HasPrefix(data, "friends_unfollow:"):
+		friendID, err := strconv.ParseInt(strings.TrimPrefix(data, "friends_unfollow:"), 10, 64)
+		if err != nil {
+			b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+			return true
+		}
+		if err := b.animeService.Unfollow(userID, friendID); err != nil {
+			b.logger.Error("Failed to unfollow for user %d: %v", userID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("friends.unfollow_failed")))
+			return true
+		}
+
+		b.editFriends(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("friends.unfollowed")))
+		return true
+	}
+
+	return false
+}
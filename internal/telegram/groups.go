@@ -0,0 +1,323 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+// chatWatchlistShown keeps /watchlist within a single message
+const chatWatchlistShown = 30
+
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// commandForOtherBot tells "/search@otherbot" apart from our own "/search@ourbot";
+// several bots often share one group
+func commandForOtherBot(message *tgbotapi.Message, botName string) bool {
+	command := message.CommandWithAt()
+	at := strings.Index(command, "@")
+	return at >= 0 && !strings.EqualFold(command[at+1:], botName)
+}
+
+func (b *Bot) joinedChat(message *tgbotapi.Message) bool {
+	for _, member := range message.NewChatMembers {
+		if member.ID == b.api.Self.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) groupHelpText(chatID int64) string {
+	return b.tr(chatID).T("group.help", b.api.Self.UserName)
+}
+
+func chatMemberName(user *tgbotapi.User) string {
+	if user.UserName != "" {
+		return user.UserName
+	}
+	return user.FirstName
+}
+
+// handleGroupMessage serves group chats. With privacy mode on, Telegram only
+// delivers commands and replies to the bot, so nothing here waits for free text
+// and no reply keyboards are shown to the whole chat
+func (b *Bot) handleGroupMessage(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	if message.MigrateToChatID != 0 {
+		if err := b.animeService.MoveChatWatchlist(chatID, message.MigrateToChatID); err != nil {
+			b.logger.Error("Failed to move watchlist of chat %d to %d: %v", chatID, message.MigrateToChatID, err)
+		}
+		b.clearState(chatID)
+		return
+	}
+
+	if b.joinedChat(message) {
+		b.api.Send(tgbotapi.NewMessage(chatID, b.groupHelpText(chatID)))
+		return
+	}
+
+	if message.From == nil || !message.IsCommand() || commandForOtherBot(message, b.api.Self.UserName) {
+		return
+	}
+	b.animeService.EnsureUserExists(message.From.ID, chatMemberName(message.From))
+	if err := b.animeService.TouchChatMember(chatID, message.From.ID); err != nil {
+		b.logger.Error("Failed to save member %d of chat %d: %v", message.From.ID, chatID, err)
+	}
+
+	switch message.Command() {
+	case "start", "help":
+		b.api.Send(tgbotapi.NewMessage(chatID, b.groupHelpText(chatID)))
+	case "search":
+		b.handleGroupSearch(chatID, strings.TrimSpace(message.CommandArguments()))
+	case "watchlist":
+		b.showChatWatchlist(chatID)
+	case "vote":
+		b.handleVote(message)
+	default:
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("group.private_only", b.api.Self.UserName))
+		msg.ReplyToMessageID = message.MessageID
+		b.api.Send(msg)
+	}
+}
+
+func (b *Bot) handleGroupSearch(chatID int64, query string) {
+	tr := b.tr(chatID)
+	if query == "" {
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("group.search_prompt")))
+		return
+	}
+
+	animes, err := b.animeService.SearchAnime(query)
+	if err != nil {
+		b.logger.Error("Search failed for chat %d, query '%s': %v", chatID, query, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("error.generic", err)))
+		return
+	}
+
+	// the whole chat browses one set of results, kept under the chat id
+	b.saveState(chatID, &UserState{SearchResults: animes})
+	b.showGroupAnime(chatID)
+}
+
+func chatWatchlistLine(tr i18n.Localizer, item *models.ChatWatchlistItem) string {
+	if item == nil {
+		return ""
+	}
+	if item.AddedByName == "" {
+		return tr.T("group.in_watchlist")
+	}
+	return tr.T("group.in_watchlist_by", utils.EscapeMarkdownText(item.AddedByName))
+}
+
+func (b *Bot) showGroupAnime(chatID int64) {
+	anime, text, keyboard := b.groupAnimeCard(chatID)
+	if anime == nil {
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("group.results_expired")))
+		return
+	}
+
+	b.sendAnimeCard(chatID, anime, text, keyboard)
+}
+
+// editGroupAnime turns the group's card into the current search result
+func (b *Bot) editGroupAnime(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	anime, text, keyboard := b.groupAnimeCard(chatID)
+	if anime == nil {
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, message.MessageID))
+		b.showGroupAnime(chatID)
+		return
+	}
+
+	b.editAnimeCard(message, anime, text, keyboard, true)
+}
+
+// groupAnimeCard renders the search result the chat is on, anime is nil when
+// the results are gone
+func (b *Bot) groupAnimeCard(chatID int64) (*models.Anime, string, tgbotapi.InlineKeyboardMarkup) {
+	anime := b.getCurrentAnime(chatID)
+	if anime == nil {
+		return nil, "", tgbotapi.InlineKeyboardMarkup{}
+	}
+
+	community, _ := b.animeService.GetCommunityRating(anime.ID)
+	alsoLiked, _ := b.animeService.GetAlsoLiked(anime.ID)
+	item, _ := b.animeService.GetChatWatchlistItem(chatID, anime.ID)
+
+	tr := b.tr(chatID)
+	text := utils.FormatAnimeMessageWithRating(tr, service.DefaultSettings(chatID), anime, false, nil, nil, community, alsoLiked, nil)
+	if line := chatWatchlistLine(tr, item); line != "" {
+		if utf8.RuneCountInString(text)+utf8.RuneCountInString(line)+2 <= utils.CaptionMaxLength {
+			text += "\n\n" + line
+		}
+	}
+
+	keyboard := b.createGroupAnimeKeyboard(tr, b.getState(chatID), anime.ID, item != nil)
+	return anime, text, keyboard
+}
+
+func chatWatchlistText(tr i18n.Localizer, items []models.ChatWatchlistItem) string {
+	if len(items) == 0 {
+		return tr.T("group.watchlist_empty", tr.T("button.add_to_chat"))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("group.watchlist_title", len(items)) + "\n")
+	for i, item := range items {
+		if i == chatWatchlistShown {
+			sb.WriteString("\n" + tr.T("list.more", len(items)-chatWatchlistShown))
+			break
+		}
+		added := item.AddedAt.Format("02.01.2006")
+		if item.AddedByName != "" {
+			added = item.AddedByName + ", " + added
+		}
+		fmt.Fprintf(&sb, "\n%d. %s (%s)", i+1, item.Title, added)
+	}
+	return sb.String()
+}
+
+func (b *Bot) showChatWatchlist(chatID int64) {
+	tr := b.tr(chatID)
+	items, err := b.animeService.GetChatWatchlist(chatID)
+	if err != nil {
+		b.logger.Error("Failed to get watchlist of chat %d: %v", chatID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("group.watchlist_failed")))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, chatWatchlistText(tr, items))
+	if len(items) > 0 {
+		msg.ReplyMarkup = b.createChatWatchlistKeyboard(items)
+	}
+	b.api.Send(msg)
+}
+
+// canRemoveFromChat lets the member who added an entry or a chat admin take it off
+func (b *Bot) canRemoveFromChat(chatID int64, userID int64, item *models.ChatWatchlistItem) bool {
+	if item.AddedBy != nil && *item.AddedBy == userID {
+		return true
+	}
+	return b.isChatAdmin(chatID, userID)
+}
+
+func (b *Bot) isChatAdmin(chatID int64, userID int64) bool {
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+	if err != nil {
+		b.logger.Error("Failed to get member %d of chat %d: %v", userID, chatID, err)
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+func (b *Bot) handleGroupCallback(callback *tgbotapi.CallbackQuery) bool {
+	data := callback.Data
+	if !strings.HasPrefix(data, "grp_") || callback.Message == nil {
+		return false
+	}
+
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	tr := b.tr(chatID)
+
+	switch {
+	case data == "grp_next" || data == "grp_prev":
+		state := b.getState(chatID)
+		if state != nil && len(state.SearchResults) > 0 {
+			if data == "grp_next" {
+				state.CurrentIndex = (state.CurrentIndex + 1) % len(state.SearchResults)
+			} else {
+				state.CurrentIndex = (state.CurrentIndex - 1 + len(state.SearchResults)) % len(state.SearchResults)
+			}
+			b.saveState(chatID, state)
+			b.editGroupAnime(callback.Message)
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case data == "grp_list":
+		b.showChatWatchlist(chatID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case strings.HasPrefix(data, "grp_add:"):
+		animeID, _ := strconv.Atoi(strings.TrimPrefix(data, "grp_add:"))
+		anime, err := b.animeService.GetAnimeByID(animeID)
+		if err != nil {
+			b.logger.Error("Failed to get anime details: %v", err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("anime.load_failed")))
+			return true
+		}
+
+		// members who only ever pressed buttons have no user row yet
+		name := chatMemberName(callback.From)
+		b.animeService.EnsureUserExists(userID, name)
+		if err := b.animeService.TouchChatMember(chatID, userID); err != nil {
+			b.logger.Error("Failed to save member %d of chat %d: %v", userID, chatID, err)
+		}
+		added, err := b.animeService.AddToChatWatchlist(chatID, userID, anime)
+		if err != nil {
+			b.logger.Error("Failed to add anime %d to watchlist of chat %d: %v", animeID, chatID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("group.add_failed")))
+			return true
+		}
+		if !added {
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("group.already_added")))
+			return true
+		}
+
+		keyboard := b.createGroupAnimeKeyboard(tr, b.getState(chatID), animeID, true)
+		b.api.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, keyboard))
+		title := utils.AnimeTitle(tr, service.DefaultSettings(chatID), anime)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("group.added", name, title)))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("group.added_short")))
+		return true
+
+	case strings.HasPrefix(data, "grp_remove:"):
+		animeID, _ := strconv.Atoi(strings.TrimPrefix(data, "grp_remove:"))
+		item, err := b.animeService.GetChatWatchlistItem(chatID, animeID)
+		if err != nil || item == nil {
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("group.not_in_watchlist")))
+			return true
+		}
+		if !b.canRemoveFromChat(chatID, userID, item) {
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("group.remove_forbidden")))
+			return true
+		}
+		if err := b.animeService.RemoveFromChatWatchlist(chatID, animeID); err != nil {
+			b.logger.Error("Failed to remove anime %d from watchlist of chat %d: %v", animeID, chatID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.delete")))
+			return true
+		}
+
+		items, _ := b.animeService.GetChatWatchlist(chatID)
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, chatWatchlistText(tr, items))
+		if len(items) > 0 {
+			keyboard := b.createChatWatchlistKeyboard(items)
+			edit.ReplyMarkup = &keyboard
+		}
+		b.api.Send(edit)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("group.removed")))
+		return true
+
+	case data == "grp_position":
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	return false
+}
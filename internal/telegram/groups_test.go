@@ -0,0 +1,68 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+)
+
+func commandMessage(text string) *tgbotapi.Message {
+	length := strings.IndexByte(text, ' ')
+	if length < 0 {
+		length = len(text)
+	}
+	return &tgbotapi.Message{
+		Text:     text,
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: length}},
+	}
+}
+
+func TestCommandForOtherBot(t *testing.T) {
+	tests := []struct {
+		text  string
+		other bool
+	}{
+		{"/search bebop", false},
+		{"/search@AnimeTrackerBot bebop", false},
+		{"/search@animetrackerbot", false},
+		{"/search@OtherBot bebop", true},
+	}
+
+	for _, tt := range tests {
+		message := commandMessage(tt.text)
+		if got := commandForOtherBot(message, "AnimeTrackerBot"); got != tt.other {
+			t.Errorf("%q: expected %v, got %v", tt.text, tt.other, got)
+		}
+		if message.Command() != "search" {
+			t.Errorf("%q: expected the suffix to be stripped, got %q", tt.text, message.Command())
+		}
+	}
+}
+
+func TestIsGroupChat(t *testing.T) {
+	if isGroupChat(&tgbotapi.Chat{Type: "private"}) || isGroupChat(nil) {
+		t.Error("private chats are not groups")
+	}
+	if !isGroupChat(&tgbotapi.Chat{Type: "group"}) || !isGroupChat(&tgbotapi.Chat{Type: "supergroup"}) {
+		t.Error("expected groups and supergroups")
+	}
+}
+
+func TestChatWatchlistText(t *testing.T) {
+	addedBy := int64(1)
+	items := []models.ChatWatchlistItem{
+		{AnimeID: 1, Title: "Monster", AddedBy: &addedBy, AddedByName: "alice", AddedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{AnimeID: 2, Title: "Mushishi", AddedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	text := chatWatchlistText(ru, items)
+	if !strings.Contains(text, "1. Monster (alice, 01.03.2026)") || !strings.Contains(text, "2. Mushishi (02.03.2026)") {
+		t.Errorf("unexpected watchlist text: %q", text)
+	}
+	if !strings.Contains(chatWatchlistText(ru, nil), "пуст") {
+		t.Error("expected empty list hint")
+	}
+}
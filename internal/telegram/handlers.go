@@ -2,9 +2,9 @@ package telegram
 
 import (
 	"fmt"
-	"math"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
 	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
 )
@@ -12,8 +12,15 @@ import (
 const favoritesPerPage = 10
 
 func (b *Bot) handleMessage(message *tgbotapi.Message) {
+	if isGroupChat(message.Chat) {
+		b.handleGroupMessage(message)
+		return
+	}
+
 	userID := message.From.ID
 	chatID := message.Chat.ID
+	b.rememberLanguage(message.From)
+	tr := b.tr(userID)
 
 	username := message.From.UserName
 	if username == "" {
@@ -21,14 +28,19 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 	}
 	b.animeService.EnsureUserExists(userID, username)
 
+	if message.Document != nil {
+		b.handleImportDocument(userID, chatID, message.Document)
+		return
+	}
+
 	state := b.getState(userID)
 	if state != nil && state.WaitingForSearch {
-		if message.Text == "Отмена" {
+		if isCancel(message.Text) {
 			state.WaitingForSearch = false
 			b.saveState(userID, state)
 
-			msg := tgbotapi.NewMessage(chatID, "Поиск отменен.")
-			msg.ReplyMarkup = b.createMainMenuKeyboard()
+			msg := tgbotapi.NewMessage(chatID, tr.T("search.cancelled"))
+			msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 			b.api.Send(msg)
 			return
 		}
@@ -37,10 +49,32 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 		return
 	}
 
+	if state != nil && state.WaitingForNote {
+		if !message.IsCommand() {
+			b.handleNoteInput(userID, chatID, message.Text)
+			return
+		}
+		// a command abandons the note prompt instead of becoming the note
+		state.WaitingForNote = false
+		state.NoteAnimeID = 0
+		b.saveState(userID, state)
+	}
+
+	if state != nil && state.WaitingForCollectionName {
+		b.handleCollectionNameInput(userID, chatID, message.Text)
+		return
+	}
+
+	if state != nil && state.WaitingForFavoritesSearch {
+		b.handleFavoritesSearchInput(userID, chatID, message.Text)
+		return
+	}
+
 	if message.IsCommand() {
 		switch message.Command() {
 		case "start":
 			b.handleStart(message)
+			b.handleStartPayload(userID, chatID, username, message.CommandArguments())
 		case "search":
 			query := message.CommandArguments()
 			if query != "" {
@@ -50,45 +84,68 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 			}
 		case "favorites":
 			b.handleFavorites(userID, chatID)
+		case "collections":
+			b.handleCollections(userID, chatID)
+		case "ratings":
+			b.handleRatings(userID, chatID)
+		case "community_top":
+			b.handleCommunityTop(chatID, message.CommandArguments())
+		case "recommend":
+			b.handleRecommend(userID, chatID)
+		case "stats":
+			b.handleStats(userID, chatID, message.CommandArguments())
+		case "wrapped":
+			b.handleWrapped(userID, chatID, message.CommandArguments())
+		case "friends":
+			b.handleFriends(userID, chatID)
+		case "follow":
+			b.handleFollow(userID, chatID, username, message.CommandArguments())
+		case "export":
+			b.handleExport(userID, chatID, message.CommandArguments())
+		case "import":
+			b.handleImportHelp(chatID)
+		case "language":
+			b.handleLanguage(userID, chatID)
+		case "settings":
+			b.handleSettings(userID, chatID, message.CommandArguments())
+		case "deleteme":
+			b.handleDeleteMe(chatID)
 		}
 		return
 	}
 
-	switch message.Text {
-	case "Поиск":
+	// the menu may have been drawn in another language, so labels are
+	// matched against every catalog
+	button, _ := i18n.Match(message.Text, "menu.search", "menu.favorites", "menu.help")
+	switch button {
+	case "menu.search":
 		b.handleSearchButton(userID, chatID)
-	case "Избранное":
+	case "menu.favorites":
 		b.handleFavorites(userID, chatID)
-	case "Помощь":
+	case "menu.help":
 		b.handleHelp(message)
 	default:
-		msg := tgbotapi.NewMessage(chatID, "Используй кнопки меню или команды")
-		msg.ReplyMarkup = b.createMainMenuKeyboard()
+		msg := tgbotapi.NewMessage(chatID, tr.T("menu.fallback"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 		b.api.Send(msg)
 	}
 }
 
 func (b *Bot) handleHelp(message *tgbotapi.Message) {
-	text := "ℹ️ Справка:\n\n" +
-		"Поиск - найти аниме по названию\n" +
-		"Избранное - список сохраненных аниме\n\n" +
-		"Команды:\n" +
-		"/search <название> - поиск\n" +
-		"/favorites - избранное"
+	tr := b.tr(message.Chat.ID)
+	text := tr.T("help.title") + "\n\n" + tr.T("help.buttons") + "\n\n" + tr.T("help.commands")
 
 	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ReplyMarkup = b.createMainMenuKeyboard()
+	msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 	b.api.Send(msg)
 }
 
 func (b *Bot) handleStart(message *tgbotapi.Message) {
-	text := "Привет! Я бот для поиска аниме.\n\n" +
-		"Команды:\n" +
-		"/search <название> - поиск аниме\n" +
-		"/favorites - твое избранное"
+	tr := b.tr(message.Chat.ID)
+	text := tr.T("start.greeting") + "\n\n" + tr.T("help.commands")
 
 	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ReplyMarkup = b.createMainMenuKeyboard()
+	msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 	b.api.Send(msg)
 }
 
@@ -100,8 +157,9 @@ func (b *Bot) handleSearchButton(userID int64, chatID int64) {
 	state.WaitingForSearch = true
 	b.saveState(userID, state)
 
-	msg := tgbotapi.NewMessage(chatID, "Напиши название аниме для поиска:")
-	msg.ReplyMarkup = b.createCancelKeyboard()
+	tr := b.tr(userID)
+	msg := tgbotapi.NewMessage(chatID, tr.T("search.prompt"))
+	msg.ReplyMarkup = b.createCancelKeyboard(tr)
 	b.api.Send(msg)
 }
 
@@ -114,18 +172,19 @@ func (b *Bot) handleSearch(userID int64, chatID int64, query string) {
 
 	b.logger.Info("User %d searching for: %s", userID, query)
 
+	tr := b.tr(userID)
 	if query == "" {
-		msg := tgbotapi.NewMessage(chatID, "Укажи название. Например: /search bebop")
-		msg.ReplyMarkup = b.createMainMenuKeyboard()
+		msg := tgbotapi.NewMessage(chatID, tr.T("search.empty_query"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 		b.api.Send(msg)
 		return
 	}
 
-	animes, err := b.animeService.SearchAnime(query)
+	animes, err := b.animeService.SearchAnimeFor(userID, query)
 	if err != nil {
 		b.logger.Error("Search failed for user %d, query '%s': %v", userID, query, err)
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %v", err))
-		msg.ReplyMarkup = b.createMainMenuKeyboard()
+		msg := tgbotapi.NewMessage(chatID, tr.T("error.generic", err))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 		b.api.Send(msg)
 		return
 	}
@@ -141,111 +200,158 @@ func (b *Bot) handleSearch(userID int64, chatID int64, query string) {
 	b.showCurrentAnime(chatID, userID)
 }
 
-func (b *Bot) handleFavorites(userID int64, chatID int64) {
+func (b *Bot) handleNoteInput(userID int64, chatID int64, text string) {
 	state := b.getState(userID)
-	if state == nil {
-		state = &UserState{FavoritesPage: 0}
-		b.saveState(userID, state)
-	}
+	animeID := state.NoteAnimeID
+	state.WaitingForNote = false
+	state.NoteAnimeID = 0
+	b.saveState(userID, state)
 
-	favorites, err := b.animeService.GetUserFavorites(userID)
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "Ошибка получения избранного")
-		msg.ReplyMarkup = b.createMainMenuKeyboard()
+	tr := b.tr(userID)
+	var reply string
+	switch {
+	case isCancel(text):
+		msg := tgbotapi.NewMessage(chatID, tr.T("note.unchanged"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 		b.api.Send(msg)
 		return
+	case text == "-":
+		if err := b.animeService.DeleteNote(userID, animeID); err != nil {
+			b.logger.Error("Failed to delete note: user %d, anime %d: %v", userID, animeID, err)
+			reply = tr.T("note.delete_failed")
+		} else {
+			reply = tr.T("note.deleted")
+		}
+	default:
+		if err := b.animeService.SaveNote(userID, animeID, text); err != nil {
+			b.logger.Error("Failed to save note: user %d, anime %d: %v", userID, animeID, err)
+			reply = tr.T("error.generic", err)
+		} else {
+			b.logger.Info("User %d saved note for anime %d", userID, animeID)
+			reply = tr.T("note.saved")
+		}
 	}
 
-	if len(favorites) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "Твое избранное пусто. Добавь аниме через поиск!")
-		msg.ReplyMarkup = b.createMainMenuKeyboard()
+	msg := tgbotapi.NewMessage(chatID, reply)
+	msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
+	b.api.Send(msg)
+
+	b.reshowAnime(chatID, userID, animeID)
+}
+
+// reshowAnime sends the card the user was looking at before a rating or note prompt
+func (b *Bot) reshowAnime(chatID int64, userID int64, animeID int) {
+	state := b.getState(userID)
+	switch {
+	case state != nil && state.CollectionID != 0 && state.CollectionAnimeID == animeID:
+		b.showCollectionAnime(chatID, userID, state.CollectionID, animeID)
+	case state != nil && state.RatedAnimeID == animeID:
+		b.showRatedAnime(chatID, userID, animeID)
+	case state != nil && len(state.SearchResults) > 0:
+		b.showCurrentAnime(chatID, userID)
+	default:
+		b.showFavoriteAnime(chatID, userID, animeID)
+	}
+}
+
+func (b *Bot) showCurrentAnime(chatID int64, userID int64) {
+	anime, text, keyboard := b.currentAnimeCard(userID)
+	if anime == nil {
+		tr := b.tr(userID)
+		msg := tgbotapi.NewMessage(chatID, tr.T("anime.not_found"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
 		b.api.Send(msg)
 		return
 	}
 
-	b.showFavoritesPage(chatID, userID, favorites)
+	b.sendAnimeCard(chatID, anime, text, keyboard)
 }
 
-func (b *Bot) showFavoritesPage(chatID int64, userID int64, favorites []models.Favorite) {
-	state := b.getState(userID)
-	if state == nil {
-		state = &UserState{FavoritesPage: 0}
-		b.saveState(userID, state)
+// currentAnimeCard renders the search result the user is on, anime is nil
+// when the results are gone
+func (b *Bot) currentAnimeCard(userID int64) (*models.Anime, string, tgbotapi.InlineKeyboardMarkup) {
+	tr := b.tr(userID)
+	anime := b.getCurrentAnime(userID)
+	if anime == nil {
+		return nil, "", tgbotapi.InlineKeyboardMarkup{}
 	}
 
-	totalPages := int(math.Ceil(float64(len(favorites)) / float64(favoritesPerPage)))
-	currentPage := state.FavoritesPage
+	isFav, _ := b.animeService.IsFavorite(userID, anime.ID)
+	userRating, _ := b.animeService.GetUserRating(userID, anime.ID)
+	note, _ := b.animeService.GetUserNote(userID, anime.ID)
+	community, _ := b.animeService.GetCommunityRating(anime.ID)
+	alsoLiked, _ := b.animeService.GetAlsoLiked(anime.ID)
+	friends, _ := b.animeService.GetFriendRatings(userID, anime.ID)
+
+	text := utils.FormatAnimeMessageWithRating(tr, b.settings(userID), anime, isFav, userRating, note, community, alsoLiked, friends)
+	keyboard := b.createAnimeKeyboard(tr, userID, anime.ID, isFav, userRating)
+	return anime, text, keyboard
+}
+
+func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	data := callback.Data
 
-	if currentPage < 0 {
-		currentPage = 0
+	b.logger.Debug("User %d clicked callback: %s", userID, data)
+
+	b.rememberLanguage(callback.From)
+	tr := b.tr(userID)
+
+	if b.handleLanguageCallback(callback) {
+		return
 	}
-	if currentPage >= totalPages {
-		currentPage = totalPages - 1
+
+	if b.handleSettingsCallback(callback) {
+		return
 	}
-	state.FavoritesPage = currentPage
-	b.saveState(userID, state)
 
-	text := fmt.Sprintf("❤️ Твое избранное (%d):\n\nВыбери аниме для просмотра:", len(favorites))
+	if b.handleCollectionCallback(callback) {
+		return
+	}
 
-	keyboard := b.createFavoritesKeyboard(favorites, currentPage, totalPages)
+	if b.handleRatingsCallback(callback) {
+		return
+	}
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ReplyMarkup = keyboard
-	b.api.Send(msg)
-}
+	if b.handleFavoritesCallback(callback) {
+		return
+	}
 
-func (b *Bot) showCurrentAnime(chatID int64, userID int64) {
-	anime := b.getCurrentAnime(userID)
-	if anime == nil {
-		msg := tgbotapi.NewMessage(chatID, "Аниме не найдено")
-		msg.ReplyMarkup = b.createMainMenuKeyboard()
-		b.api.Send(msg)
+	if b.handleExportCallback(callback) {
 		return
 	}
 
-	isFav, _ := b.animeService.IsFavorite(userID, anime.ID)
-	userRating, _ := b.animeService.GetUserRating(userID, anime.ID)
+	if b.handleAccountCallback(callback) {
+		return
+	}
 
-	text := utils.FormatAnimeMessageWithRating(anime, isFav, userRating)
-	keyboard := b.createAnimeKeyboard(userID, anime.ID, isFav, userRating)
+	if b.handleStatsCallback(callback) {
+		return
+	}
 
-	if anime.Image.Original != "" || anime.Image.Preview != "" {
-		baseURL := "https://shikimori.one"
-		imagePath := anime.Image.Original
-		if imagePath == "" {
-			imagePath = anime.Image.Preview
-		}
+	if b.handleWrappedCallback(callback) {
+		return
+	}
 
-		fullURL := baseURL + imagePath
-
-		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(fullURL))
-		photo.Caption = text
-		photo.ParseMode = "Markdown"
-		photo.ReplyMarkup = keyboard
-		if _, err := b.api.Send(photo); err != nil {
-			b.logger.Error("Failed to send photo for anime ID %d: %v", anime.ID, err)
-			msg := tgbotapi.NewMessage(chatID, text+"\n\n⚠️ Изображение недоступно")
-			msg.ParseMode = "Markdown"
-			msg.ReplyMarkup = keyboard
-			if _, err := b.api.Send(msg); err != nil {
-				b.logger.Error("Failed to send fallback message: %v", err)
-			}
-		}
-	} else {
-		msg := tgbotapi.NewMessage(chatID, text)
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		if _, err := b.api.Send(msg); err != nil {
-			b.logger.Error("Failed to send message for anime ID %d: %v", anime.ID, err)
-		}
+	if b.handleRecommendCallback(callback) {
+		return
 	}
-}
 
-func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
-	userID := callback.From.ID
-	data := callback.Data
+	if b.handleFriendsCallback(callback) {
+		return
+	}
 
-	b.logger.Debug("User %d clicked callback: %s", userID, data)
+	if b.handleShareCallback(callback) {
+		return
+	}
+
+	if b.handleVoteCallback(callback) {
+		return
+	}
+
+	if b.handleGroupCallback(callback) {
+		return
+	}
 
 	if len(data) > 5 && data[:5] == "rate:" {
 		animeID := 0
@@ -259,7 +365,7 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 		state.WaitingForRating = true
 		b.saveState(userID, state)
 
-		keyboard := b.createRatingKeyboard(animeID)
+		keyboard := b.createRatingKeyboard(tr, animeID)
 
 		edit := tgbotapi.NewEditMessageReplyMarkup(
 			callback.Message.Chat.ID,
@@ -267,7 +373,31 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 			keyboard,
 		)
 		b.api.Send(edit)
-		b.api.Send(tgbotapi.NewCallback(callback.ID, "Выбери оценку от 1 до 10"))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("rating.choose")))
+		return
+	}
+
+	if len(data) > 5 && data[:5] == "note:" {
+		animeID := 0
+		fmt.Sscanf(data, "note:%d", &animeID)
+
+		state := b.getState(userID)
+		if state == nil {
+			state = &UserState{}
+		}
+		state.NoteAnimeID = animeID
+		state.WaitingForNote = true
+		b.saveState(userID, state)
+
+		text := tr.T("note.prompt")
+		if note, _ := b.animeService.GetUserNote(userID, animeID); note != nil {
+			text = tr.T("note.current", note.Text) + text
+		}
+
+		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, text)
+		msg.ReplyMarkup = b.createCancelKeyboard(tr)
+		b.api.Send(msg)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
 		return
 	}
 
@@ -279,12 +409,12 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 		err := b.animeService.AddRating(userID, animeID, score)
 		if err != nil {
 			b.logger.Error("Failed to add rating: %v", err)
-			b.api.Send(tgbotapi.NewCallback(callback.ID, "Ошибка при сохранении оценки"))
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("rating.save_failed")))
 			return
 		}
 
 		b.logger.Info("User %d rated anime %d with score %d", userID, animeID, score)
-		b.api.Send(tgbotapi.NewCallback(callback.ID, fmt.Sprintf("✅ Оценка %d сохранена", score)))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("rating.saved", score)))
 
 		deleteMsg := tgbotapi.NewDeleteMessage(callback.Message.Chat.ID, callback.Message.MessageID)
 		b.api.Send(deleteMsg)
@@ -295,11 +425,7 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 			state.RatingAnimeID = 0
 			b.saveState(userID, state)
 
-			if len(state.SearchResults) > 0 {
-				b.showCurrentAnime(callback.Message.Chat.ID, userID)
-			} else {
-				b.showFavoriteAnime(callback.Message.Chat.ID, userID, animeID)
-			}
+			b.reshowAnime(callback.Message.Chat.ID, userID, animeID)
 		}
 		return
 	}
@@ -313,7 +439,7 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 				state.CurrentIndex = 0
 			}
 			b.saveState(userID, state)
-			b.editCurrentAnime(callback.Message.Chat.ID, callback.Message.MessageID, userID)
+			b.editCurrentAnime(callback.Message, userID, true)
 		}
 		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
 		return
@@ -326,7 +452,7 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 				state.CurrentIndex = len(state.SearchResults) - 1
 			}
 			b.saveState(userID, state)
-			b.editCurrentAnime(callback.Message.Chat.ID, callback.Message.MessageID, userID)
+			b.editCurrentAnime(callback.Message, userID, true)
 		}
 		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
 		return
@@ -335,34 +461,6 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
 		return
 
-	case "fav_next":
-		state := b.getState(userID)
-		if state != nil {
-			state.FavoritesPage++
-			b.saveState(userID, state)
-
-			favorites, _ := b.animeService.GetUserFavorites(userID)
-			b.editFavoritesPage(callback.Message.Chat.ID, callback.Message.MessageID, userID, favorites)
-		}
-		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
-		return
-
-	case "fav_prev":
-		state := b.getState(userID)
-		if state != nil && state.FavoritesPage > 0 {
-			state.FavoritesPage--
-			b.saveState(userID, state)
-
-			favorites, _ := b.animeService.GetUserFavorites(userID)
-			b.editFavoritesPage(callback.Message.Chat.ID, callback.Message.MessageID, userID, favorites)
-		}
-		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
-		return
-
-	case "fav_page":
-		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
-		return
-
 	case "back_to_favs":
 		deleteMsg := tgbotapi.NewDeleteMessage(callback.Message.Chat.ID, callback.Message.MessageID)
 		b.api.Send(deleteMsg)
@@ -381,13 +479,9 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 			deleteMsg := tgbotapi.NewDeleteMessage(callback.Message.Chat.ID, callback.Message.MessageID)
 			b.api.Send(deleteMsg)
 
-			if len(state.SearchResults) > 0 {
-				b.showCurrentAnime(callback.Message.Chat.ID, userID)
-			} else {
-				b.showFavoriteAnime(callback.Message.Chat.ID, userID, animeID)
-			}
+			b.reshowAnime(callback.Message.Chat.ID, userID, animeID)
 		}
-		b.api.Send(tgbotapi.NewCallback(callback.ID, "Отменено"))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("cancelled")))
 		return
 	}
 
@@ -410,12 +504,12 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 		err := b.animeService.RemoveFromFavorites(userID, animeID)
 		if err != nil {
 			b.logger.Error("Failed to delete from favorites: user %d, anime %d: %v", userID, animeID, err)
-			b.api.Send(tgbotapi.NewCallback(callback.ID, "Ошибка удаления"))
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("favorites.rm_error")))
 			return
 		}
 
 		b.logger.Info("User %d deleted anime %d from favorites", userID, animeID)
-		b.api.Send(tgbotapi.NewCallback(callback.ID, "💔 Удалено из избранного"))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("favorites.removed")))
 
 		deleteMsg := tgbotapi.NewDeleteMessage(callback.Message.Chat.ID, callback.Message.MessageID)
 		b.api.Send(deleteMsg)
@@ -429,21 +523,21 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 
 		anime := b.getCurrentAnime(userID)
 		if anime == nil {
-			b.api.Send(tgbotapi.NewCallback(callback.ID, "Ошибка"))
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.short")))
 			return
 		}
 
 		err := b.animeService.AddToFavorites(userID, *anime)
 		if err != nil {
 			b.logger.Error("Failed to add to favorites: user %d, anime %d: %v", userID, animeID, err)
-			b.api.Send(tgbotapi.NewCallback(callback.ID, "Ошибка добавления"))
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("favorites.add_error")))
 			return
 		}
 
 		b.logger.Info("User %d added anime %d to favorites", userID, animeID)
-		b.api.Send(tgbotapi.NewCallback(callback.ID, "✅ Добавлено в избранное"))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("favorites.added")))
 
-		b.editCurrentAnime(callback.Message.Chat.ID, callback.Message.MessageID, userID)
+		b.editCurrentAnime(callback.Message, userID, false)
 		return
 	}
 
@@ -454,87 +548,50 @@ func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 		err := b.animeService.RemoveFromFavorites(userID, animeID)
 		if err != nil {
 			b.logger.Error("Failed to delete from favorites: user %d, anime %d: %v", userID, animeID, err)
-			b.api.Send(tgbotapi.NewCallback(callback.ID, "Ошибка удаления"))
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("favorites.rm_error")))
 			return
 		}
 
 		b.logger.Info("User %d deleted anime %d from favorites", userID, animeID)
-		b.api.Send(tgbotapi.NewCallback(callback.ID, "💔 Удалено из избранного"))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("favorites.removed")))
 
-		b.editCurrentAnime(callback.Message.Chat.ID, callback.Message.MessageID, userID)
+		b.editCurrentAnime(callback.Message, userID, false)
 		return
 	}
 }
 
-func (b *Bot) editCurrentAnime(chatID int64, messageID int, userID int64) {
-	deleteMsg := tgbotapi.NewDeleteMessage(chatID, messageID)
-	b.api.Send(deleteMsg)
-
-	b.showCurrentAnime(chatID, userID)
-}
-
-func (b *Bot) editFavoritesPage(chatID int64, messageID int, userID int64, favorites []models.Favorite) {
-	state := b.getState(userID)
-	if state == nil {
+// editCurrentAnime redraws the search card in place, newPhoto is set when
+// navigation moved to another anime
+func (b *Bot) editCurrentAnime(message *tgbotapi.Message, userID int64, newPhoto bool) {
+	anime, text, keyboard := b.currentAnimeCard(userID)
+	if anime == nil {
+		b.api.Send(tgbotapi.NewDeleteMessage(message.Chat.ID, message.MessageID))
+		b.showCurrentAnime(message.Chat.ID, userID)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(len(favorites)) / float64(favoritesPerPage)))
-	currentPage := state.FavoritesPage
-
-	if currentPage < 0 {
-		currentPage = 0
-	}
-	if currentPage >= totalPages {
-		currentPage = totalPages - 1
-	}
-
-	text := fmt.Sprintf("❤️ Твое избранное (%d):\n\nВыбери нужное аниме:", len(favorites))
-
-	keyboard := b.createFavoritesKeyboard(favorites, currentPage, totalPages)
-
-	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
-	edit.ReplyMarkup = &keyboard
-	b.api.Send(edit)
+	b.editAnimeCard(message, anime, text, keyboard, newPhoto)
 }
 
 func (b *Bot) showFavoriteAnime(chatID int64, userID int64, animeID int) {
 	b.logger.Info("User %d viewing favorite anime ID: %d", userID, animeID)
+	tr := b.tr(userID)
 	anime, err := b.animeService.GetAnimeByID(animeID)
 	if err != nil {
 		b.logger.Error("Failed to get anime details: %v", err)
-		b.api.Send(tgbotapi.NewMessage(chatID, "Ошибка загрузки данных аниме"))
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("anime.load_failed")))
 		return
 	}
 
 	isFav := true
 	userRating, _ := b.animeService.GetUserRating(userID, animeID)
+	note, _ := b.animeService.GetUserNote(userID, animeID)
+	community, _ := b.animeService.GetCommunityRating(animeID)
+	alsoLiked, _ := b.animeService.GetAlsoLiked(animeID)
+	friends, _ := b.animeService.GetFriendRatings(userID, animeID)
 
-	text := utils.FormatAnimeMessageWithRating(anime, isFav, userRating)
-	keyboard := b.createFavoriteAnimeKeyboard(animeID, userRating)
-
-	if anime.Image.Original != "" || anime.Image.Preview != "" {
-		baseURL := "https://shikimori.one"
-		imagePath := anime.Image.Original
-		if imagePath == "" {
-			imagePath = anime.Image.Preview
-		}
+	text := utils.FormatAnimeMessageWithRating(tr, b.settings(userID), anime, isFav, userRating, note, community, alsoLiked, friends)
+	keyboard := b.createFavoriteAnimeKeyboard(tr, animeID, userRating)
 
-		fullURL := baseURL + imagePath
-
-		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(fullURL))
-		photo.Caption = text
-		photo.ParseMode = "Markdown"
-		photo.ReplyMarkup = keyboard
-		if _, err := b.api.Send(photo); err != nil {
-			b.logger.Error("Failed to send photo for anime ID %d: %v", anime.ID, err)
-		}
-	} else {
-		msg := tgbotapi.NewMessage(chatID, text)
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		if _, err := b.api.Send(msg); err != nil {
-			b.logger.Error("Failed to send message for anime ID %d: %v", anime.ID, err)
-		}
-	}
+	b.sendAnimeCard(chatID, anime, text, keyboard)
 }
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/library"
+)
+
+const maxImportFileSize = 5 * 1024 * 1024
+
+var importHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func (b *Bot) handleImportHelp(chatID int64) {
+	b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("import.help")))
+}
+
+func (b *Bot) handleImportDocument(userID int64, chatID int64, document *tgbotapi.Document) {
+	tr := b.tr(userID)
+	if document.FileSize > maxImportFileSize {
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("import.too_large")))
+		return
+	}
+
+	data, err := b.downloadFile(document.FileID)
+	if err != nil {
+		b.logger.Error("Failed to download import file for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("import.download_failed")))
+		return
+	}
+
+	result, err := b.animeService.ImportLibrary(userID, data)
+	if errors.Is(err, library.ErrUnknownFormat) {
+		b.handleImportHelp(chatID)
+		return
+	}
+	if errors.Is(err, library.ErrTooLarge) {
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("import.too_large_unpacked")))
+		return
+	}
+	if err != nil {
+		b.logger.Error("Failed to import library for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("import.failed")))
+		return
+	}
+
+	b.logger.Info("User %d imported library: %d imported, %d skipped, %d conflicts",
+		userID, result.Imported, result.Skipped, result.Conflicts)
+
+	text := tr.T("import.done") + "\n\n" +
+		tr.N("import.imported", result.Imported, result.Imported) + "\n" +
+		tr.N("import.skipped", result.Skipped, result.Skipped) + "\n" +
+		tr.N("import.conflicts", result.Conflicts, result.Conflicts)
+	if result.Conflicts > 0 {
+		text += "\n\n" + tr.T("import.conflicts_note")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
+	b.api.Send(msg)
+}
+
+func (b *Bot) downloadFile(fileID string) ([]byte, error) {
+	url, err := b.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file url: %w", err)
+	}
+
+	resp, err := importHTTPClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxImportFileSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", maxImportFileSize)
+	}
+	return data, nil
+}
@@ -4,11 +4,16 @@ import (
 	"fmt"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/recommend"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
 )
 
 // inline keyboards
-func (b *Bot) createAnimeKeyboard(userID int64, animeID int, isFavorite bool, userRating *models.Rating) tgbotapi.InlineKeyboardMarkup {
+func (b *Bot) createAnimeKeyboard(tr i18n.Localizer, userID int64, animeID int, isFavorite bool, userRating *models.Rating) tgbotapi.InlineKeyboardMarkup {
 	state := b.getState(userID)
 	if state == nil {
 		return tgbotapi.NewInlineKeyboardMarkup()
@@ -31,23 +36,31 @@ func (b *Bot) createAnimeKeyboard(userID int64, animeID int, isFavorite bool, us
 	actionRow := []tgbotapi.InlineKeyboardButton{}
 
 	if isFavorite {
-		actionRow = append(actionRow, tgbotapi.NewInlineKeyboardButtonData("💔 Удалить", fmt.Sprintf("unfav:%d", animeID)))
+		actionRow = append(actionRow, tgbotapi.NewInlineKeyboardButtonData(tr.T("button.remove_favorite"), fmt.Sprintf("unfav:%d", animeID)))
 	} else {
-		actionRow = append(actionRow, tgbotapi.NewInlineKeyboardButtonData("❤️ Добавить", fmt.Sprintf("fav:%d", animeID)))
+		actionRow = append(actionRow, tgbotapi.NewInlineKeyboardButtonData(tr.T("button.add_favorite"), fmt.Sprintf("fav:%d", animeID)))
 	}
 
-	ratingText := "⭐ Оценить"
+	ratingText := tr.T("button.rate")
 	if userRating != nil {
-		ratingText = fmt.Sprintf("⭐ Оценка: %d", userRating.Score)
+		ratingText = tr.T("button.rated", userRating.Score)
 	}
 	actionRow = append(actionRow, tgbotapi.NewInlineKeyboardButtonData(ratingText, fmt.Sprintf("rate:%d", animeID)))
 
 	buttons = append(buttons, actionRow)
 
+	noteRow := []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.note"), fmt.Sprintf("note:%d", animeID)),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.to_collection"), fmt.Sprintf("addcol:%d", animeID)),
+	}
+	buttons = append(buttons, noteRow)
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(shareAnimeButton(tr, animeID)))
+
 	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
 }
 
-func (b *Bot) createRatingKeyboard(animeID int) tgbotapi.InlineKeyboardMarkup {
+func (b *Bot) createRatingKeyboard(tr i18n.Localizer, animeID int) tgbotapi.InlineKeyboardMarkup {
 	var buttons [][]tgbotapi.InlineKeyboardButton
 
 	row1 := []tgbotapi.InlineKeyboardButton{
@@ -67,7 +80,7 @@ func (b *Bot) createRatingKeyboard(animeID int) tgbotapi.InlineKeyboardMarkup {
 	}
 
 	cancelRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "cancel_rating"),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), "cancel_rating"),
 	}
 
 	buttons = append(buttons, row1, row2, cancelRow)
@@ -75,24 +88,28 @@ func (b *Bot) createRatingKeyboard(animeID int) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
 }
 
-func (b *Bot) createFavoritesKeyboard(favorites []models.Favorite, currentPage, totalPages int) tgbotapi.InlineKeyboardMarkup {
-	var buttons [][]tgbotapi.InlineKeyboardButton
+type listEntry struct {
+	Title string
+	Data  string
+}
 
-	start := currentPage * 10
-	end := start + 10
-	if end > len(favorites) {
-		end = len(favorites)
-	}
+type listNavigation struct {
+	Prev string
+	Page string
+	Next string
+}
 
-	for i := start; i < end; i++ {
-		fav := favorites[i]
-		title := fav.Title
+// createListPageKeyboard renders entries that are already limited to the current page
+func (b *Bot) createListPageKeyboard(tr i18n.Localizer, entries []listEntry, currentPage, totalPages int, nav listNavigation) [][]tgbotapi.InlineKeyboardButton {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+
+	for _, entry := range entries {
+		title := entry.Title
 		if len(title) > 60 {
 			title = title[:57] + "..."
 		}
-		buttonData := fmt.Sprintf("show_fav:%d", fav.AnimeID)
 
-		button := tgbotapi.NewInlineKeyboardButtonData(title, buttonData)
+		button := tgbotapi.NewInlineKeyboardButtonData(title, entry.Data)
 		buttons = append(buttons, []tgbotapi.InlineKeyboardButton{button})
 	}
 
@@ -100,64 +117,484 @@ func (b *Bot) createFavoritesKeyboard(favorites []models.Favorite, currentPage,
 		navRow := []tgbotapi.InlineKeyboardButton{}
 
 		if currentPage > 0 {
-			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️", "fav_prev"))
+			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️", nav.Prev))
 		}
 
-		pageText := fmt.Sprintf("Стр. %d/%d", currentPage+1, totalPages)
-		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(pageText, "fav_page"))
+		pageText := tr.T("button.page", currentPage+1, totalPages)
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(pageText, nav.Page))
 
 		if currentPage < totalPages-1 {
-			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("➡️", "fav_next"))
+			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("➡️", nav.Next))
 		}
 
 		buttons = append(buttons, navRow)
 	}
 
+	return buttons
+}
+
+// createFavoritesKeyboard renders a single page already fetched with a keyset query
+func (b *Bot) createFavoritesKeyboard(tr i18n.Localizer, favorites []models.FavoriteListItem, sort database.FavoriteSort, searching bool, currentPage, totalPages int) tgbotapi.InlineKeyboardMarkup {
+	entries := make([]listEntry, 0, len(favorites))
+	for _, fav := range favorites {
+		title := fav.Title
+		switch {
+		case sort == database.FavoriteSortRating && fav.MyScore > 0:
+			title = fmt.Sprintf("⭐ %d · %s", fav.MyScore, title)
+		case sort == database.FavoriteSortScore && fav.AnimeScore > 0:
+			title = fmt.Sprintf("📊 %.2f · %s", fav.AnimeScore, title)
+		}
+		entries = append(entries, listEntry{Title: title, Data: fmt.Sprintf("show_fav:%d", fav.AnimeID)})
+	}
+
+	buttons := b.createListPageKeyboard(tr, entries, currentPage, totalPages, listNavigation{
+		Prev: "fav_prev",
+		Page: "fav_page",
+		Next: "fav_next",
+	})
+
+	sortLabel := tr.T(favoriteSortKey(sort))
+	searchButton := tgbotapi.NewInlineKeyboardButtonData(tr.T("button.search"), "fav_search")
+	if searching {
+		searchButton = tgbotapi.NewInlineKeyboardButtonData(tr.T("button.search_clear"), "fav_search_clear")
+	}
+	if len(favorites) > 0 && !searching {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.share_list"), "share_favs"),
+		))
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("↕️ "+sortLabel, "fav_sort"),
+		searchButton,
+	))
+
 	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
 }
 
-func (b *Bot) createFavoriteAnimeKeyboard(animeID int, userRating *models.Rating) tgbotapi.InlineKeyboardMarkup {
+func (b *Bot) createFavoriteAnimeKeyboard(tr i18n.Localizer, animeID int, userRating *models.Rating) tgbotapi.InlineKeyboardMarkup {
 	var buttons [][]tgbotapi.InlineKeyboardButton
 
 	deleteRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить из избранного", fmt.Sprintf("del_fav:%d", animeID)),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.delete_favorite"), fmt.Sprintf("del_fav:%d", animeID)),
 	}
 	buttons = append(buttons, deleteRow)
 
-	ratingText := "⭐ Оценить"
+	ratingText := tr.T("button.rate")
 	if userRating != nil {
-		ratingText = fmt.Sprintf("⭐ Оценка: %d", userRating.Score)
+		ratingText = tr.T("button.rated", userRating.Score)
 	}
 	ratingRow := []tgbotapi.InlineKeyboardButton{
 		tgbotapi.NewInlineKeyboardButtonData(ratingText, fmt.Sprintf("rate:%d", animeID)),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.note"), fmt.Sprintf("note:%d", animeID)),
 	}
 	buttons = append(buttons, ratingRow)
 
+	collectionRow := []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.to_collection"), fmt.Sprintf("addcol:%d", animeID)),
+		shareAnimeButton(tr, animeID),
+	}
+	buttons = append(buttons, collectionRow)
+
 	backRow := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к списку", "back_to_favs"),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.back_to_list"), "back_to_favs"),
 	}
 	buttons = append(buttons, backRow)
 
 	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
 }
 
+func (b *Bot) createCollectionsKeyboard(tr i18n.Localizer, collections []models.Collection) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+
+	for _, collection := range collections {
+		text := fmt.Sprintf("📁 %s (%d)", collection.Name, collection.ItemCount)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, fmt.Sprintf("col:%d", collection.ID)),
+		))
+	}
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.new_collection"), "col_new"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+// createCollectionKeyboard renders a single page already fetched with a keyset query
+func (b *Bot) createCollectionKeyboard(tr i18n.Localizer, collectionID int, items []models.CollectionItem, currentPage, totalPages int) tgbotapi.InlineKeyboardMarkup {
+	entries := make([]listEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, listEntry{Title: item.Title, Data: fmt.Sprintf("col_item:%d", item.AnimeID)})
+	}
+
+	buttons := b.createListPageKeyboard(tr, entries, currentPage, totalPages, listNavigation{
+		Prev: "col_prev",
+		Page: "col_page",
+		Next: "col_next",
+	})
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.rename"), fmt.Sprintf("col_ren:%d", collectionID)),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.delete"), fmt.Sprintf("col_del:%d", collectionID)),
+	))
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.share_collection"), fmt.Sprintf("share_col:%d", collectionID)),
+	))
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.to_collections"), "collections"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createCollectionPickerKeyboard(tr i18n.Localizer, animeID int, collections []models.Collection, memberOf []int) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+
+	member := make(map[int]bool, len(memberOf))
+	for _, id := range memberOf {
+		member[id] = true
+	}
+
+	for _, collection := range collections {
+		text := "📁 " + collection.Name
+		if member[collection.ID] {
+			text = "✅ " + collection.Name
+		}
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, fmt.Sprintf("colput:%d:%d", collection.ID, animeID)),
+		))
+	}
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.new_collection"), fmt.Sprintf("col_new:%d", animeID)),
+	))
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.close"), "col_close"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createDeleteCollectionKeyboard(tr i18n.Localizer, collectionID int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.delete_confirm"), fmt.Sprintf("col_delok:%d", collectionID)),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), fmt.Sprintf("col:%d", collectionID)),
+		),
+	)
+}
+
+func (b *Bot) createCollectionAnimeKeyboard(tr i18n.Localizer, collectionID int, animeID int, userRating *models.Rating) tgbotapi.InlineKeyboardMarkup {
+	ratingText := tr.T("button.rate")
+	if userRating != nil {
+		ratingText = tr.T("button.rated", userRating.Score)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.remove_from_col"), fmt.Sprintf("col_rm:%d:%d", collectionID, animeID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(ratingText, fmt.Sprintf("rate:%d", animeID)),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.note"), fmt.Sprintf("note:%d", animeID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(shareAnimeButton(tr, animeID)),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.back_to_collection"), fmt.Sprintf("col_back:%d", collectionID)),
+		),
+	)
+}
+
+func (b *Bot) createRatingsOverviewKeyboard(tr i18n.Localizer, distribution map[int]int) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+
+	for score := 10; score >= 1; score-- {
+		count := distribution[score]
+		if count == 0 {
+			continue
+		}
+		text := fmt.Sprintf("⭐ %d — %d", score, count)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, fmt.Sprintf("rscore:%d", score)),
+		))
+	}
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.ratings_by_date"), "rlist:date"),
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.ratings_by_score"), "rlist:score"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createRatingsListKeyboard(tr i18n.Localizer, ratings []models.UserRating, showScore bool, currentPage, totalPages int) tgbotapi.InlineKeyboardMarkup {
+	entries := make([]listEntry, 0, len(ratings))
+	for _, rating := range ratings {
+		title := rating.Title
+		if title == "" {
+			title = tr.T("anime.number", rating.AnimeID)
+		}
+		if showScore {
+			title = fmt.Sprintf("⭐ %d · %s", rating.Score, title)
+		}
+		entries = append(entries, listEntry{Title: title, Data: fmt.Sprintf("show_rated:%d", rating.AnimeID)})
+	}
+
+	buttons := b.createListPageKeyboard(tr, entries, currentPage, totalPages, listNavigation{
+		Prev: "rt_prev",
+		Page: "rt_page",
+		Next: "rt_next",
+	})
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.to_ratings"), "ratings"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createRatedAnimeKeyboard(tr i18n.Localizer, animeID int, userRating *models.Rating) tgbotapi.InlineKeyboardMarkup {
+	ratingText := tr.T("button.rate")
+	if userRating != nil {
+		ratingText = tr.T("button.rated", userRating.Score)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(ratingText, fmt.Sprintf("rate:%d", animeID)),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.note"), fmt.Sprintf("note:%d", animeID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(shareAnimeButton(tr, animeID)),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.back_to_list"), "rt_back"),
+		),
+	)
+}
+
 // reply keyboards
-func (b *Bot) createMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
+func (b *Bot) createExportKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("JSON", "export:json"),
+			tgbotapi.NewInlineKeyboardButtonData("CSV", "export:csv"),
+			tgbotapi.NewInlineKeyboardButtonData("MAL XML", "export:xml"),
+		),
+	)
+}
+
+func (b *Bot) createStatsKeyboard(tr i18n.Localizer) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.stats_all"), "stats:all"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.stats_year"), "stats:year"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.stats_month"), "stats:month"),
+		),
+	)
+}
+
+func (b *Bot) createWrappedKeyboard(tr i18n.Localizer, year int, index, total int) tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	if index > 0 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("⬅️", fmt.Sprintf("wrapped:%d:%d", year, index-1)))
+	}
+	if index < total-1 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(tr.T("button.wrapped_next"), fmt.Sprintf("wrapped:%d:%d", year, index+1)))
+	} else {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(tr.T("button.wrapped_image"), fmt.Sprintf("wrapped_img:%d", year)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+func (b *Bot) createRecommendationsKeyboard(tr i18n.Localizer, picks []recommend.Pick) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	for i, pick := range picks {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.open_numbered", i+1), fmt.Sprintf("rec_show:%d", pick.Anime.ID)),
+			tgbotapi.NewInlineKeyboardButtonData("👍", fmt.Sprintf("rec_like:%d", pick.Anime.ID)),
+			tgbotapi.NewInlineKeyboardButtonData("👎", fmt.Sprintf("rec_dislike:%d", pick.Anime.ID)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createFriendsKeyboard(tr i18n.Localizer, following []models.Friend, share bool) tgbotapi.InlineKeyboardMarkup {
+	privacy := tgbotapi.NewInlineKeyboardButtonData(tr.T("button.hide_list"), "friends_share:off")
+	if !share {
+		privacy = tgbotapi.NewInlineKeyboardButtonData(tr.T("button.show_list"), "friends_share:on")
+	}
+
+	buttons := [][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(privacy)}
+	for _, friend := range following {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.unfollow", utils.FriendName(friend.Username, friend.UserID)), fmt.Sprintf("friends_unfollow:%d", friend.UserID)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+// createGroupAnimeKeyboard is the shared card of a group chat: navigation is
+// per chat and there are no personal buttons
+func (b *Bot) createGroupAnimeKeyboard(tr i18n.Localizer, state *UserState, animeID int, inWatchlist bool) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+
+	if state != nil && len(state.SearchResults) > 1 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬅️", "grp_prev"),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", state.CurrentIndex+1, len(state.SearchResults)), "grp_position"),
+			tgbotapi.NewInlineKeyboardButtonData("➡️", "grp_next"),
+		))
+	}
+
+	action := tgbotapi.NewInlineKeyboardButtonData(tr.T("button.add_to_chat"), fmt.Sprintf("grp_add:%d", animeID))
+	if inWatchlist {
+		action = tgbotapi.NewInlineKeyboardButtonData(tr.T("button.chat_list"), "grp_list")
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(action))
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createChatWatchlistKeyboard(items []models.ChatWatchlistItem) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	for i, item := range items {
+		if i == chatWatchlistShown {
+			break
+		}
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("❌ %d. %s", i+1, utils.TruncateRunes(item.Title, 40)), fmt.Sprintf("grp_remove:%d", item.AnimeID)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createVoteKeyboard(tr i18n.Localizer) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.close_vote"), "vote_close"),
+		),
+	)
+}
+
+func shareAnimeButton(tr i18n.Localizer, animeID int) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(tr.T("button.share"), fmt.Sprintf("share:%d", animeID))
+}
+
+func (b *Bot) createShareKeyboard(tr i18n.Localizer, link string, text string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(tr.T("button.send_to_friend"), shareURL(link, text)),
+		),
+	)
+}
+
+func (b *Bot) createSharedListKeyboard(entries []listEntry) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	for i, entry := range entries {
+		if i == sharedListShown {
+			break
+		}
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", i+1, utils.TruncateRunes(entry.Title, 40)), entry.Data),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createDeleteAccountKeyboard(tr i18n.Localizer) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.delete_everything"), "deleteme_ok"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel_plain"), "deleteme_cancel"),
+		),
+	)
+}
+
+func (b *Bot) createLanguageKeyboard(tr i18n.Localizer, override i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	for _, lang := range i18n.Supported {
+		text := languageName(tr, lang)
+		if lang == override {
+			text = "✅ " + text
+		}
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, "lang:"+string(lang)),
+		))
+	}
+
+	auto := tr.T("language.auto")
+	if override == "" {
+		auto = "✅ " + auto
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(auto, "lang:auto"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createSettingsKeyboard(tr i18n.Localizer, settings models.UserSettings) tgbotapi.InlineKeyboardMarkup {
+	onOff := func(on bool) string {
+		if on {
+			return tr.T("settings.on")
+		}
+		return tr.T("settings.off")
+	}
+
+	button := func(text string, data string) []tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text, data))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		button(tr.T("settings.language", languageName(tr, tr.Lang())), "set:lang"),
+		button(tr.T("settings.titles", tr.T("settings.titles."+settings.TitleDisplay)), "set:titles"),
+		button(tr.T("settings.time_zone", zoneName(tr, settings.TimeZone)), "set:tz"),
+		button(tr.T("settings.nsfw", onOff(settings.HideNSFW)), "set:nsfw"),
+		button(tr.T("settings.spoilers", onOff(settings.HideSpoilers)), "set:spoilers"),
+		button(tr.T("settings.page_size", settings.PageSize), "set:page"),
+		button(tr.T("settings.notify_follows", onOff(settings.NotifyFollows)), "set:follows"),
+		button(tr.T("settings.notify_wrapped", onOff(settings.NotifyWrapped)), "set:wrapped"),
+	)
+}
+
+func (b *Bot) createTimeZoneKeyboard(tr i18n.Localizer, current string) tgbotapi.InlineKeyboardMarkup {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, zone := range service.TimeZones {
+		text := zoneName(tr, zone)
+		if zone == current {
+			text = "✅ " + text
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(text, "set:tz:"+zone))
+		if len(row) == 2 {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("button.back_to_settings"), "set:menu"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
+}
+
+func (b *Bot) createMainMenuKeyboard(tr i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Поиск"),
-			tgbotapi.NewKeyboardButton("Избранное"),
+			tgbotapi.NewKeyboardButton(tr.T("menu.search")),
+			tgbotapi.NewKeyboardButton(tr.T("menu.favorites")),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Помощь"),
+			tgbotapi.NewKeyboardButton(tr.T("menu.help")),
 		),
 	)
 }
 
-func (b *Bot) createCancelKeyboard() tgbotapi.ReplyKeyboardMarkup {
+func (b *Bot) createCancelKeyboard(tr i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("Отмена"),
+			tgbotapi.NewKeyboardButton(tr.T("menu.cancel")),
 		),
 	)
 }
@@ -1,15 +1,22 @@
 package telegram
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/recommend"
 )
 
+var ru = i18n.For(i18n.Russian)
+
 func TestCreateAnimeKeyboard_NoState(t *testing.T) {
 	b := &Bot{userStates: make(map[int64]*UserState)}
-	kb := b.createAnimeKeyboard(1, 10, false, nil)
+	kb := b.createAnimeKeyboard(ru, 1, 10, false, nil)
 	if len(kb.InlineKeyboard) != 0 {
 		t.Fatalf("expected empty keyboard on no state, got: %v", kb)
 	}
@@ -18,7 +25,7 @@ func TestCreateAnimeKeyboard_NoState(t *testing.T) {
 func TestCreateAnimeKeyboard_WithState(t *testing.T) {
 	b := &Bot{userStates: make(map[int64]*UserState)}
 	b.userStates[1] = &UserState{SearchResults: []models.Anime{{ID: 1}, {ID: 2}}, CurrentIndex: 0}
-	kb := b.createAnimeKeyboard(1, 1, true, nil)
+	kb := b.createAnimeKeyboard(ru, 1, 1, true, nil)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Fatalf("expected keyboard rows, got none")
 	}
@@ -26,11 +33,11 @@ func TestCreateAnimeKeyboard_WithState(t *testing.T) {
 
 func TestCreateFavoritesKeyboard_Pagination(t *testing.T) {
 	b := &Bot{}
-	favs := []models.Favorite{}
-	for i := 0; i < 25; i++ {
-		favs = append(favs, models.Favorite{AnimeID: i, Title: "title"})
+	favs := []models.FavoriteListItem{}
+	for i := 0; i < favoritesPerPage; i++ {
+		favs = append(favs, models.FavoriteListItem{AnimeID: i, Title: "title"})
 	}
-	kb := b.createFavoritesKeyboard(favs, 1, 3)
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 1, 3)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Fatalf("expected favorites keyboard rows, got none")
 	}
@@ -38,11 +45,11 @@ func TestCreateFavoritesKeyboard_Pagination(t *testing.T) {
 
 func TestCreateMainAndCancelKeyboards(t *testing.T) {
 	b := &Bot{}
-	m := b.createMainMenuKeyboard()
+	m := b.createMainMenuKeyboard(ru)
 	if len(m.Keyboard) == 0 {
 		t.Fatalf("main menu keyboard empty")
 	}
-	c := b.createCancelKeyboard()
+	c := b.createCancelKeyboard(ru)
 	if len(c.Keyboard) == 0 {
 		t.Fatalf("cancel keyboard empty")
 	}
@@ -56,7 +63,7 @@ func TestCreateAnimeKeyboard_WithRating(t *testing.T) {
 		CurrentIndex:  0,
 	}
 	rating := &models.Rating{Score: 8}
-	kb := b.createAnimeKeyboard(1, 1, true, rating)
+	kb := b.createAnimeKeyboard(ru, 1, 1, true, rating)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Fatalf("expected keyboard rows, got none")
 	}
@@ -69,7 +76,7 @@ func TestCreateAnimeKeyboard_MultipleResults(t *testing.T) {
 		SearchResults: animes,
 		CurrentIndex:  2,
 	}
-	kb := b.createAnimeKeyboard(1, 1, false, nil)
+	kb := b.createAnimeKeyboard(ru, 1, 1, false, nil)
 	if len(kb.InlineKeyboard) < 2 {
 		t.Error("expected navigation and action rows")
 	}
@@ -82,7 +89,7 @@ func TestCreateAnimeKeyboard_FirstPosition(t *testing.T) {
 		SearchResults: animes,
 		CurrentIndex:  0,
 	}
-	kb := b.createAnimeKeyboard(1, 1, false, nil)
+	kb := b.createAnimeKeyboard(ru, 1, 1, false, nil)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Error("expected keyboard rows")
 	}
@@ -95,7 +102,7 @@ func TestCreateAnimeKeyboard_LastPosition(t *testing.T) {
 		SearchResults: animes,
 		CurrentIndex:  1,
 	}
-	kb := b.createAnimeKeyboard(1, 2, false, nil)
+	kb := b.createAnimeKeyboard(ru, 1, 2, false, nil)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Error("expected keyboard rows")
 	}
@@ -103,7 +110,7 @@ func TestCreateAnimeKeyboard_LastPosition(t *testing.T) {
 
 func TestCreateRatingKeyboard(t *testing.T) {
 	b := &Bot{}
-	kb := b.createRatingKeyboard(1)
+	kb := b.createRatingKeyboard(ru, 1)
 	if len(kb.InlineKeyboard) != 3 {
 		t.Errorf("expected 3 rows (1-5, 6-10, cancel), got %d", len(kb.InlineKeyboard))
 	}
@@ -120,11 +127,11 @@ func TestCreateRatingKeyboard(t *testing.T) {
 
 func TestCreateFavoritesKeyboard_FirstPage(t *testing.T) {
 	b := &Bot{}
-	favs := []models.Favorite{
-		{ID: 1, Title: "Anime 1"},
-		{ID: 2, Title: "Anime 2"},
+	favs := []models.FavoriteListItem{
+		{AnimeID: 1, Title: "Anime 1"},
+		{AnimeID: 2, Title: "Anime 2"},
 	}
-	kb := b.createFavoritesKeyboard(favs, 0, 1)
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 0, 1)
 	if len(kb.InlineKeyboard) < 2 {
 		t.Error("expected at least 2 rows (items + navigation)")
 	}
@@ -132,11 +139,11 @@ func TestCreateFavoritesKeyboard_FirstPage(t *testing.T) {
 
 func TestCreateFavoritesKeyboard_MiddlePage(t *testing.T) {
 	b := &Bot{}
-	var favs []models.Favorite
-	for i := 0; i < 35; i++ {
-		favs = append(favs, models.Favorite{ID: i + 1, Title: "Anime " + string(rune(i))})
+	var favs []models.FavoriteListItem
+	for i := 0; i < favoritesPerPage; i++ {
+		favs = append(favs, models.FavoriteListItem{AnimeID: i + 1, Title: "Anime " + string(rune(i))})
 	}
-	kb := b.createFavoritesKeyboard(favs, 1, 4)
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 1, 4)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Error("expected keyboard rows")
 	}
@@ -144,11 +151,11 @@ func TestCreateFavoritesKeyboard_MiddlePage(t *testing.T) {
 
 func TestCreateFavoritesKeyboard_LastPage(t *testing.T) {
 	b := &Bot{}
-	var favs []models.Favorite
-	for i := 0; i < 15; i++ {
-		favs = append(favs, models.Favorite{ID: i + 1, Title: "Anime " + string(rune(i))})
+	var favs []models.FavoriteListItem
+	for i := 0; i < 5; i++ {
+		favs = append(favs, models.FavoriteListItem{AnimeID: i + 1, Title: "Anime " + string(rune(i))})
 	}
-	kb := b.createFavoritesKeyboard(favs, 1, 2)
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 1, 2)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Error("expected keyboard rows")
 	}
@@ -157,10 +164,10 @@ func TestCreateFavoritesKeyboard_LastPage(t *testing.T) {
 func TestCreateFavoritesKeyboard_LongTitles(t *testing.T) {
 	b := &Bot{}
 	longTitle := "This is a very long anime title that should be truncated to fit in the button"
-	favs := []models.Favorite{
-		{ID: 1, Title: longTitle},
+	favs := []models.FavoriteListItem{
+		{AnimeID: 1, Title: longTitle},
 	}
-	kb := b.createFavoritesKeyboard(favs, 0, 1)
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 0, 1)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Error("expected keyboard rows")
 	}
@@ -174,8 +181,8 @@ func TestCreateFavoritesKeyboard_LongTitles(t *testing.T) {
 
 func TestCreateFavoritesKeyboard_EmptyList(t *testing.T) {
 	b := &Bot{}
-	favs := []models.Favorite{}
-	kb := b.createFavoritesKeyboard(favs, 0, 0)
+	favs := []models.FavoriteListItem{}
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 0, 0)
 	if len(kb.InlineKeyboard) > 1 {
 		t.Errorf("expected at most 1 row for empty list, got %d", len(kb.InlineKeyboard))
 	}
@@ -183,8 +190,8 @@ func TestCreateFavoritesKeyboard_EmptyList(t *testing.T) {
 
 func TestCreateFavoritesKeyboard_SingleItem(t *testing.T) {
 	b := &Bot{}
-	favs := []models.Favorite{{ID: 1, Title: "Single Anime"}}
-	kb := b.createFavoritesKeyboard(favs, 0, 1)
+	favs := []models.FavoriteListItem{{AnimeID: 1, Title: "Single Anime"}}
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 0, 1)
 	if len(kb.InlineKeyboard) == 0 {
 		t.Error("expected keyboard rows")
 	}
@@ -192,7 +199,7 @@ func TestCreateFavoritesKeyboard_SingleItem(t *testing.T) {
 
 func TestCreateFavoriteAnimeKeyboard(t *testing.T) {
 	b := &Bot{}
-	kb := b.createFavoriteAnimeKeyboard(1, nil)
+	kb := b.createFavoriteAnimeKeyboard(ru, 1, nil)
 	if len(kb.InlineKeyboard) < 3 {
 		t.Errorf("expected at least 3 rows, got %d", len(kb.InlineKeyboard))
 	}
@@ -201,7 +208,7 @@ func TestCreateFavoriteAnimeKeyboard(t *testing.T) {
 func TestCreateFavoriteAnimeKeyboard_WithRatingBasic(t *testing.T) {
 	b := &Bot{}
 	rating := &models.Rating{Score: 7}
-	kb := b.createFavoriteAnimeKeyboard(1, rating)
+	kb := b.createFavoriteAnimeKeyboard(ru, 1, rating)
 	if len(kb.InlineKeyboard) < 3 {
 		t.Error("expected at least 3 rows")
 	}
@@ -209,7 +216,7 @@ func TestCreateFavoriteAnimeKeyboard_WithRatingBasic(t *testing.T) {
 
 func TestCreateCancelKeyboard(t *testing.T) {
 	b := &Bot{}
-	kb := b.createCancelKeyboard()
+	kb := b.createCancelKeyboard(ru)
 	if len(kb.Keyboard) == 0 {
 		t.Error("expected reply keyboard rows")
 	}
@@ -220,7 +227,7 @@ func TestCreateCancelKeyboard(t *testing.T) {
 
 func TestCreateMainMenuKeyboard_Structure(t *testing.T) {
 	b := &Bot{}
-	kb := b.createMainMenuKeyboard()
+	kb := b.createMainMenuKeyboard(ru)
 	if len(kb.Keyboard) < 1 {
 		t.Error("expected at least 1 row in main menu")
 	}
@@ -231,3 +238,335 @@ func TestCreateMainMenuKeyboard_Structure(t *testing.T) {
 		t.Errorf("expected 1 button in second row, got %d", len(kb.Keyboard[1]))
 	}
 }
+
+func hasCallback(kb tgbotapi.InlineKeyboardMarkup, data string) bool {
+	for _, row := range kb.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil && *button.CallbackData == data {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestCreateAnimeKeyboard_NoteButton(t *testing.T) {
+	b := &Bot{userStates: make(map[int64]*UserState)}
+	b.userStates[1] = &UserState{SearchResults: []models.Anime{{ID: 5}}}
+	kb := b.createAnimeKeyboard(ru, 1, 5, false, nil)
+	if !hasCallback(kb, "note:5") {
+		t.Error("expected note button on anime card")
+	}
+}
+
+func TestCreateFavoriteAnimeKeyboard_NoteButton(t *testing.T) {
+	b := &Bot{}
+	kb := b.createFavoriteAnimeKeyboard(ru, 5, nil)
+	if !hasCallback(kb, "note:5") {
+		t.Error("expected note button on favorite card")
+	}
+}
+
+func TestCreateCollectionsKeyboard(t *testing.T) {
+	b := &Bot{}
+	collections := []models.Collection{{ID: 1, Name: "friends", ItemCount: 2}, {ID: 2, Name: "rewatch"}}
+	kb := b.createCollectionsKeyboard(ru, collections)
+	if len(kb.InlineKeyboard) != 3 {
+		t.Errorf("expected 2 collections and a create row, got %d rows", len(kb.InlineKeyboard))
+	}
+	if !hasCallback(kb, "col:2") || !hasCallback(kb, "col_new") {
+		t.Error("expected collection and create buttons")
+	}
+}
+
+func TestCreateCollectionKeyboard_Pagination(t *testing.T) {
+	b := &Bot{}
+	var items []models.CollectionItem
+	for i := 10; i < 20; i++ {
+		items = append(items, models.CollectionItem{CollectionID: 3, AnimeID: i + 1, Title: "title"})
+	}
+	kb := b.createCollectionKeyboard(ru, 3, items, 1, 3)
+	if !hasCallback(kb, "col_item:11") || !hasCallback(kb, "col_item:20") {
+		t.Error("expected every item of the page")
+	}
+	if !hasCallback(kb, "col_prev") || !hasCallback(kb, "col_next") {
+		t.Error("expected navigation buttons on middle page")
+	}
+	if !hasCallback(kb, "col_ren:3") || !hasCallback(kb, "col_del:3") {
+		t.Error("expected rename and delete buttons")
+	}
+}
+
+func TestCreateCollectionPickerKeyboard_MarksMembership(t *testing.T) {
+	b := &Bot{}
+	collections := []models.Collection{{ID: 1, Name: "friends"}, {ID: 2, Name: "rewatch"}}
+	kb := b.createCollectionPickerKeyboard(ru, 9, collections, []int{2})
+	if kb.InlineKeyboard[0][0].Text != "📁 friends" {
+		t.Errorf("unexpected button text: %q", kb.InlineKeyboard[0][0].Text)
+	}
+	if kb.InlineKeyboard[1][0].Text != "✅ rewatch" {
+		t.Errorf("expected membership mark, got %q", kb.InlineKeyboard[1][0].Text)
+	}
+	if !hasCallback(kb, "colput:2:9") || !hasCallback(kb, "col_new:9") {
+		t.Error("expected toggle and create buttons")
+	}
+}
+
+func TestCreateAnimeKeyboard_CollectionButton(t *testing.T) {
+	b := &Bot{userStates: make(map[int64]*UserState)}
+	b.userStates[1] = &UserState{SearchResults: []models.Anime{{ID: 5}}}
+	kb := b.createAnimeKeyboard(ru, 1, 5, false, nil)
+	if !hasCallback(kb, "addcol:5") {
+		t.Error("expected add-to-collection button on anime card")
+	}
+}
+
+func TestCreateRatingsOverviewKeyboard_GroupsByScore(t *testing.T) {
+	b := &Bot{}
+	kb := b.createRatingsOverviewKeyboard(ru, map[int]int{10: 2, 7: 1})
+	if len(kb.InlineKeyboard) != 3 {
+		t.Fatalf("expected 2 score rows and a sort row, got %d", len(kb.InlineKeyboard))
+	}
+	if kb.InlineKeyboard[0][0].Text != "⭐ 10 — 2" {
+		t.Errorf("expected highest score first, got %q", kb.InlineKeyboard[0][0].Text)
+	}
+	if !hasCallback(kb, "rscore:7") || !hasCallback(kb, "rlist:score") {
+		t.Error("expected drill-down and sort buttons")
+	}
+}
+
+func TestCreateRatingsListKeyboard(t *testing.T) {
+	b := &Bot{}
+	ratings := []models.UserRating{{AnimeID: 1, Title: "Steins;Gate", Score: 10}, {AnimeID: 2, Score: 9}}
+	kb := b.createRatingsListKeyboard(ru, ratings, true, 0, 2)
+	if kb.InlineKeyboard[0][0].Text != "⭐ 10 · Steins;Gate" {
+		t.Errorf("unexpected entry text: %q", kb.InlineKeyboard[0][0].Text)
+	}
+	if kb.InlineKeyboard[1][0].Text != "⭐ 9 · Аниме #2" {
+		t.Errorf("expected fallback title, got %q", kb.InlineKeyboard[1][0].Text)
+	}
+	if !hasCallback(kb, "show_rated:1") || !hasCallback(kb, "rt_next") || !hasCallback(kb, "ratings") {
+		t.Error("expected card, navigation and back buttons")
+	}
+}
+
+func TestCreateFavoritesKeyboard_ControlsRow(t *testing.T) {
+	b := &Bot{}
+	favs := []models.FavoriteListItem{{AnimeID: 1, Title: "Anime"}}
+
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortTitle, false, 0, 1)
+	controls := kb.InlineKeyboard[len(kb.InlineKeyboard)-1]
+	if len(controls) != 2 || *controls[0].CallbackData != "fav_sort" || *controls[1].CallbackData != "fav_search" {
+		t.Fatalf("unexpected controls row: %+v", controls)
+	}
+
+	kb = b.createFavoritesKeyboard(ru, favs, database.FavoriteSortTitle, true, 0, 1)
+	controls = kb.InlineKeyboard[len(kb.InlineKeyboard)-1]
+	if *controls[1].CallbackData != "fav_search_clear" {
+		t.Errorf("expected clear search button, got %s", *controls[1].CallbackData)
+	}
+}
+
+func TestCreateFavoritesKeyboard_ScoreLabels(t *testing.T) {
+	b := &Bot{}
+	favs := []models.FavoriteListItem{{AnimeID: 1, Title: "Anime", MyScore: 9, AnimeScore: 8.5}}
+
+	kb := b.createFavoritesKeyboard(ru, favs, database.FavoriteSortRating, false, 0, 1)
+	if got := kb.InlineKeyboard[0][0].Text; got != "⭐ 9 · Anime" {
+		t.Errorf("unexpected rating label: %q", got)
+	}
+
+	kb = b.createFavoritesKeyboard(ru, favs, database.FavoriteSortScore, false, 0, 1)
+	if got := kb.InlineKeyboard[0][0].Text; got != "📊 8.50 · Anime" {
+		t.Errorf("unexpected score label: %q", got)
+	}
+
+	kb = b.createFavoritesKeyboard(ru, favs, database.FavoriteSortDate, false, 0, 1)
+	if got := kb.InlineKeyboard[0][0].Text; got != "Anime" {
+		t.Errorf("unexpected date label: %q", got)
+	}
+}
+
+func TestNextFavoriteSort(t *testing.T) {
+	sort := database.FavoriteSort("")
+	seen := []database.FavoriteSort{}
+	for i := 0; i < len(database.FavoriteSorts); i++ {
+		sort = nextFavoriteSort(sort)
+		seen = append(seen, sort)
+	}
+	want := []database.FavoriteSort{database.FavoriteSortTitle, database.FavoriteSortRating, database.FavoriteSortScore, database.FavoriteSortDate}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("sort cycle = %v, want %v", seen, want)
+		}
+	}
+}
+
+func TestCreateWrappedKeyboard(t *testing.T) {
+	b := &Bot{}
+
+	first := b.createWrappedKeyboard(ru, 2025, 0, 3)
+	row := first.InlineKeyboard[0]
+	if len(row) != 1 || *row[0].CallbackData != "wrapped:2025:1" {
+		t.Errorf("unexpected first card buttons: %+v", row)
+	}
+
+	last := b.createWrappedKeyboard(ru, 2025, 2, 3)
+	row = last.InlineKeyboard[0]
+	if len(row) != 2 || *row[0].CallbackData != "wrapped:2025:1" || *row[1].CallbackData != "wrapped_img:2025" {
+		t.Errorf("unexpected last card buttons: %+v", row)
+	}
+}
+
+func TestCreateRecommendationsKeyboard(t *testing.T) {
+	b := &Bot{}
+	picks := []recommend.Pick{{Anime: models.Anime{ID: 10}}, {Anime: models.Anime{ID: 20}}}
+
+	kb := b.createRecommendationsKeyboard(ru, picks)
+	if len(kb.InlineKeyboard) != 2 {
+		t.Fatalf("expected a row per pick, got %d", len(kb.InlineKeyboard))
+	}
+	row := kb.InlineKeyboard[1]
+	if len(row) != 3 || *row[0].CallbackData != "rec_show:20" || *row[1].CallbackData != "rec_like:20" || *row[2].CallbackData != "rec_dislike:20" {
+		t.Errorf("unexpected row: %+v", row)
+	}
+}
+
+func TestCreateFriendsKeyboard(t *testing.T) {
+	b := &Bot{}
+	following := []models.Friend{{UserID: 7, Username: "alice"}, {UserID: 8}}
+
+	kb := b.createFriendsKeyboard(ru, following, true)
+	if len(kb.InlineKeyboard) != 3 {
+		t.Fatalf("expected privacy row and a row per friend, got %d", len(kb.InlineKeyboard))
+	}
+	if *kb.InlineKeyboard[0][0].CallbackData != "friends_share:off" {
+		t.Errorf("expected hide button while sharing, got %+v", kb.InlineKeyboard[0])
+	}
+	if row := kb.InlineKeyboard[2]; *row[0].CallbackData != "friends_unfollow:8" || !strings.Contains(row[0].Text, "id8") {
+		t.Errorf("unexpected unfollow row: %+v", row)
+	}
+
+	kb = b.createFriendsKeyboard(ru, nil, false)
+	if len(kb.InlineKeyboard) != 1 || *kb.InlineKeyboard[0][0].CallbackData != "friends_share:on" {
+		t.Errorf("expected only the show button, got %+v", kb.InlineKeyboard)
+	}
+}
+
+func TestCreateGroupAnimeKeyboard(t *testing.T) {
+	b := &Bot{}
+	state := &UserState{SearchResults: []models.Anime{{ID: 1}, {ID: 2}}, CurrentIndex: 1}
+
+	kb := b.createGroupAnimeKeyboard(ru, state, 2, false)
+	if len(kb.InlineKeyboard) != 2 {
+		t.Fatalf("expected navigation and action rows, got %d", len(kb.InlineKeyboard))
+	}
+	if nav := kb.InlineKeyboard[0]; *nav[0].CallbackData != "grp_prev" || nav[1].Text != "2/2" || *nav[2].CallbackData != "grp_next" {
+		t.Errorf("unexpected navigation: %+v", nav)
+	}
+	if action := kb.InlineKeyboard[1][0]; *action.CallbackData != "grp_add:2" {
+		t.Errorf("expected add button, got %+v", action)
+	}
+
+	kb = b.createGroupAnimeKeyboard(ru, &UserState{SearchResults: []models.Anime{{ID: 2}}}, 2, true)
+	if len(kb.InlineKeyboard) != 1 || *kb.InlineKeyboard[0][0].CallbackData != "grp_list" {
+		t.Errorf("expected only the list button, got %+v", kb.InlineKeyboard)
+	}
+}
+
+func TestCreateChatWatchlistKeyboard(t *testing.T) {
+	b := &Bot{}
+	items := make([]models.ChatWatchlistItem, chatWatchlistShown+5)
+	for i := range items {
+		items[i] = models.ChatWatchlistItem{AnimeID: i + 1, Title: strings.Repeat("Длинное название ", 5)}
+	}
+
+	kb := b.createChatWatchlistKeyboard(items)
+	if len(kb.InlineKeyboard) != chatWatchlistShown {
+		t.Fatalf("expected %d rows, got %d", chatWatchlistShown, len(kb.InlineKeyboard))
+	}
+	button := kb.InlineKeyboard[0][0]
+	if *button.CallbackData != "grp_remove:1" || utf8.RuneCountInString(button.Text) > 50 {
+		t.Errorf("unexpected button: %+v", button)
+	}
+}
+
+func TestCreateVoteKeyboard(t *testing.T) {
+	b := &Bot{}
+	kb := b.createVoteKeyboard(ru)
+	if len(kb.InlineKeyboard) != 1 || *kb.InlineKeyboard[0][0].CallbackData != "vote_close" {
+		t.Errorf("unexpected keyboard: %+v", kb.InlineKeyboard)
+	}
+}
+
+func TestAnimeCardKeyboards_HaveShareButton(t *testing.T) {
+	b := &Bot{userStates: make(map[int64]*UserState)}
+	b.saveState(1, &UserState{SearchResults: []models.Anime{{ID: 5}}})
+
+	keyboards := map[string]tgbotapi.InlineKeyboardMarkup{
+		"search":     b.createAnimeKeyboard(ru, 1, 5, false, nil),
+		"favorite":   b.createFavoriteAnimeKeyboard(ru, 5, nil),
+		"collection": b.createCollectionAnimeKeyboard(ru, 2, 5, nil),
+		"rated":      b.createRatedAnimeKeyboard(ru, 5, nil),
+	}
+	for name, kb := range keyboards {
+		if !hasCallback(kb, "share:5") {
+			t.Errorf("%s card: expected a share button", name)
+		}
+	}
+}
+
+func TestCreateSharedListKeyboard(t *testing.T) {
+	b := &Bot{}
+	entries := make([]listEntry, sharedListShown+5)
+	for i := range entries {
+		entries[i] = listEntry{Title: strings.Repeat("Длинное название ", 5), Data: "share_open:1"}
+	}
+
+	kb := b.createSharedListKeyboard(entries)
+	if len(kb.InlineKeyboard) != sharedListShown {
+		t.Fatalf("expected %d rows, got %d", sharedListShown, len(kb.InlineKeyboard))
+	}
+	if utf8.RuneCountInString(kb.InlineKeyboard[0][0].Text) > 50 {
+		t.Errorf("button text is too long: %q", kb.InlineKeyboard[0][0].Text)
+	}
+
+	share := b.createShareKeyboard(ru, "https://t.me/bot?start=anime_1", "Monster")
+	if share.InlineKeyboard[0][0].URL == nil || !strings.HasPrefix(*share.InlineKeyboard[0][0].URL, "https://t.me/share/url?") {
+		t.Errorf("expected a share url button, got %+v", share.InlineKeyboard[0][0])
+	}
+}
+
+func TestCreateLanguageKeyboard(t *testing.T) {
+	b := &Bot{}
+	kb := b.createLanguageKeyboard(ru, "")
+	if len(kb.InlineKeyboard) != len(i18n.Supported)+1 {
+		t.Fatalf("expected a row per language plus auto, got %d", len(kb.InlineKeyboard))
+	}
+	if !hasCallback(kb, "lang:ru") || !hasCallback(kb, "lang:en") || !hasCallback(kb, "lang:auto") {
+		t.Errorf("missing language callbacks: %+v", kb.InlineKeyboard)
+	}
+	if !strings.HasPrefix(kb.InlineKeyboard[len(kb.InlineKeyboard)-1][0].Text, "✅") {
+		t.Errorf("expected auto to be marked without an override")
+	}
+
+	kb = b.createLanguageKeyboard(ru, i18n.English)
+	if kb.InlineKeyboard[1][0].Text != "✅ English" {
+		t.Errorf("expected the override marked, got %q", kb.InlineKeyboard[1][0].Text)
+	}
+}
+
+func TestKeyboards_English(t *testing.T) {
+	b := &Bot{}
+	en := i18n.For(i18n.English)
+
+	menu := b.createMainMenuKeyboard(en)
+	if menu.Keyboard[0][0].Text != "🔍 Search" || menu.Keyboard[1][0].Text != "ℹ️ Help" {
+		t.Errorf("expected english menu, got %+v", menu.Keyboard)
+	}
+
+	kb := b.createFavoriteAnimeKeyboard(en, 1, &models.Rating{Score: 8})
+	if kb.InlineKeyboard[1][0].Text != "⭐ Rated: 8" {
+		t.Errorf("expected english rating button, got %q", kb.InlineKeyboard[1][0].Text)
+	}
+}
@@ -0,0 +1,124 @@
+package telegram
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+)
+
+// userLanguage is cached per user so rendering a screen never hits the database
+type userLanguage struct {
+	override i18n.Lang // picked with /language, "" follows telegram
+	telegram i18n.Lang
+}
+
+// rememberLanguage runs on every private update: telegram's language_code can
+// change between updates, the override is only loaded once
+func (b *Bot) rememberLanguage(user *tgbotapi.User) {
+	if user == nil {
+		return
+	}
+
+	b.mu.RLock()
+	lang, ok := b.languages[user.ID]
+	b.mu.RUnlock()
+
+	if !ok {
+		override, err := b.animeService.GetLanguage(user.ID)
+		if err != nil {
+			b.logger.Error("Failed to load language for user %d: %v", user.ID, err)
+		}
+		lang.override = override
+	}
+	lang.telegram = i18n.FromTelegram(user.LanguageCode)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.languages[user.ID] = lang
+}
+
+func (b *Bot) forgetLanguage(userID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.languages, userID)
+}
+
+// tr returns the localizer for a private chat; group chats and users the bot
+// hasn't seen yet get the default language
+func (b *Bot) tr(id int64) i18n.Localizer {
+	b.mu.RLock()
+	lang := b.languages[id]
+	b.mu.RUnlock()
+
+	if lang.override != "" {
+		return i18n.For(lang.override)
+	}
+	return i18n.For(lang.telegram)
+}
+
+func (b *Bot) languageOverride(userID int64) i18n.Lang {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.languages[userID].override
+}
+
+func languageName(tr i18n.Localizer, lang i18n.Lang) string {
+	return tr.T("language.name." + string(lang))
+}
+
+func (b *Bot) handleLanguage(userID int64, chatID int64) {
+	tr := b.tr(userID)
+	msg := tgbotapi.NewMessage(chatID, tr.T("language.prompt", languageName(tr, tr.Lang())))
+	msg.ReplyMarkup = b.createLanguageKeyboard(tr, b.languageOverride(userID))
+	b.api.Send(msg)
+}
+
+func (b *Bot) handleLanguageCallback(callback *tgbotapi.CallbackQuery) bool {
+	code, ok := strings.CutPrefix(callback.Data, "lang:")
+	if !ok || callback.Message == nil {
+		return false
+	}
+
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+
+	var override i18n.Lang
+	if code != "auto" {
+		lang, ok := i18n.Parse(code)
+		if !ok {
+			b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+			return true
+		}
+		override = lang
+	}
+
+	if err := b.animeService.SetLanguage(userID, override); err != nil {
+		b.logger.Error("Failed to save language for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, b.tr(userID).T("error.save")))
+		return true
+	}
+
+	b.mu.Lock()
+	lang := b.languages[userID]
+	lang.override = override
+	b.languages[userID] = lang
+	b.mu.Unlock()
+
+	tr := b.tr(userID)
+	b.api.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID,
+		tr.T("language.prompt", languageName(tr, tr.Lang())), b.createLanguageKeyboard(tr, override)))
+
+	// reply keyboards can't be edited, the menu is resent in the new language
+	msg := tgbotapi.NewMessage(chatID, tr.T("language.saved"))
+	msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
+	b.api.Send(msg)
+	b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+	return true
+}
+
+// isCancel matches the reply keyboard's cancel button in any language
+func isCancel(text string) bool {
+	_, ok := i18n.Match(text, "menu.cancel")
+	return ok
+}
@@ -0,0 +1,35 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+)
+
+func TestBotTr(t *testing.T) {
+	b := &Bot{languages: map[int64]userLanguage{
+		1: {telegram: i18n.English},
+		2: {override: i18n.Russian, telegram: i18n.English},
+	}}
+
+	if got := b.tr(1).Lang(); got != i18n.English {
+		t.Errorf("expected telegram language, got %q", got)
+	}
+	if got := b.tr(2).Lang(); got != i18n.Russian {
+		t.Errorf("expected the override to win, got %q", got)
+	}
+	if got := b.tr(-100).Lang(); got != i18n.Default {
+		t.Errorf("expected the default for a group chat, got %q", got)
+	}
+}
+
+func TestIsCancel(t *testing.T) {
+	for _, text := range []string{"Отмена", "Cancel"} {
+		if !isCancel(text) {
+			t.Errorf("%q: expected cancel", text)
+		}
+	}
+	if isCancel("cancel please") {
+		t.Error("expected free text not to cancel")
+	}
+}
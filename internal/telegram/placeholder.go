@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+// posters on shikimori are roughly 225x320, the placeholder keeps their shape
+const (
+	placeholderWidth  = 360
+	placeholderHeight = 510
+	placeholderMargin = 30
+	placeholderLines  = 8
+)
+
+var (
+	placeholderBackground = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+	placeholderHeader     = color.RGBA{0x4a, 0x90, 0xe2, 0xff}
+	placeholderTitle      = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	placeholderWhite      = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// renderPlaceholderPNG draws the card sent when no poster could be fetched.
+// The built-in bitmap font only covers ASCII, so the title is the romaji name
+func renderPlaceholderPNG(w io.Writer, name string, animeID int) error {
+	img := image.NewRGBA(image.Rect(0, 0, placeholderWidth, placeholderHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{placeholderBackground}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, placeholderWidth, 70), &image.Uniform{placeholderHeader}, image.Point{}, draw.Src)
+	stats.DrawColoredText(img, placeholderMargin, 42, "NO POSTER", placeholderWhite)
+
+	y := 120
+	for _, line := range wrapPlaceholderTitle(stats.ASCIILabel(name, animeID), placeholderWidth-placeholderMargin*2) {
+		stats.DrawColoredText(img, placeholderMargin, y, line, placeholderTitle)
+		y += 22
+	}
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode placeholder: %w", err)
+	}
+	return nil
+}
+
+// wrapPlaceholderTitle breaks the title into at most placeholderLines lines of
+// width pixels, words longer than a line are cut
+func wrapPlaceholderTitle(title string, width int) []string {
+	fit := func(line string) string {
+		for line != "" && stats.TextWidth(line) > width {
+			line = line[:len(line)-1]
+		}
+		return line
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(title) {
+		switch {
+		case line == "":
+			line = word
+		case stats.TextWidth(line+" "+word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, fit(line))
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, fit(line))
+	}
+	if len(lines) > placeholderLines {
+		lines = lines[:placeholderLines]
+	}
+	return lines
+}
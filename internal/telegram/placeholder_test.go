@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+func TestRenderPlaceholderPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderPlaceholderPNG(&buf, "Shingeki no Kyojin", 16498); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("placeholder is not a valid png: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != placeholderWidth || b.Dy() != placeholderHeight {
+		t.Errorf("unexpected size %v", b)
+	}
+}
+
+func TestWrapPlaceholderTitle(t *testing.T) {
+	lines := wrapPlaceholderTitle("Sen to Chihiro no Kamikakushi", 120)
+	if len(lines) < 2 || strings.Join(lines, " ") != "Sen to Chihiro no Kamikakushi" {
+		t.Errorf("unexpected lines: %q", lines)
+	}
+	for _, line := range lines {
+		if stats.TextWidth(line) > 120 {
+			t.Errorf("line %q is wider than the limit", line)
+		}
+	}
+
+	long := wrapPlaceholderTitle(strings.Repeat("Oshi ", 100), 120)
+	if len(long) != placeholderLines {
+		t.Errorf("expected %d lines, got %d", placeholderLines, len(long))
+	}
+}
@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+// isMessageError tells errors about the message itself, which another poster
+// file won't fix
+func isMessageError(err error) bool {
+	return isNotModified(err) || isMessageGone(err) ||
+		(err != nil && strings.Contains(err.Error(), "caption is too long"))
+}
+
+// isWrongFileID tells telegram rejected a cached file_id, e.g. after the bot
+// token changed. Timeouts and server errors say nothing about the file
+func isWrongFileID(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 {
+		return false
+	}
+	return strings.Contains(apiErr.Message, "wrong file identifier") ||
+		strings.Contains(apiErr.Message, "wrong remote file identifier")
+}
+
+// deliverPoster hands the card's photo to send, which wraps it into a new
+// message or an edit. Posters telegram already has go first, then the ones
+// downloaded from shikimori from best to worst, then a generated placeholder
+func (b *Bot) deliverPoster(anime *models.Anime, send func(file tgbotapi.RequestFileData) (tgbotapi.Message, error)) error {
+	sources := service.PosterSources(anime.Image)
+
+	for _, source := range sources {
+		fileID, err := b.animeService.PosterFileID(anime.ID, source.Size, source.Path)
+		if err != nil {
+			b.logger.Error("Failed to get poster file for anime ID %d: %v", anime.ID, err)
+		}
+		if fileID == "" {
+			continue
+		}
+		_, err = send(tgbotapi.FileID(fileID))
+		if err == nil || isMessageError(err) {
+			return err
+		}
+		b.logger.Error("Failed to send cached poster for anime ID %d: %v", anime.ID, err)
+		if isWrongFileID(err) {
+			b.forgetPoster(anime.ID, source)
+		}
+	}
+
+	for _, source := range sources {
+		data, err := b.animeService.FetchPoster(source.Path)
+		if err != nil {
+			b.logger.Error("Failed to fetch %s poster for anime ID %d: %v", source.Size, anime.ID, err)
+			continue
+		}
+		sent, err := send(tgbotapi.FileBytes{Name: path.Base(source.Path), Bytes: data})
+		if isMessageError(err) {
+			return err
+		}
+		if err != nil {
+			b.logger.Error("Failed to upload %s poster for anime ID %d: %v", source.Size, anime.ID, err)
+			continue
+		}
+		b.rememberPoster(anime.ID, source, sent)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := renderPlaceholderPNG(&buf, anime.Name, anime.ID); err != nil {
+		return err
+	}
+	_, err := send(tgbotapi.FileBytes{Name: "placeholder.png", Bytes: buf.Bytes()})
+	return err
+}
+
+// sendAnimeCard sends a new photo card, or a text one when even the
+// placeholder didn't go through
+func (b *Bot) sendAnimeCard(chatID int64, anime *models.Anime, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	err := b.deliverPoster(anime, func(file tgbotapi.RequestFileData) (tgbotapi.Message, error) {
+		photo := tgbotapi.NewPhoto(chatID, file)
+		photo.Caption = text
+		photo.ParseMode = "Markdown"
+		photo.ReplyMarkup = keyboard
+		return b.api.Send(photo)
+	})
+	if err == nil {
+		return
+	}
+	b.logger.Error("Failed to send photo for anime ID %d: %v", anime.ID, err)
+	text += "\n\n" + b.tr(chatID).T("card.image_unavailable")
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err := b.api.Send(msg); err != nil {
+		b.logger.Error("Failed to send message for anime ID %d: %v", anime.ID, err)
+	}
+}
+
+// rememberPoster stores the file_id of the largest size telegram made of the upload
+func (b *Bot) rememberPoster(animeID int, source service.PosterSource, sent tgbotapi.Message) {
+	if len(sent.Photo) == 0 {
+		return
+	}
+	fileID := sent.Photo[len(sent.Photo)-1].FileID
+	if err := b.animeService.SavePosterFileID(animeID, source.Size, source.Path, fileID); err != nil {
+		b.logger.Error("Failed to save poster file for anime ID %d: %v", animeID, err)
+	}
+}
+
+func (b *Bot) forgetPoster(animeID int, source service.PosterSource) {
+	if err := b.animeService.ForgetPosterFileID(animeID, source.Size); err != nil {
+		b.logger.Error("Failed to forget poster file for anime ID %d: %v", animeID, err)
+	}
+}
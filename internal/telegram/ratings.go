@@ -0,0 +1,234 @@
+package telegram
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/database"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+func ratingsOverviewText(tr i18n.Localizer, distribution map[int]int) string {
+	total := 0
+	for _, count := range distribution {
+		total += count
+	}
+	if total == 0 {
+		return tr.T("ratings.empty", tr.T("button.rate"))
+	}
+	return tr.T("ratings.title", total)
+}
+
+func (b *Bot) handleRatings(userID int64, chatID int64) {
+	distribution, err := b.animeService.GetRatingDistribution(userID)
+	if err != nil {
+		b.logger.Error("Failed to get ratings for user %d: %v", userID, err)
+		msg := tgbotapi.NewMessage(chatID, b.tr(chatID).T("ratings.load_failed"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(b.tr(chatID))
+		b.api.Send(msg)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, ratingsOverviewText(b.tr(chatID), distribution))
+	if len(distribution) > 0 {
+		msg.ReplyMarkup = b.createRatingsOverviewKeyboard(b.tr(chatID), distribution)
+	}
+	b.api.Send(msg)
+}
+
+func (b *Bot) editRatingsOverview(chatID int64, messageID int, userID int64) {
+	distribution, err := b.animeService.GetRatingDistribution(userID)
+	if err != nil {
+		b.logger.Error("Failed to get ratings for user %d: %v", userID, err)
+		return
+	}
+
+	keyboard := b.createRatingsOverviewKeyboard(b.tr(chatID), distribution)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, ratingsOverviewText(b.tr(chatID), distribution))
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+func (b *Bot) ratingsListView(userID int64) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+
+	distribution, err := b.animeService.GetRatingDistribution(userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	total := distribution[state.RatingsScore]
+	if state.RatingsScore == 0 {
+		total = 0
+		for _, count := range distribution {
+			total += count
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(favoritesPerPage)))
+	state.RatingsPage = clampPage(state.RatingsPage, totalPages)
+	b.saveState(userID, state)
+
+	ratings, err := b.animeService.GetUserRatings(userID, database.RatingsQuery{
+		Score:  state.RatingsScore,
+		Sort:   database.RatingSort(state.RatingsSort),
+		Limit:  favoritesPerPage,
+		Offset: state.RatingsPage * favoritesPerPage,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	tr := b.tr(userID)
+	text := tr.T("ratings.score_title", state.RatingsScore, total)
+	if state.RatingsScore == 0 {
+		text = tr.T("ratings.all_title", total)
+	}
+
+	keyboard := b.createRatingsListKeyboard(tr, ratings, state.RatingsScore == 0, state.RatingsPage, totalPages)
+	return text, &keyboard, nil
+}
+
+func (b *Bot) showRatingsList(chatID int64, userID int64) {
+	text, keyboard, err := b.ratingsListView(userID)
+	if err != nil {
+		b.logger.Error("Failed to get ratings list for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("ratings.load_failed")))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	b.api.Send(msg)
+}
+
+func (b *Bot) editRatingsList(chatID int64, messageID int, userID int64) {
+	text, keyboard, err := b.ratingsListView(userID)
+	if err != nil {
+		b.logger.Error("Failed to get ratings list for user %d: %v", userID, err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ReplyMarkup = keyboard
+	b.api.Send(edit)
+}
+
+func (b *Bot) showRatedAnime(chatID int64, userID int64, animeID int) {
+	anime, err := b.animeService.GetAnimeByID(animeID)
+	if err != nil {
+		b.logger.Error("Failed to get anime details: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("anime.load_failed")))
+		return
+	}
+
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.RatedAnimeID = animeID
+	state.CollectionAnimeID = 0
+	b.saveState(userID, state)
+
+	isFav, _ := b.animeService.IsFavorite(userID, animeID)
+	userRating, _ := b.animeService.GetUserRating(userID, animeID)
+	note, _ := b.animeService.GetUserNote(userID, animeID)
+	community, _ := b.animeService.GetCommunityRating(animeID)
+	alsoLiked, _ := b.animeService.GetAlsoLiked(animeID)
+	friends, _ := b.animeService.GetFriendRatings(userID, animeID)
+
+	text := utils.FormatAnimeMessageWithRating(b.tr(chatID), b.settings(userID), anime, isFav, userRating, note, community, alsoLiked, friends)
+	history, _ := b.animeService.GetRatingHistory(userID, animeID)
+	if line := utils.FormatRatingHistory(b.tr(chatID), history); line != "" {
+		if utf8.RuneCountInString(text)+utf8.RuneCountInString(line)+1 <= utils.CaptionMaxLength {
+			text += "\n" + line
+		}
+	}
+
+	keyboard := b.createRatedAnimeKeyboard(b.tr(chatID), animeID, userRating)
+	b.sendAnimeCard(chatID, anime, text, keyboard)
+}
+
+func (b *Bot) handleRatingsCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	data := callback.Data
+
+	switch data {
+	case "ratings":
+		b.editRatingsOverview(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "rt_page":
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "rt_next", "rt_prev":
+		state := b.getState(userID)
+		if state != nil {
+			if data == "rt_next" {
+				state.RatingsPage++
+			} else {
+				state.RatingsPage--
+			}
+			b.saveState(userID, state)
+			b.editRatingsList(chatID, messageID, userID)
+		}
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case "rt_back":
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.showRatingsList(chatID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 7 && data[:7] == "rscore:" {
+		score := 0
+		fmt.Sscanf(data, "rscore:%d", &score)
+
+		b.openRatingsList(userID, score, database.RatingSortDate)
+		b.editRatingsList(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 6 && data[:6] == "rlist:" {
+		b.openRatingsList(userID, 0, database.RatingSort(data[6:]))
+		b.editRatingsList(chatID, messageID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if len(data) > 11 && data[:11] == "show_rated:" {
+		animeID := 0
+		fmt.Sscanf(data, "show_rated:%d", &animeID)
+
+		b.api.Send(tgbotapi.NewDeleteMessage(chatID, messageID))
+		b.showRatedAnime(chatID, userID, animeID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	return false
+}
+
+func (b *Bot) openRatingsList(userID int64, score int, sort database.RatingSort) {
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.RatingsScore = score
+	state.RatingsSort = string(sort)
+	state.RatingsPage = 0
+	b.saveState(userID, state)
+}
@@ -0,0 +1,175 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/recommend"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+func signalTitle(tr i18n.Localizer, signal models.TasteSignal) string {
+	if signal.Title != "" {
+		return signal.Title
+	}
+	return tr.T("anime.number", signal.AnimeID)
+}
+
+func recommendReasonText(tr i18n.Localizer, reason recommend.Reason) string {
+	title := signalTitle(tr, reason.Signal)
+
+	if reason.Genre == nil {
+		switch {
+		case reason.Signal.Score != nil:
+			return tr.T("recommend.reason.rated", title, *reason.Signal.Score)
+		case reason.Signal.Favorite:
+			return tr.T("recommend.reason.favorite", title)
+		default:
+			return tr.T("recommend.reason.liked", title)
+		}
+	}
+
+	genre := genreLabel(tr, reason.Genre.Name, reason.Genre.Russian)
+	mark := "👍"
+	switch {
+	case reason.Signal.Score != nil:
+		mark = fmt.Sprintf("⭐ %d", *reason.Signal.Score)
+	case reason.Signal.Favorite:
+		mark = "❤️"
+	}
+	return tr.T("recommend.reason.genre", genre, title, mark)
+}
+
+func recommendationsText(tr i18n.Localizer, settings models.UserSettings, picks []recommend.Pick) string {
+	if len(picks) == 0 {
+		return tr.T("recommend.thanks")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("recommend.title") + "\n")
+	for i, pick := range picks {
+		title := utils.AnimeTitle(tr, settings, &pick.Anime)
+		fmt.Fprintf(&sb, "\n%d. %s — %s", i+1, title, recommendReasonText(tr, pick.Reason))
+	}
+	sb.WriteString("\n\n" + tr.T("recommend.hint"))
+	return sb.String()
+}
+
+func (b *Bot) handleRecommend(userID int64, chatID int64) {
+	tr := b.tr(userID)
+	picks, err := b.animeService.Recommend(userID, service.RecommendLimit)
+	if errors.Is(err, service.ErrNotEnoughTaste) {
+		msg := tgbotapi.NewMessage(chatID, tr.T("recommend.not_enough"))
+		msg.ReplyMarkup = b.createMainMenuKeyboard(tr)
+		b.api.Send(msg)
+		return
+	}
+	if err != nil {
+		b.logger.Error("Failed to recommend for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("recommend.failed")))
+		return
+	}
+
+	if len(picks) == 0 {
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("recommend.nothing_new")))
+		return
+	}
+
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.Recommendations = picks
+	b.saveState(userID, state)
+
+	msg := tgbotapi.NewMessage(chatID, recommendationsText(tr, b.settings(userID), picks))
+	msg.ReplyMarkup = b.createRecommendationsKeyboard(tr, picks)
+	b.api.Send(msg)
+}
+
+func (b *Bot) editRecommendations(userID int64, chatID int64, messageID int, picks []recommend.Pick) {
+	tr := b.tr(userID)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, recommendationsText(tr, b.settings(userID), picks))
+	if len(picks) > 0 {
+		keyboard := b.createRecommendationsKeyboard(tr, picks)
+		edit.ReplyMarkup = &keyboard
+	}
+	b.api.Send(edit)
+}
+
+func (b *Bot) handleRecommendCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+	tr := b.tr(userID)
+
+	if len(data) > 9 && data[:9] == "rec_show:" {
+		animeID := 0
+		fmt.Sscanf(data, "rec_show:%d", &animeID)
+
+		anime, err := b.animeService.GetAnimeByID(animeID)
+		if err != nil {
+			b.logger.Error("Failed to get anime details: %v", err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("anime.load_failed")))
+			return true
+		}
+
+		state := b.getState(userID)
+		if state == nil {
+			state = &UserState{}
+		}
+		state.SearchResults = []models.Anime{*anime}
+		state.CurrentIndex = 0
+		state.CollectionAnimeID = 0
+		state.RatedAnimeID = 0
+		b.saveState(userID, state)
+
+		b.showCurrentAnime(chatID, userID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	var animeID int
+	var liked bool
+	switch {
+	case len(data) > 9 && data[:9] == "rec_like:":
+		fmt.Sscanf(data, "rec_like:%d", &animeID)
+		liked = true
+	case len(data) > 12 && data[:12] == "rec_dislike:":
+		fmt.Sscanf(data, "rec_dislike:%d", &animeID)
+	default:
+		return false
+	}
+
+	if err := b.animeService.SaveRecommendationFeedback(userID, animeID, liked); err != nil {
+		b.logger.Error("Failed to save recommendation feedback for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("recommend.feedback_failed")))
+		return true
+	}
+
+	// reacted titles are never suggested again, so drop them from the list at once
+	state := b.getState(userID)
+	if state != nil {
+		picks := state.Recommendations[:0]
+		for _, pick := range state.Recommendations {
+			if pick.Anime.ID != animeID {
+				picks = append(picks, pick)
+			}
+		}
+		state.Recommendations = picks
+		b.saveState(userID, state)
+		b.editRecommendations(userID, chatID, callback.Message.MessageID, picks)
+	}
+
+	answer := tr.T("recommend.liked")
+	if !liked {
+		answer = tr.T("recommend.disliked")
+	}
+	b.api.Send(tgbotapi.NewCallback(callback.ID, answer))
+	return true
+}
@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+// settings is what screens render with; when loading fails the defaults keep
+// the bot usable
+func (b *Bot) settings(userID int64) models.UserSettings {
+	settings, err := b.animeService.GetSettings(userID)
+	if err != nil {
+		b.logger.Error("Failed to load settings for user %d: %v", userID, err)
+	}
+	return settings
+}
+
+func nextOf[T comparable](values []T, current T) T {
+	return values[(slices.Index(values, current)+1)%len(values)]
+}
+
+func zoneName(tr i18n.Localizer, zone string) string {
+	if zone == "" {
+		return tr.T("settings.zone_server")
+	}
+	return zone
+}
+
+func (b *Bot) handleSettings(userID int64, chatID int64, args string) {
+	tr := b.tr(userID)
+	args = strings.TrimSpace(args)
+
+	if args == "tz" {
+		msg := tgbotapi.NewMessage(chatID, tr.T("settings.pick_zone"))
+		msg.ReplyMarkup = b.createTimeZoneKeyboard(tr, b.settings(userID).TimeZone)
+		b.api.Send(msg)
+		return
+	}
+	if zone, ok := strings.CutPrefix(args, "tz "); ok {
+		b.setTimeZone(userID, chatID, strings.TrimSpace(zone))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, tr.T("settings.title"))
+	msg.ReplyMarkup = b.createSettingsKeyboard(tr, b.settings(userID))
+	b.api.Send(msg)
+}
+
+func (b *Bot) setTimeZone(userID int64, chatID int64, zone string) {
+	tr := b.tr(userID)
+	settings, err := b.animeService.GetSettings(userID)
+	if err == nil {
+		settings.TimeZone = zone
+		err = b.animeService.SaveSettings(settings)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrInvalidSetting):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("settings.unknown_zone")))
+	case err != nil:
+		b.logger.Error("Failed to save time zone for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("error.save")))
+	default:
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("settings.zone_saved", zoneName(tr, zone))))
+	}
+}
+
+func (b *Bot) handleSettingsCallback(callback *tgbotapi.CallbackQuery) bool {
+	action, ok := strings.CutPrefix(callback.Data, "set:")
+	if !ok || callback.Message == nil {
+		return false
+	}
+
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	tr := b.tr(userID)
+
+	if action == "lang" {
+		b.handleLanguage(userID, chatID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	// a failed load must not overwrite the stored settings with defaults
+	settings, err := b.animeService.GetSettings(userID)
+	if err != nil {
+		b.logger.Error("Failed to load settings for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.short")))
+		return true
+	}
+
+	if action == "tz" {
+		b.api.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, tr.T("settings.pick_zone"),
+			b.createTimeZoneKeyboard(tr, settings.TimeZone)))
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	switch {
+	case action == "menu":
+	case action == "titles":
+		settings.TitleDisplay = nextOf(service.TitleDisplays, settings.TitleDisplay)
+	case action == "nsfw":
+		settings.HideNSFW = !settings.HideNSFW
+	case action == "spoilers":
+		settings.HideSpoilers = !settings.HideSpoilers
+	case action == "follows":
+		settings.NotifyFollows = !settings.NotifyFollows
+	case action == "wrapped":
+		settings.NotifyWrapped = !settings.NotifyWrapped
+	case action == "page":
+		settings.PageSize = nextOf(service.PageSizes, settings.PageSize)
+		// list cursors were taken with the old page size
+		if state := b.getState(userID); state != nil {
+			state.FavoritesCursors = nil
+			state.CollectionCursors = nil
+			b.saveState(userID, state)
+		}
+	case strings.HasPrefix(action, "tz:"):
+		settings.TimeZone = strings.TrimPrefix(action, "tz:")
+	default:
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	if action != "menu" {
+		if err := b.animeService.SaveSettings(settings); err != nil {
+			b.logger.Error("Failed to save settings for user %d: %v", userID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("error.save")))
+			return true
+		}
+	}
+
+	b.api.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, tr.T("settings.title"),
+		b.createSettingsKeyboard(tr, settings)))
+	b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+	return true
+}
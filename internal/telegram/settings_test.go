@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+func TestCreateSettingsKeyboard(t *testing.T) {
+	b := &Bot{}
+	settings := service.DefaultSettings(1)
+	settings.HideSpoilers = true
+
+	kb := b.createSettingsKeyboard(ru, settings)
+	for _, data := range []string{"set:lang", "set:titles", "set:tz", "set:nsfw", "set:spoilers", "set:page", "set:follows", "set:wrapped"} {
+		if !hasCallback(kb, data) {
+			t.Errorf("expected %s button", data)
+		}
+	}
+	if got := kb.InlineKeyboard[4][0].Text; got != "🙈 Скрывать описания: да" {
+		t.Errorf("unexpected spoilers label %q", got)
+	}
+}
+
+func TestCreateTimeZoneKeyboard(t *testing.T) {
+	b := &Bot{}
+	kb := b.createTimeZoneKeyboard(ru, "Asia/Tokyo")
+
+	for _, zone := range service.TimeZones {
+		if !hasCallback(kb, "set:tz:"+zone) {
+			t.Errorf("expected button for %q", zone)
+		}
+	}
+	if !hasCallback(kb, "set:menu") {
+		t.Error("expected back button")
+	}
+	if got := kb.InlineKeyboard[0][0].Text; got != "как у бота" {
+		t.Errorf("expected the server zone first, got %q", got)
+	}
+
+	found := false
+	for _, row := range kb.InlineKeyboard {
+		for _, button := range row {
+			found = found || button.Text == "✅ Asia/Tokyo"
+		}
+	}
+	if !found {
+		t.Error("expected the current zone marked")
+	}
+}
+
+func TestNextOf(t *testing.T) {
+	if got := nextOf(service.PageSizes, 20); got != 5 {
+		t.Errorf("expected the choices to wrap around, got %d", got)
+	}
+	// a value that is no longer offered starts over from the first choice
+	if got := nextOf(service.TitleDisplays, "kanji"); got != models.TitleDisplayAuto {
+		t.Errorf("expected %q, got %q", models.TitleDisplayAuto, got)
+	}
+}
@@ -0,0 +1,236 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+	"github.com/wywyy3cee/tgbot-anime-tracker/pkg/utils"
+)
+
+// /start payloads of share links; telegram allows [A-Za-z0-9_-] up to 64 chars
+const (
+	animeStartPrefix      = "anime_"
+	listStartPrefix       = "list_"
+	collectionStartPrefix = "col_"
+)
+
+// sharedListShown keeps a shared list within a single message
+const sharedListShown = 30
+
+func startLink(botName string, payload string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", botName, payload)
+}
+
+// shareURL opens Telegram's "forward to…" picker with the link prefilled
+func shareURL(link string, text string) string {
+	return "https://t.me/share/url?" + url.Values{"url": {link}, "text": {text}}.Encode()
+}
+
+func collectionPayload(code string, collectionID int) string {
+	return fmt.Sprintf("%s%s_%d", collectionStartPrefix, code, collectionID)
+}
+
+// parseCollectionPayload reads "<code>_<id>", what follows collectionStartPrefix
+func parseCollectionPayload(payload string) (string, int, bool) {
+	code, rawID, ok := strings.Cut(payload, "_")
+	if !ok || code == "" {
+		return "", 0, false
+	}
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		return "", 0, false
+	}
+	return code, id, true
+}
+
+func (b *Bot) handleStartPayload(userID int64, chatID int64, username string, payload string) {
+	if code, ok := strings.CutPrefix(payload, friendStartPrefix); ok {
+		b.handleFollow(userID, chatID, username, code)
+		return
+	}
+	if rawID, ok := strings.CutPrefix(payload, animeStartPrefix); ok {
+		if animeID, err := strconv.Atoi(rawID); err == nil {
+			b.openSharedAnime(userID, chatID, animeID)
+		}
+		return
+	}
+	if code, ok := strings.CutPrefix(payload, listStartPrefix); ok {
+		b.showSharedFavorites(chatID, code)
+		return
+	}
+	if rest, ok := strings.CutPrefix(payload, collectionStartPrefix); ok {
+		if code, collectionID, ok := parseCollectionPayload(rest); ok {
+			b.showSharedCollection(chatID, code, collectionID)
+		}
+	}
+}
+
+// openSharedAnime shows a card as if the user had found it through search
+func (b *Bot) openSharedAnime(userID int64, chatID int64, animeID int) {
+	anime, err := b.animeService.GetAnimeByID(animeID)
+	if err != nil {
+		b.logger.Error("Failed to get anime details: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("share.open_failed")))
+		return
+	}
+
+	state := b.getState(userID)
+	if state == nil {
+		state = &UserState{}
+	}
+	state.SearchResults = []models.Anime{*anime}
+	state.CurrentIndex = 0
+	b.saveState(userID, state)
+	b.showCurrentAnime(chatID, userID)
+}
+
+func sharedListText(tr i18n.Localizer, header string, entries []listEntry) string {
+	if len(entries) == 0 {
+		return header + "\n\n" + tr.T("share.list_empty")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s (%d):\n", header, len(entries))
+	for i, entry := range entries {
+		if i == sharedListShown {
+			sb.WriteString("\n" + tr.T("list.more", len(entries)-sharedListShown))
+			break
+		}
+		fmt.Fprintf(&sb, "\n%d. %s", i+1, entry.Title)
+	}
+	sb.WriteString("\n\n" + tr.T("share.list_hint"))
+	return sb.String()
+}
+
+func (b *Bot) sendSharedList(chatID int64, header string, entries []listEntry) {
+	msg := tgbotapi.NewMessage(chatID, sharedListText(b.tr(chatID), header, entries))
+	if len(entries) > 0 {
+		msg.ReplyMarkup = b.createSharedListKeyboard(entries)
+	}
+	b.api.Send(msg)
+}
+
+func (b *Bot) sharedLinkError(chatID int64, err error) {
+	tr := b.tr(chatID)
+	switch {
+	case errors.Is(err, service.ErrUnknownFriendCode), errors.Is(err, service.ErrSharedCollectionAbsent):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("share.link_expired")))
+	case errors.Is(err, service.ErrListHidden):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("share.list_hidden")))
+	default:
+		b.logger.Error("Failed to open shared list in chat %d: %v", chatID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("share.list_failed")))
+	}
+}
+
+func (b *Bot) showSharedFavorites(chatID int64, code string) {
+	owner, favorites, err := b.animeService.GetSharedFavorites(code)
+	if err != nil {
+		b.sharedLinkError(chatID, err)
+		return
+	}
+
+	entries := make([]listEntry, 0, len(favorites))
+	for _, fav := range favorites {
+		entries = append(entries, listEntry{Title: fav.Title, Data: fmt.Sprintf("share_open:%d", fav.AnimeID)})
+	}
+	b.sendSharedList(chatID, b.tr(chatID).T("share.favorites_header", utils.FriendName(owner.Username, owner.ID)), entries)
+}
+
+func (b *Bot) showSharedCollection(chatID int64, code string, collectionID int) {
+	owner, collection, items, err := b.animeService.GetSharedCollection(code, collectionID)
+	if err != nil {
+		b.sharedLinkError(chatID, err)
+		return
+	}
+
+	entries := make([]listEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, listEntry{Title: item.Title, Data: fmt.Sprintf("share_open:%d", item.AnimeID)})
+	}
+	header := b.tr(chatID).T("share.collection_header", collection.Name, utils.FriendName(owner.Username, owner.ID))
+	b.sendSharedList(chatID, header, entries)
+}
+
+func (b *Bot) sendShareLink(chatID int64, intro string, link string, shareText string) {
+	tr := b.tr(chatID)
+	text := intro + "\n" + link + "\n\n" + tr.T("share.forward_hint")
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = b.createShareKeyboard(tr, link, shareText)
+	msg.DisableWebPagePreview = true
+	b.api.Send(msg)
+}
+
+func (b *Bot) handleShareCallback(callback *tgbotapi.CallbackQuery) bool {
+	data := callback.Data
+	if !strings.HasPrefix(data, "share") || callback.Message == nil {
+		return false
+	}
+
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	botName := b.api.Self.UserName
+	tr := b.tr(userID)
+
+	switch {
+	case strings.HasPrefix(data, "share:"):
+		animeID, _ := strconv.Atoi(strings.TrimPrefix(data, "share:"))
+		anime, err := b.animeService.GetAnimeByID(animeID)
+		if err != nil {
+			b.logger.Error("Failed to get anime details: %v", err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("anime.load_failed")))
+			return true
+		}
+
+		title := utils.AnimeTitle(tr, b.settings(userID), anime)
+		link := startLink(botName, fmt.Sprintf("%s%d", animeStartPrefix, anime.ID))
+		b.sendShareLink(chatID, tr.T("share.anime_link", title), link, title)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case data == "share_favs" || strings.HasPrefix(data, "share_col:"):
+		code, err := b.animeService.GetFriendCode(userID)
+		if err != nil {
+			b.logger.Error("Failed to get friend code for user %d: %v", userID, err)
+			b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("share.link_failed")))
+			return true
+		}
+
+		intro, shareText := tr.T("share.favorites_link"), tr.T("share.favorites_text")
+		link := startLink(botName, listStartPrefix+code)
+		if data != "share_favs" {
+			collectionID, _ := strconv.Atoi(strings.TrimPrefix(data, "share_col:"))
+			collection, err := b.animeService.GetCollection(userID, collectionID)
+			if err != nil || collection == nil {
+				b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("collections.not_found")))
+				return true
+			}
+			intro = tr.T("share.collection_link", collection.Name)
+			shareText = tr.T("share.collection_text", collection.Name)
+			link = startLink(botName, collectionPayload(code, collectionID))
+		}
+
+		// the links respect the same switch as friends do
+		if share, err := b.animeService.GetShareList(userID); err == nil && !share {
+			intro = tr.T("share.hidden_notice") + "\n\n" + intro
+		}
+		b.sendShareLink(chatID, intro, link, shareText)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+
+	case strings.HasPrefix(data, "share_open:"):
+		animeID, _ := strconv.Atoi(strings.TrimPrefix(data, "share_open:"))
+		b.openSharedAnime(userID, chatID, animeID)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	return false
+}
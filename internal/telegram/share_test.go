@@ -0,0 +1,59 @@
+package telegram
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestStartLinks(t *testing.T) {
+	if got := startLink("AnimeTrackerBot", animeStartPrefix+"5114"); got != "https://t.me/AnimeTrackerBot?start=anime_5114" {
+		t.Errorf("unexpected anime link: %s", got)
+	}
+	if got := friendDeepLink("AnimeTrackerBot", "abcd2345"); got != "https://t.me/AnimeTrackerBot?start=friend_abcd2345" {
+		t.Errorf("unexpected friend link: %s", got)
+	}
+
+	payload := collectionPayload("abcd2345", 17)
+	if len(payload) > 64 {
+		t.Errorf("payload %q is longer than telegram allows", payload)
+	}
+	code, id, ok := parseCollectionPayload(strings.TrimPrefix(payload, collectionStartPrefix))
+	if !ok || code != "abcd2345" || id != 17 {
+		t.Errorf("expected the payload to round-trip, got %q %d %v", code, id, ok)
+	}
+}
+
+func TestParseCollectionPayload_Invalid(t *testing.T) {
+	for _, payload := range []string{"", "abcd2345", "abcd2345_x", "_17"} {
+		if _, _, ok := parseCollectionPayload(payload); ok {
+			t.Errorf("%q: expected invalid payload", payload)
+		}
+	}
+}
+
+func TestShareURL(t *testing.T) {
+	link := "https://t.me/AnimeTrackerBot?start=anime_1"
+	parsed, err := url.Parse(shareURL(link, "Ковбой Бибоп & co"))
+	if err != nil {
+		t.Fatalf("invalid share url: %v", err)
+	}
+	if parsed.Host != "t.me" || parsed.Query().Get("url") != link || parsed.Query().Get("text") != "Ковбой Бибоп & co" {
+		t.Errorf("unexpected share url: %s", parsed)
+	}
+}
+
+func TestSharedListText(t *testing.T) {
+	entries := make([]listEntry, sharedListShown+2)
+	for i := range entries {
+		entries[i] = listEntry{Title: "Monster", Data: "share_open:1"}
+	}
+
+	text := sharedListText(ru, "❤️ Избранное alice", entries)
+	if !strings.Contains(text, "(32)") || !strings.Contains(text, "…и еще 2") {
+		t.Errorf("unexpected text: %s", text)
+	}
+	if text := sharedListText(ru, "❤️ Избранное alice", nil); !strings.Contains(text, "пусто") {
+		t.Errorf("expected an empty list notice, got %s", text)
+	}
+}
@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+var statsKindKeys = map[string]string{
+	"tv":      "stats.kind.tv",
+	"movie":   "stats.kind.movie",
+	"ova":     "stats.kind.ova",
+	"ona":     "stats.kind.ona",
+	"special": "stats.kind.special",
+	"music":   "stats.kind.music",
+	"unknown": "stats.kind.unknown",
+}
+
+func statsPeriodLabel(tr i18n.Localizer, period stats.Period) string {
+	switch period.Name {
+	case stats.PeriodAll:
+		return tr.T("stats.period.all")
+	case stats.PeriodYear:
+		return tr.T("stats.period.year")
+	case stats.PeriodMonth:
+		return tr.T("stats.period.month")
+	}
+	return tr.T("stats.period.custom", period.Name)
+}
+
+// genreLabel names a genre the way cards do: russian names for the russian locale only
+func genreLabel(tr i18n.Localizer, name string, russian string) string {
+	if russian == "" || tr.Lang() != i18n.Russian {
+		return name
+	}
+	return russian
+}
+
+func statsCaption(tr i18n.Localizer, period stats.Period, s stats.Summary) string {
+	var sb strings.Builder
+	sb.WriteString(tr.T("stats.title", statsPeriodLabel(tr, period)) + "\n\n")
+	sb.WriteString(tr.N("stats.titles", s.Titles, s.Titles, s.Favorites, s.Rated) + "\n")
+	if s.Rated > 0 {
+		sb.WriteString(tr.T("stats.average", s.AverageScore) + "\n")
+	}
+	sb.WriteString(tr.T("stats.hours", s.HoursWatched()) + "\n")
+	if s.Uncataloged > 0 {
+		sb.WriteString(tr.N("stats.uncataloged", s.Uncataloged, s.Uncataloged) + "\n")
+	}
+
+	if len(s.Kinds) > 0 {
+		kinds := make([]string, 0, len(s.Kinds))
+		for _, kind := range s.Kinds {
+			label := kind.Kind
+			if key, ok := statsKindKeys[kind.Kind]; ok {
+				label = tr.T(key)
+			}
+			kinds = append(kinds, fmt.Sprintf("%s %d", label, kind.Count))
+		}
+		sb.WriteString(tr.T("stats.kinds", strings.Join(kinds, ", ")) + "\n")
+	}
+
+	if len(s.Genres) > 0 {
+		genres := make([]string, 0, len(s.Genres))
+		for _, genre := range s.Genres {
+			genres = append(genres, fmt.Sprintf("%s %d", genreLabel(tr, genre.Name, genre.Russian), genre.Count))
+		}
+		sb.WriteString(tr.T("stats.genres", strings.Join(genres, ", ")) + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func (b *Bot) handleStats(userID int64, chatID int64, args string) {
+	period, err := stats.ParsePeriod(args, time.Now().In(b.animeService.UserLocation(userID)))
+	if errors.Is(err, stats.ErrInvalidPeriod) {
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("stats.usage")))
+		return
+	}
+
+	b.sendStats(userID, chatID, period)
+}
+
+func (b *Bot) sendStats(userID int64, chatID int64, period stats.Period) {
+	tr := b.tr(userID)
+	summary, err := b.animeService.GetUserStats(userID, period)
+	if err != nil {
+		b.logger.Error("Failed to get stats for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("stats.failed")))
+		return
+	}
+
+	if summary.Titles == 0 {
+		msg := tgbotapi.NewMessage(chatID, tr.T("stats.empty", statsPeriodLabel(tr, period)))
+		msg.ReplyMarkup = b.createStatsKeyboard(tr)
+		b.api.Send(msg)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := stats.RenderPNG(&buf, summary); err != nil {
+		b.logger.Error("Failed to render stats for user %d: %v", userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("stats.failed")))
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  stats.FileName(period),
+		Bytes: buf.Bytes(),
+	})
+	photo.Caption = statsCaption(tr, period, summary)
+	photo.ReplyMarkup = b.createStatsKeyboard(tr)
+	if _, err := b.api.Send(photo); err != nil {
+		b.logger.Error("Failed to send stats to user %d: %v", userID, err)
+	}
+}
+
+func (b *Bot) handleStatsCallback(callback *tgbotapi.CallbackQuery) bool {
+	data := callback.Data
+	if len(data) <= 6 || data[:6] != "stats:" {
+		return false
+	}
+
+	tr := b.tr(callback.From.ID)
+	period, err := stats.ParsePeriod(data[6:], time.Now().In(b.animeService.UserLocation(callback.From.ID)))
+	if err != nil {
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("stats.unknown_period")))
+		return true
+	}
+
+	b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("stats.counting")))
+	b.sendStats(callback.From.ID, callback.Message.Chat.ID, period)
+	return true
+}
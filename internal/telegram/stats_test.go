@@ -0,0 +1,35 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+func TestStatsCaption(t *testing.T) {
+	summary := stats.Summary{
+		Titles:      21,
+		Favorites:   20,
+		Rated:       3,
+		Uncataloged: 2,
+		Genres:      []models.GenreCount{{Name: "Comedy", Russian: "Комедия", Count: 4}},
+	}
+	period := stats.Period{Name: stats.PeriodAll}
+
+	text := statsCaption(ru, period, summary)
+	for _, want := range []string{"за всё время", "21 тайтл (в избранном 20, оценено 3)", "2 тайтла без данных", "Комедия 4"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	text = statsCaption(i18n.For(i18n.English), period, summary)
+	for _, want := range []string{"for all time", "21 titles (favorites: 20, rated: 3)", "2 titles have no shikimori data", "Comedy 4"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package telegram
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+// parseVoteArgs reads "/vote [N] [список|избранное]" in any order
+func parseVoteArgs(args string) (int, service.VoteSource) {
+	n := service.VoteDefaultOptions
+	source := service.VoteSourceAny
+	for _, arg := range strings.Fields(strings.ToLower(args)) {
+		if value, err := strconv.Atoi(arg); err == nil {
+			n = value
+			continue
+		}
+		switch arg {
+		case "список", "list":
+			source = service.VoteSourceWatchlist
+		case "избранное", "fav", "favorites":
+			source = service.VoteSourceFavorites
+		}
+	}
+	return n, source
+}
+
+func voteResultText(tr i18n.Localizer, result *service.VoteResult) string {
+	if result.Winner == nil {
+		return tr.T("vote.no_votes")
+	}
+	return tr.T("vote.winner", result.Winner.Title, tr.N("votes", result.Votes, result.Votes))
+}
+
+func pollCounts(poll tgbotapi.Poll) []int {
+	counts := make([]int, len(poll.Options))
+	for i, option := range poll.Options {
+		counts[i] = option.VoterCount
+	}
+	return counts
+}
+
+func (b *Bot) handleVote(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(chatID)
+	n, source := parseVoteArgs(message.CommandArguments())
+
+	options, err := b.animeService.PrepareVote(chatID, n, source)
+	switch {
+	case errors.Is(err, service.ErrVoteInProgress):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("vote.in_progress")))
+		return
+	case errors.Is(err, service.ErrNotEnoughVoteCandidates):
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("vote.not_enough")))
+		return
+	case err != nil:
+		b.logger.Error("Failed to prepare vote for chat %d: %v", chatID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("vote.start_failed")))
+		return
+	}
+
+	titles := make([]string, len(options))
+	for i, option := range options {
+		titles[i] = option.Title
+	}
+	poll := tgbotapi.NewPoll(chatID, tr.T("vote.question"), titles...)
+	poll.IsAnonymous = false
+	poll.ReplyMarkup = b.createVoteKeyboard(tr)
+
+	sent, err := b.api.Send(poll)
+	if err != nil || sent.Poll == nil {
+		b.logger.Error("Failed to send vote poll to chat %d: %v", chatID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("vote.start_failed")))
+		return
+	}
+
+	if err := b.animeService.StartVote(sent.Poll.ID, chatID, sent.MessageID, message.From.ID, options); err != nil {
+		b.logger.Error("Failed to save vote %s of chat %d: %v", sent.Poll.ID, chatID, err)
+	}
+}
+
+// finishVote announces the winner once, whichever of the stop button and the
+// closed-poll update gets here first
+func (b *Bot) finishVote(poll tgbotapi.Poll) {
+	result, err := b.animeService.FinishVote(poll.ID, pollCounts(poll))
+	if err != nil {
+		b.logger.Error("Failed to finish vote %s: %v", poll.ID, err)
+		return
+	}
+	if result == nil {
+		return
+	}
+
+	chatID := result.Session.ChatID
+	msg := tgbotapi.NewMessage(chatID, voteResultText(b.tr(chatID), result))
+	msg.ReplyToMessageID = result.Session.MessageID
+	b.api.Send(msg)
+
+	if result.Winner == nil {
+		return
+	}
+	anime, err := b.animeService.GetAnimeByID(result.Winner.AnimeID)
+	if err != nil {
+		b.logger.Error("Failed to get anime details: %v", err)
+		return
+	}
+	b.saveState(chatID, &UserState{SearchResults: []models.Anime{*anime}})
+	b.showGroupAnime(chatID)
+}
+
+func (b *Bot) handlePoll(poll *tgbotapi.Poll) {
+	if poll.IsClosed {
+		b.finishVote(*poll)
+	}
+}
+
+// handlePollAnswer counts voters as chat members, so their favorites become
+// candidates for the next vote
+func (b *Bot) handlePollAnswer(answer *tgbotapi.PollAnswer) {
+	session, err := b.animeService.GetVoteSession(answer.PollID)
+	if err != nil || session == nil {
+		return
+	}
+
+	b.animeService.EnsureUserExists(answer.User.ID, chatMemberName(&answer.User))
+	if err := b.animeService.TouchChatMember(session.ChatID, answer.User.ID); err != nil {
+		b.logger.Error("Failed to save member %d of chat %d: %v", answer.User.ID, session.ChatID, err)
+	}
+}
+
+func (b *Bot) handleVoteCallback(callback *tgbotapi.CallbackQuery) bool {
+	if callback.Data != "vote_close" || callback.Message == nil {
+		return false
+	}
+
+	chatID := callback.Message.Chat.ID
+	tr := b.tr(chatID)
+	if callback.Message.Poll == nil {
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	session, err := b.animeService.GetVoteSession(callback.Message.Poll.ID)
+	if err != nil || session == nil || session.ClosedAt != nil {
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("vote.finished")))
+		return true
+	}
+
+	startedBy := session.StartedBy != nil && *session.StartedBy == callback.From.ID
+	if !startedBy && !b.isChatAdmin(chatID, callback.From.ID) {
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("vote.close_forbidden")))
+		return true
+	}
+
+	poll, err := b.api.StopPoll(tgbotapi.NewStopPoll(chatID, session.MessageID))
+	if err != nil {
+		b.logger.Error("Failed to stop poll %s in chat %d: %v", session.PollID, chatID, err)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, tr.T("vote.stop_failed")))
+		return true
+	}
+
+	b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+	b.finishVote(poll)
+	return true
+}
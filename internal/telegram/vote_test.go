@@ -0,0 +1,52 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/service"
+)
+
+func TestParseVoteArgs(t *testing.T) {
+	tests := []struct {
+		args   string
+		n      int
+		source service.VoteSource
+	}{
+		{"", service.VoteDefaultOptions, service.VoteSourceAny},
+		{"6", 6, service.VoteSourceAny},
+		{"Список 3", 3, service.VoteSourceWatchlist},
+		{"5 избранное", 5, service.VoteSourceFavorites},
+		{"много", service.VoteDefaultOptions, service.VoteSourceAny},
+	}
+
+	for _, tt := range tests {
+		n, source := parseVoteArgs(tt.args)
+		if n != tt.n || source != tt.source {
+			t.Errorf("%q: expected (%d, %d), got (%d, %d)", tt.args, tt.n, tt.source, n, source)
+		}
+	}
+}
+
+func TestVoteResultText(t *testing.T) {
+	tests := []struct {
+		votes int
+		want  string
+	}{
+		{1, "🏆 Побеждает «Monster» - 1 голос!"},
+		{3, "🏆 Побеждает «Monster» - 3 голоса!"},
+		{11, "🏆 Побеждает «Monster» - 11 голосов!"},
+		{22, "🏆 Побеждает «Monster» - 22 голоса!"},
+	}
+
+	for _, tt := range tests {
+		result := &service.VoteResult{Winner: &models.VoteOption{AnimeID: 1, Title: "Monster"}, Votes: tt.votes}
+		if got := voteResultText(ru, result); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+
+	if got := voteResultText(ru, &service.VoteResult{}); got == "" {
+		t.Error("expected a message when nobody voted")
+	}
+}
@@ -0,0 +1,202 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/stats"
+)
+
+const (
+	wrappedFirstYear = 2000
+	// stays well under Telegram's limit of 30 messages per second
+	wrappedBroadcastDelay = 50 * time.Millisecond
+)
+
+func wrappedTitle(tr i18n.Localizer, title stats.WrappedTitle) string {
+	if title.Title != "" {
+		return title.Title
+	}
+	return tr.T("anime.number", title.AnimeID)
+}
+
+// wrappedCards skips cards that have nothing to show
+func wrappedCards(tr i18n.Localizer, w stats.Wrapped) []string {
+	cards := []string{tr.T("wrapped.summary", w.Year, w.Added, w.Rated)}
+
+	if len(w.TopRated) > 0 {
+		var sb strings.Builder
+		sb.WriteString(tr.T("wrapped.top", w.Year) + "\n")
+		for i, title := range w.TopRated {
+			fmt.Fprintf(&sb, "\n%d. %s — ⭐ %d", i+1, wrappedTitle(tr, title), title.Score)
+		}
+		cards = append(cards, sb.String())
+	}
+
+	if w.Genre != nil {
+		cards = append(cards, tr.T("wrapped.genre", genreLabel(tr, w.Genre.Name, w.Genre.Russian), tr.N("wrapped.genre_titles", w.Genre.Count, w.Genre.Count)))
+	}
+
+	if w.MonthEvents > 0 {
+		month := tr.T(fmt.Sprintf("month.%d", w.ActiveMonth))
+		cards = append(cards, tr.T("wrapped.month", month, w.MonthEvents))
+	}
+
+	if w.Longest != nil {
+		cards = append(cards, tr.T("wrapped.longest", wrappedTitle(tr, *w.Longest), float64(w.Longest.Minutes)/60))
+	}
+
+	return cards
+}
+
+func (b *Bot) wrappedCardView(tr i18n.Localizer, w stats.Wrapped, index int) (string, tgbotapi.InlineKeyboardMarkup) {
+	cards := wrappedCards(tr, w)
+	index = clampPage(index, len(cards))
+
+	text := fmt.Sprintf("%s\n\n%d/%d", cards[index], index+1, len(cards))
+	return text, b.createWrappedKeyboard(tr, w.Year, index, len(cards))
+}
+
+func parseWrappedYear(arg string, now time.Time) (int, bool) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return now.Year(), true
+	}
+	year, err := strconv.Atoi(arg)
+	if err != nil || year < wrappedFirstYear || year > now.Year() {
+		return 0, false
+	}
+	return year, true
+}
+
+func (b *Bot) handleWrapped(userID int64, chatID int64, args string) {
+	year, ok := parseWrappedYear(args, time.Now().In(b.animeService.UserLocation(userID)))
+	if !ok {
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("wrapped.year_prompt")))
+		return
+	}
+
+	if _, err := b.sendWrapped(userID, chatID, year); err != nil {
+		b.logger.Error("Failed to send wrapped %d to user %d: %v", year, userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(chatID).T("wrapped.failed")))
+	}
+}
+
+// sendWrapped reports false when the user had no activity that year
+func (b *Bot) sendWrapped(userID int64, chatID int64, year int) (bool, error) {
+	wrapped, err := b.animeService.GetWrapped(userID, year)
+	if err != nil {
+		return false, err
+	}
+
+	if wrapped.Empty() {
+		b.api.Send(tgbotapi.NewMessage(chatID, b.tr(userID).T("wrapped.empty", year)))
+		return false, nil
+	}
+
+	text, keyboard := b.wrappedCardView(b.tr(userID), wrapped, 0)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err := b.api.Send(msg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *Bot) sendWrappedImage(userID int64, chatID int64, year int) {
+	tr := b.tr(userID)
+	wrapped, err := b.animeService.GetWrapped(userID, year)
+	if err != nil {
+		b.logger.Error("Failed to get wrapped %d for user %d: %v", year, userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("wrapped.failed")))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := stats.RenderWrappedPNG(&buf, wrapped); err != nil {
+		b.logger.Error("Failed to render wrapped %d for user %d: %v", year, userID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, tr.T("wrapped.failed")))
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  stats.WrappedFileName(year),
+		Bytes: buf.Bytes(),
+	})
+	photo.Caption = tr.T("wrapped.image_caption", year)
+	if _, err := b.api.Send(photo); err != nil {
+		b.logger.Error("Failed to send wrapped image to user %d: %v", userID, err)
+	}
+}
+
+// BroadcastWrapped sends the first recap card to every user active in year who
+// hasn't received it yet, so it is safe to re-run after a partial failure
+func (b *Bot) BroadcastWrapped(year int) (int, error) {
+	userIDs, err := b.animeService.GetWrappedRecipients(year)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, userID := range userIDs {
+		// private chats share the user's id
+		ok, err := b.sendWrapped(userID, userID, year)
+		if err != nil {
+			b.logger.Error("Failed to broadcast wrapped %d to user %d: %v", year, userID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+		if err := b.animeService.MarkWrappedSent(userID, year); err != nil {
+			return sent, err
+		}
+		time.Sleep(wrappedBroadcastDelay)
+	}
+
+	b.logger.Info("Broadcast wrapped %d to %d of %d users", year, sent, len(userIDs))
+	return sent, nil
+}
+
+func (b *Bot) handleWrappedCallback(callback *tgbotapi.CallbackQuery) bool {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	if len(data) > 12 && data[:12] == "wrapped_img:" {
+		year := 0
+		fmt.Sscanf(data, "wrapped_img:%d", &year)
+
+		b.api.Send(tgbotapi.NewCallback(callback.ID, b.tr(userID).T("wrapped.drawing")))
+		b.sendWrappedImage(userID, chatID, year)
+		return true
+	}
+
+	if len(data) > 8 && data[:8] == "wrapped:" {
+		year, index := 0, 0
+		fmt.Sscanf(data, "wrapped:%d:%d", &year, &index)
+
+		wrapped, err := b.animeService.GetWrapped(userID, year)
+		if err != nil || wrapped.Empty() {
+			if err != nil {
+				b.logger.Error("Failed to get wrapped %d for user %d: %v", year, userID, err)
+			}
+			b.api.Send(tgbotapi.NewCallback(callback.ID, b.tr(userID).T("wrapped.unavailable")))
+			return true
+		}
+
+		text, keyboard := b.wrappedCardView(b.tr(userID), wrapped, index)
+		edit := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID, text)
+		edit.ReplyMarkup = &keyboard
+		b.api.Send(edit)
+		b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+		return true
+	}
+
+	return false
+}
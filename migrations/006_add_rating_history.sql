@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rating_history (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    anime_id INTEGER NOT NULL,
+    score INTEGER NOT NULL CHECK (score >= 1 AND score <= 10),
+    rated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_user_anime ON rating_history(user_id, anime_id, rated_at);
+
+INSERT INTO rating_history (user_id, anime_id, score, rated_at)
+SELECT user_id, anime_id, score, rated_at FROM ratings;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_rating_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.score IS DISTINCT FROM OLD.score THEN
+        INSERT INTO rating_history (user_id, anime_id, score, rated_at)
+        VALUES (NEW.user_id, NEW.anime_id, NEW.score, COALESCE(NEW.rated_at, NOW()));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER ratings_history_trigger
+AFTER INSERT OR UPDATE ON ratings
+FOR EACH ROW EXECUTE FUNCTION record_rating_history();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_history_trigger ON ratings;
+DROP FUNCTION IF EXISTS record_rating_history();
+DROP INDEX IF EXISTS idx_rating_history_user_anime;
+DROP TABLE IF EXISTS rating_history;
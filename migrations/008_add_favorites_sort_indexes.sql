@@ -0,0 +1,8 @@
+-- +goose Up
+-- keyset pagination walks favorites in (sort key, anime_id) order
+CREATE INDEX IF NOT EXISTS idx_favorites_user_added ON favorites(user_id, added_at, anime_id);
+CREATE INDEX IF NOT EXISTS idx_favorites_user_title ON favorites(user_id, title, anime_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_favorites_user_title;
+DROP INDEX IF EXISTS idx_favorites_user_added;
@@ -0,0 +1,104 @@
+-- +goose Up
+-- aggregate of bot users' ratings per anime, kept in sync with ratings by triggers
+CREATE TABLE IF NOT EXISTS anime_rating_stats (
+    anime_id INTEGER PRIMARY KEY,
+    votes INTEGER NOT NULL,
+    average DOUBLE PRECISION NOT NULL,
+    score_1 INTEGER NOT NULL DEFAULT 0,
+    score_2 INTEGER NOT NULL DEFAULT 0,
+    score_3 INTEGER NOT NULL DEFAULT 0,
+    score_4 INTEGER NOT NULL DEFAULT 0,
+    score_5 INTEGER NOT NULL DEFAULT 0,
+    score_6 INTEGER NOT NULL DEFAULT 0,
+    score_7 INTEGER NOT NULL DEFAULT 0,
+    score_8 INTEGER NOT NULL DEFAULT 0,
+    score_9 INTEGER NOT NULL DEFAULT 0,
+    score_10 INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_anime_rating_stats_top ON anime_rating_stats(average, votes);
+
+INSERT INTO anime_rating_stats (anime_id, votes, average, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10, updated_at)
+SELECT anime_id, COUNT(*), AVG(score),
+    SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 6 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 7 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 8 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 9 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 10 THEN 1 ELSE 0 END),
+    NOW()
+FROM ratings
+GROUP BY anime_id;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_anime_rating_stats(target INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO anime_rating_stats (anime_id, votes, average, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10, updated_at)
+    SELECT anime_id, COUNT(*), AVG(score),
+        SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 6 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 7 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 8 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 9 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 10 THEN 1 ELSE 0 END),
+        NOW()
+    FROM ratings
+    WHERE anime_id = target
+    GROUP BY anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET
+        votes = EXCLUDED.votes,
+        average = EXCLUDED.average,
+        score_1 = EXCLUDED.score_1,
+        score_2 = EXCLUDED.score_2,
+        score_3 = EXCLUDED.score_3,
+        score_4 = EXCLUDED.score_4,
+        score_5 = EXCLUDED.score_5,
+        score_6 = EXCLUDED.score_6,
+        score_7 = EXCLUDED.score_7,
+        score_8 = EXCLUDED.score_8,
+        score_9 = EXCLUDED.score_9,
+        score_10 = EXCLUDED.score_10,
+        updated_at = EXCLUDED.updated_at;
+
+    DELETE FROM anime_rating_stats
+    WHERE anime_id = target AND NOT EXISTS (SELECT 1 FROM ratings WHERE anime_id = target);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ratings_refresh_stats() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.score = OLD.score AND NEW.anime_id = OLD.anime_id THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_anime_rating_stats(OLD.anime_id);
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.anime_id <> OLD.anime_id) THEN
+        PERFORM refresh_anime_rating_stats(NEW.anime_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER ratings_stats_trigger
+AFTER INSERT OR UPDATE OR DELETE ON ratings
+FOR EACH ROW EXECUTE FUNCTION ratings_refresh_stats();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_stats_trigger ON ratings;
+DROP FUNCTION IF EXISTS ratings_refresh_stats();
+DROP FUNCTION IF EXISTS refresh_anime_rating_stats(INTEGER);
+DROP INDEX IF EXISTS idx_anime_rating_stats_top;
+DROP TABLE IF EXISTS anime_rating_stats;
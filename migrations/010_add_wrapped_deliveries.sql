@@ -0,0 +1,14 @@
+-- +goose Up
+-- one row per delivered year-end recap, so a re-run broadcast skips users who already got it
+CREATE TABLE IF NOT EXISTS wrapped_deliveries (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    sent_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, year)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ratings_user_rated_at ON ratings(user_id, rated_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ratings_user_rated_at;
+DROP TABLE IF EXISTS wrapped_deliveries;
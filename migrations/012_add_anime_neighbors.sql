@@ -0,0 +1,63 @@
+-- +goose Up
+-- item-item neighbours from bot users' ratings, rebuilt by the in-process similarity job
+CREATE TABLE IF NOT EXISTS anime_neighbors (
+    anime_id INTEGER NOT NULL,
+    neighbor_id INTEGER NOT NULL,
+    similarity DOUBLE PRECISION NOT NULL,
+    common_users INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (anime_id, neighbor_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_anime_neighbors_rank ON anime_neighbors(anime_id, similarity);
+CREATE INDEX IF NOT EXISTS idx_anime_neighbors_neighbor ON anime_neighbors(neighbor_id);
+
+-- anime whose ratings changed since the job last ran; version lets the job
+-- clear only what it has seen
+CREATE TABLE IF NOT EXISTS similarity_dirty (
+    anime_id INTEGER PRIMARY KEY,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+INSERT INTO similarity_dirty (anime_id)
+SELECT DISTINCT anime_id FROM ratings
+ON CONFLICT (anime_id) DO NOTHING;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION mark_similarity_dirty(target INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (target, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = similarity_dirty.version + 1;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ratings_mark_similarity_dirty() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.score = OLD.score AND NEW.anime_id = OLD.anime_id THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND NEW.anime_id <> OLD.anime_id) THEN
+        PERFORM mark_similarity_dirty(OLD.anime_id);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        PERFORM mark_similarity_dirty(NEW.anime_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER ratings_similarity_trigger
+AFTER INSERT OR UPDATE OR DELETE ON ratings
+FOR EACH ROW EXECUTE FUNCTION ratings_mark_similarity_dirty();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_similarity_trigger ON ratings;
+DROP FUNCTION IF EXISTS ratings_mark_similarity_dirty();
+DROP FUNCTION IF EXISTS mark_similarity_dirty(INTEGER);
+DROP TABLE IF EXISTS similarity_dirty;
+DROP INDEX IF EXISTS idx_anime_neighbors_neighbor;
+DROP INDEX IF EXISTS idx_anime_neighbors_rank;
+DROP TABLE IF EXISTS anime_neighbors;
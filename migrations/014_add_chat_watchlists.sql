@@ -0,0 +1,19 @@
+-- +goose Up
+-- shared watchlists of group chats; an entry outlives its author's /deleteme,
+-- it just loses the name
+CREATE TABLE IF NOT EXISTS chat_watchlist (
+    chat_id BIGINT NOT NULL,
+    anime_id INTEGER NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    added_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    added_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (chat_id, anime_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_watchlist_added ON chat_watchlist(chat_id, added_at);
+CREATE INDEX IF NOT EXISTS idx_chat_watchlist_added_by ON chat_watchlist(added_by);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chat_watchlist_added_by;
+DROP INDEX IF EXISTS idx_chat_watchlist_added;
+DROP TABLE IF EXISTS chat_watchlist;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- the rest of /settings joins the language in user_settings; users without a
+-- row get these defaults. time_zone is an IANA name, '' is the server's zone.
+-- share_list stays on users next to friend_code: the friends feed and vote
+-- candidate queries filter other users by it through their existing join on
+-- users, and it is toggled in /friends rather than in /settings
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS title_display VARCHAR(16) NOT NULL DEFAULT 'auto';
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS hide_nsfw BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS notify_follows BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS notify_wrapped BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS hide_spoilers BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS page_size INT NOT NULL DEFAULT 10;
+
+-- +goose Down
+ALTER TABLE user_settings DROP COLUMN IF EXISTS page_size;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS hide_spoilers;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS notify_wrapped;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS notify_follows;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS hide_nsfw;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS time_zone;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS title_display;
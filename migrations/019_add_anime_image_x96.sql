@@ -0,0 +1,6 @@
+-- +goose Up
+-- the smallest poster, the last one tried before the placeholder
+ALTER TABLE animes ADD COLUMN IF NOT EXISTS image_x96 TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE animes DROP COLUMN IF EXISTS image_x96;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- a deleted rating moves its user's mean, but the user no longer rates that
+-- anime, so the job cannot reach their other titles through it; mark them here
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ratings_mark_similarity_dirty() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.score = OLD.score AND NEW.anime_id = OLD.anime_id THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND NEW.anime_id <> OLD.anime_id) THEN
+        PERFORM mark_similarity_dirty(OLD.anime_id);
+        INSERT INTO similarity_dirty (anime_id, version)
+        SELECT anime_id, 1 FROM ratings WHERE user_id = OLD.user_id
+        ON CONFLICT (anime_id) DO UPDATE SET version = similarity_dirty.version + 1;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        PERFORM mark_similarity_dirty(NEW.anime_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ratings_mark_similarity_dirty() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.score = OLD.score AND NEW.anime_id = OLD.anime_id THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND NEW.anime_id <> OLD.anime_id) THEN
+        PERFORM mark_similarity_dirty(OLD.anime_id);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        PERFORM mark_similarity_dirty(NEW.anime_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
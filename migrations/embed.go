@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS holds the Postgres migrations at the root and the SQLite ones under sqlite/
+//
+//go:embed *.sql sqlite/*.sql
+var FS embed.FS
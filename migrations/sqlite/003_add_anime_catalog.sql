@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS animes (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(500) NOT NULL DEFAULT '',
+    russian VARCHAR(500) NOT NULL DEFAULT '',
+    kind VARCHAR(50) NOT NULL DEFAULT '',
+    score VARCHAR(10) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL DEFAULT '',
+    episodes INTEGER NOT NULL DEFAULT 0,
+    duration INTEGER NOT NULL DEFAULT 0,
+    aired_on VARCHAR(10) NOT NULL DEFAULT '',
+    released_on VARCHAR(10) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    image_original TEXT NOT NULL DEFAULT '',
+    image_preview TEXT NOT NULL DEFAULT '',
+    fetched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS genres (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    russian VARCHAR(255) NOT NULL DEFAULT '',
+    kind VARCHAR(50) NOT NULL DEFAULT '',
+    entry_type VARCHAR(50) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS anime_genres (
+    anime_id INTEGER NOT NULL REFERENCES animes(id) ON DELETE CASCADE,
+    genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (anime_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_animes_kind ON animes(kind);
+CREATE INDEX IF NOT EXISTS idx_anime_genres_genre_id ON anime_genres(genre_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_anime_genres_genre_id;
+DROP INDEX IF EXISTS idx_animes_kind;
+DROP TABLE IF EXISTS anime_genres;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS animes;
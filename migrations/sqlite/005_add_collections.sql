@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    anime_id INTEGER NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    poster_url TEXT,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, anime_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collections_user_id ON collections(user_id);
+CREATE INDEX IF NOT EXISTS idx_collection_items_anime_id ON collection_items(anime_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_collection_items_anime_id;
+DROP INDEX IF EXISTS idx_collections_user_id;
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rating_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    anime_id INTEGER NOT NULL,
+    score INTEGER NOT NULL CHECK (score >= 1 AND score <= 10),
+    rated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_user_anime ON rating_history(user_id, anime_id, rated_at);
+
+INSERT INTO rating_history (user_id, anime_id, score, rated_at)
+SELECT user_id, anime_id, score, rated_at FROM ratings;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_history_insert
+AFTER INSERT ON ratings
+BEGIN
+    INSERT INTO rating_history (user_id, anime_id, score, rated_at)
+    VALUES (NEW.user_id, NEW.anime_id, NEW.score, COALESCE(NEW.rated_at, CURRENT_TIMESTAMP));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_history_update
+AFTER UPDATE ON ratings
+WHEN NEW.score IS NOT OLD.score
+BEGIN
+    INSERT INTO rating_history (user_id, anime_id, score, rated_at)
+    VALUES (NEW.user_id, NEW.anime_id, NEW.score, COALESCE(NEW.rated_at, CURRENT_TIMESTAMP));
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_history_update;
+DROP TRIGGER IF EXISTS ratings_history_insert;
+DROP INDEX IF EXISTS idx_rating_history_user_anime;
+DROP TABLE IF EXISTS rating_history;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- audit records must not identify the user: only what happened and how much
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action VARCHAR(50) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    items_deleted INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,158 @@
+-- +goose Up
+-- aggregate of bot users' ratings per anime, kept in sync with ratings by triggers
+CREATE TABLE IF NOT EXISTS anime_rating_stats (
+    anime_id INTEGER PRIMARY KEY,
+    votes INTEGER NOT NULL,
+    average DOUBLE PRECISION NOT NULL,
+    score_1 INTEGER NOT NULL DEFAULT 0,
+    score_2 INTEGER NOT NULL DEFAULT 0,
+    score_3 INTEGER NOT NULL DEFAULT 0,
+    score_4 INTEGER NOT NULL DEFAULT 0,
+    score_5 INTEGER NOT NULL DEFAULT 0,
+    score_6 INTEGER NOT NULL DEFAULT 0,
+    score_7 INTEGER NOT NULL DEFAULT 0,
+    score_8 INTEGER NOT NULL DEFAULT 0,
+    score_9 INTEGER NOT NULL DEFAULT 0,
+    score_10 INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_anime_rating_stats_top ON anime_rating_stats(average, votes);
+
+INSERT INTO anime_rating_stats (anime_id, votes, average, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10, updated_at)
+SELECT anime_id, COUNT(*), AVG(score),
+    SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 6 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 7 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 8 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 9 THEN 1 ELSE 0 END),
+    SUM(CASE WHEN score = 10 THEN 1 ELSE 0 END),
+    CURRENT_TIMESTAMP
+FROM ratings
+GROUP BY anime_id;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_stats_insert
+AFTER INSERT ON ratings
+BEGIN
+    INSERT INTO anime_rating_stats (anime_id, votes, average, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10, updated_at)
+    SELECT anime_id, COUNT(*), AVG(score),
+        SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 6 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 7 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 8 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 9 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 10 THEN 1 ELSE 0 END),
+        CURRENT_TIMESTAMP
+    FROM ratings
+    WHERE anime_id = NEW.anime_id
+    GROUP BY anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET
+        votes = EXCLUDED.votes,
+        average = EXCLUDED.average,
+        score_1 = EXCLUDED.score_1,
+        score_2 = EXCLUDED.score_2,
+        score_3 = EXCLUDED.score_3,
+        score_4 = EXCLUDED.score_4,
+        score_5 = EXCLUDED.score_5,
+        score_6 = EXCLUDED.score_6,
+        score_7 = EXCLUDED.score_7,
+        score_8 = EXCLUDED.score_8,
+        score_9 = EXCLUDED.score_9,
+        score_10 = EXCLUDED.score_10,
+        updated_at = EXCLUDED.updated_at;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_stats_update
+AFTER UPDATE ON ratings
+WHEN NEW.score IS NOT OLD.score
+BEGIN
+    INSERT INTO anime_rating_stats (anime_id, votes, average, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10, updated_at)
+    SELECT anime_id, COUNT(*), AVG(score),
+        SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 6 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 7 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 8 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 9 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 10 THEN 1 ELSE 0 END),
+        CURRENT_TIMESTAMP
+    FROM ratings
+    WHERE anime_id = NEW.anime_id
+    GROUP BY anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET
+        votes = EXCLUDED.votes,
+        average = EXCLUDED.average,
+        score_1 = EXCLUDED.score_1,
+        score_2 = EXCLUDED.score_2,
+        score_3 = EXCLUDED.score_3,
+        score_4 = EXCLUDED.score_4,
+        score_5 = EXCLUDED.score_5,
+        score_6 = EXCLUDED.score_6,
+        score_7 = EXCLUDED.score_7,
+        score_8 = EXCLUDED.score_8,
+        score_9 = EXCLUDED.score_9,
+        score_10 = EXCLUDED.score_10,
+        updated_at = EXCLUDED.updated_at;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_stats_delete
+AFTER DELETE ON ratings
+BEGIN
+    INSERT INTO anime_rating_stats (anime_id, votes, average, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10, updated_at)
+    SELECT anime_id, COUNT(*), AVG(score),
+        SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 6 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 7 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 8 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 9 THEN 1 ELSE 0 END),
+        SUM(CASE WHEN score = 10 THEN 1 ELSE 0 END),
+        CURRENT_TIMESTAMP
+    FROM ratings
+    WHERE anime_id = OLD.anime_id
+    GROUP BY anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET
+        votes = EXCLUDED.votes,
+        average = EXCLUDED.average,
+        score_1 = EXCLUDED.score_1,
+        score_2 = EXCLUDED.score_2,
+        score_3 = EXCLUDED.score_3,
+        score_4 = EXCLUDED.score_4,
+        score_5 = EXCLUDED.score_5,
+        score_6 = EXCLUDED.score_6,
+        score_7 = EXCLUDED.score_7,
+        score_8 = EXCLUDED.score_8,
+        score_9 = EXCLUDED.score_9,
+        score_10 = EXCLUDED.score_10,
+        updated_at = EXCLUDED.updated_at;
+
+    DELETE FROM anime_rating_stats
+    WHERE anime_id = OLD.anime_id AND NOT EXISTS (SELECT 1 FROM ratings WHERE anime_id = OLD.anime_id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_stats_delete;
+DROP TRIGGER IF EXISTS ratings_stats_update;
+DROP TRIGGER IF EXISTS ratings_stats_insert;
+DROP INDEX IF EXISTS idx_anime_rating_stats_top;
+DROP TABLE IF EXISTS anime_rating_stats;
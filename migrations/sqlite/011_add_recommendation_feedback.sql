@@ -0,0 +1,12 @@
+-- +goose Up
+-- 👍/👎 on /recommend picks; reacted titles are not suggested again
+CREATE TABLE IF NOT EXISTS recommendation_feedback (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    anime_id INTEGER NOT NULL,
+    liked BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, anime_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS recommendation_feedback;
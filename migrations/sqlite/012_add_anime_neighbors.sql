@@ -0,0 +1,64 @@
+-- +goose Up
+-- item-item neighbours from bot users' ratings, rebuilt by the in-process similarity job
+CREATE TABLE IF NOT EXISTS anime_neighbors (
+    anime_id INTEGER NOT NULL,
+    neighbor_id INTEGER NOT NULL,
+    similarity DOUBLE PRECISION NOT NULL,
+    common_users INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (anime_id, neighbor_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_anime_neighbors_rank ON anime_neighbors(anime_id, similarity);
+CREATE INDEX IF NOT EXISTS idx_anime_neighbors_neighbor ON anime_neighbors(neighbor_id);
+
+-- anime whose ratings changed since the job last ran; version lets the job
+-- clear only what it has seen
+CREATE TABLE IF NOT EXISTS similarity_dirty (
+    anime_id INTEGER PRIMARY KEY,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+INSERT INTO similarity_dirty (anime_id)
+SELECT DISTINCT anime_id FROM ratings WHERE true
+ON CONFLICT (anime_id) DO NOTHING;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_similarity_insert
+AFTER INSERT ON ratings
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (NEW.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_similarity_update
+AFTER UPDATE ON ratings
+WHEN NEW.score IS NOT OLD.score OR NEW.anime_id IS NOT OLD.anime_id
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version)
+    SELECT OLD.anime_id, 1 WHERE OLD.anime_id IS NOT NEW.anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (NEW.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS ratings_similarity_delete
+AFTER DELETE ON ratings
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (OLD.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_similarity_delete;
+DROP TRIGGER IF EXISTS ratings_similarity_update;
+DROP TRIGGER IF EXISTS ratings_similarity_insert;
+DROP TABLE IF EXISTS similarity_dirty;
+DROP INDEX IF EXISTS idx_anime_neighbors_neighbor;
+DROP INDEX IF EXISTS idx_anime_neighbors_rank;
+DROP TABLE IF EXISTS anime_neighbors;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- friend_code is handed out lazily by /friends; share_list hides a user's
+-- favorites and ratings from everyone who follows them
+ALTER TABLE users ADD COLUMN friend_code VARCHAR(16);
+ALTER TABLE users ADD COLUMN share_list BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_friend_code ON users(friend_code);
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee ON follows(followee_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_follows_followee;
+DROP TABLE IF EXISTS follows;
+DROP INDEX IF EXISTS idx_users_friend_code;
+ALTER TABLE users DROP COLUMN share_list;
+ALTER TABLE users DROP COLUMN friend_code;
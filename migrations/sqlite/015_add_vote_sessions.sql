@@ -0,0 +1,40 @@
+-- +goose Up
+-- group members the bot has seen, so /vote can draw on their favorites
+CREATE TABLE IF NOT EXISTS chat_members (
+    chat_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_members_user ON chat_members(user_id);
+
+-- native polls posted by /vote; closed_at is set exactly once, when the winner is announced
+CREATE TABLE IF NOT EXISTS vote_sessions (
+    poll_id VARCHAR(64) PRIMARY KEY,
+    chat_id INTEGER NOT NULL,
+    message_id INTEGER NOT NULL,
+    started_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP,
+    winner_anime_id INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_vote_sessions_chat ON vote_sessions(chat_id, closed_at);
+CREATE INDEX IF NOT EXISTS idx_vote_sessions_started_by ON vote_sessions(started_by);
+
+CREATE TABLE IF NOT EXISTS vote_options (
+    poll_id VARCHAR(64) NOT NULL REFERENCES vote_sessions(poll_id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    anime_id INTEGER NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    PRIMARY KEY (poll_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS vote_options;
+DROP INDEX IF EXISTS idx_vote_sessions_started_by;
+DROP INDEX IF EXISTS idx_vote_sessions_chat;
+DROP TABLE IF EXISTS vote_sessions;
+DROP INDEX IF EXISTS idx_chat_members_user;
+DROP TABLE IF EXISTS chat_members;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- per-user preferences, one row per user who changed anything. language
+-- overrides the locale detected from Telegram; NULL follows Telegram
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    language VARCHAR(8),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_settings;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- telegram file_ids of posters the bot already uploaded, per shikimori image size;
+-- a row is stale once the anime's poster path differs from the one it was uploaded from
+CREATE TABLE IF NOT EXISTS poster_files (
+    anime_id INTEGER NOT NULL,
+    size VARCHAR(16) NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    file_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (anime_id, size)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS poster_files;
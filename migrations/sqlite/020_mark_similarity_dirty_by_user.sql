@@ -0,0 +1,59 @@
+-- +goose Up
+-- a deleted rating moves its user's mean, but the user no longer rates that
+-- anime, so the job cannot reach their other titles through it; mark them here
+DROP TRIGGER IF EXISTS ratings_similarity_update;
+DROP TRIGGER IF EXISTS ratings_similarity_delete;
+
+-- +goose StatementBegin
+CREATE TRIGGER ratings_similarity_update
+AFTER UPDATE ON ratings
+WHEN NEW.score IS NOT OLD.score OR NEW.anime_id IS NOT OLD.anime_id
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version)
+    SELECT OLD.anime_id, 1 WHERE OLD.anime_id IS NOT NEW.anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+    INSERT INTO similarity_dirty (anime_id, version)
+    SELECT anime_id, 1 FROM ratings WHERE user_id = OLD.user_id AND OLD.anime_id IS NOT NEW.anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (NEW.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER ratings_similarity_delete
+AFTER DELETE ON ratings
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (OLD.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+    INSERT INTO similarity_dirty (anime_id, version)
+    SELECT anime_id, 1 FROM ratings WHERE user_id = OLD.user_id
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS ratings_similarity_update;
+DROP TRIGGER IF EXISTS ratings_similarity_delete;
+
+-- +goose StatementBegin
+CREATE TRIGGER ratings_similarity_update
+AFTER UPDATE ON ratings
+WHEN NEW.score IS NOT OLD.score OR NEW.anime_id IS NOT OLD.anime_id
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version)
+    SELECT OLD.anime_id, 1 WHERE OLD.anime_id IS NOT NEW.anime_id
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (NEW.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER ratings_similarity_delete
+AFTER DELETE ON ratings
+BEGIN
+    INSERT INTO similarity_dirty (anime_id, version) VALUES (OLD.anime_id, 1)
+    ON CONFLICT (anime_id) DO UPDATE SET version = version + 1;
+END;
+-- +goose StatementEnd
@@ -3,11 +3,18 @@ package utils
 import (
 	"fmt"
 	"strings"
+	"unicode/utf16"
 	"unicode/utf8"
 
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
 )
 
+// Telegram limit for photo captions
+const CaptionMaxLength = 1024
+
+const noteDisplayLength = 300
+
 func TruncateTextWithEllipsis(text string, maxLength int) string {
 	if len(text) > maxLength {
 		return text[:maxLength-3] + "..."
@@ -15,6 +22,57 @@ func TruncateTextWithEllipsis(text string, maxLength int) string {
 	return text
 }
 
+func TruncateRunes(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+	if maxRunes <= 3 {
+		if maxRunes < 0 {
+			maxRunes = 0
+		}
+		return string(runes[:maxRunes])
+	}
+	// never leave a dangling escape character before the ellipsis
+	cut := strings.TrimRight(string(runes[:maxRunes-3]), "\\")
+	return cut + "..."
+}
+
+// UTF16Length counts text the way Telegram measures message and caption limits
+func UTF16Length(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// TruncateUTF16 is TruncateRunes with the limit in UTF-16 code units, so emoji
+// and other astral characters count as two
+func TruncateUTF16(text string, maxUnits int) string {
+	if UTF16Length(text) <= maxUnits {
+		return text
+	}
+	if maxUnits < 0 {
+		maxUnits = 0
+	}
+	limit := maxUnits - 3
+	if maxUnits <= 3 {
+		limit = maxUnits
+	}
+
+	units := 0
+	end := 0
+	for i, r := range text {
+		units += utf16.RuneLen(r)
+		if units > limit {
+			break
+		}
+		end = i + utf8.RuneLen(r)
+	}
+	if maxUnits <= 3 {
+		return text[:end]
+	}
+	// never leave a dangling escape character before the ellipsis
+	return strings.TrimRight(text[:end], "\\") + "..."
+}
+
 func SanitizeUTF8(text string) string {
 	if utf8.ValidString(text) {
 		return text
@@ -45,6 +103,15 @@ func EscapeMarkdown(text string) string {
 	return strings.TrimSpace(result)
 }
 
+func EscapeMarkdownText(text string) string {
+	return strings.NewReplacer(
+		"*", "\\*",
+		"_", "\\_",
+		"`", "\\`",
+		"[", "\\[",
+	).Replace(text)
+}
+
 func removeJapaneseCharacters(text string) string {
 	validRunes := []rune{}
 	for _, r := range text {
@@ -77,9 +144,9 @@ func removeBracketContent(text string) string {
 	return result
 }
 
-func FormatGenres(genres []models.Genre) string {
+func FormatGenres(tr i18n.Localizer, genres []models.Genre) string {
 	if len(genres) == 0 {
-		return "нет"
+		return tr.T("card.none")
 	}
 
 	maxGenres := 4
@@ -90,7 +157,7 @@ func FormatGenres(genres []models.Genre) string {
 	genreNames := make([]string, 0, maxGenres)
 	for i := 0; i < maxGenres; i++ {
 		genreName := genres[i].Russian
-		if genreName == "" {
+		if genreName == "" || tr.Lang() != i18n.Russian {
 			genreName = genres[i].Name
 		}
 		genreNames = append(genreNames, genreName)
@@ -99,86 +166,194 @@ func FormatGenres(genres []models.Genre) string {
 	return strings.Join(genreNames, ", ")
 }
 
-func FormatAnimeMessage(anime *models.Anime, isFav bool) string {
+// AnimeTitle picks a single title following the user's title setting; auto
+// and both go by the language
+func AnimeTitle(tr i18n.Localizer, settings models.UserSettings, anime *models.Anime) string {
+	russian := settings.TitleDisplay == models.TitleDisplayRussian ||
+		(settings.TitleDisplay != models.TitleDisplayRomaji && tr.Lang() == i18n.Russian)
+	if russian && anime.Russian != "" {
+		return anime.Russian
+	}
+	if anime.Name == "" {
+		return anime.Russian
+	}
+	return anime.Name
+}
+
+// formatTitle is the card heading; auto shows both titles to Russian readers
+func formatTitle(tr i18n.Localizer, settings models.UserSettings, anime *models.Anime) string {
+	both := settings.TitleDisplay == models.TitleDisplayBoth
+	if settings.TitleDisplay == "" || settings.TitleDisplay == models.TitleDisplayAuto {
+		both = tr.Lang() == i18n.Russian
+	}
+	if !both {
+		return "🎬 " + EscapeMarkdown(AnimeTitle(tr, settings, anime)) + "\n"
+	}
+	return fmt.Sprintf("🎬 %s\n%s\n", EscapeMarkdown(anime.Name), EscapeMarkdown(anime.Russian))
+}
+
+func FormatAnimeMessage(tr i18n.Localizer, settings models.UserSettings, anime *models.Anime, isFav bool) string {
 	description := TruncateTextWithEllipsis(anime.Description, 800)
 	description = SanitizeUTF8(description)
 	description = EscapeMarkdown(description)
 	descText := description
 	if len(descText) == 0 {
-		descText = "нет"
+		descText = tr.T("card.none")
+	} else if settings.HideSpoilers {
+		descText = tr.T("card.spoiler_hidden")
 	}
 
-	name := EscapeMarkdown(anime.Name)
-	russian := EscapeMarkdown(anime.Russian)
 	kind := EscapeMarkdown(anime.Kind)
 	score := EscapeMarkdown(anime.Score)
 	status := EscapeMarkdown(anime.Status)
-	genres := EscapeMarkdown(FormatGenres(anime.Genres))
-
-	text := fmt.Sprintf(
-		"🎬 %s\n%s\n\n"+
-			"📺 Тип: %s\n"+
-			"🎭 Жанр: %s\n"+
-			"⭐ Оценка: %s\n"+
-			"📊 Статус: %s\n"+
-			"📺 Эпизодов: %d\n\n"+
-			"Описание: %s",
-		name,
-		russian,
-		kind,
-		genres,
-		score,
-		status,
-		anime.Episodes,
-		descText,
-	)
+	genres := EscapeMarkdown(FormatGenres(tr, anime.Genres))
+
+	text := formatTitle(tr, settings, anime) + "\n" +
+		tr.T("card.type", kind) + "\n" +
+		tr.T("card.genres", genres) + "\n" +
+		tr.T("card.score", score) + "\n" +
+		tr.T("card.status", status) + "\n" +
+		tr.T("card.episodes", anime.Episodes) + "\n\n" +
+		tr.T("card.description") + descText
 	if isFav {
-		text += "\n\n💚 В избранном"
+		text += "\n\n" + tr.T("card.in_favorites")
 	}
 	return text
 }
 
-func FormatAnimeMessageWithRating(anime *models.Anime, isFav bool, userRating *models.Rating) string {
+func FormatCommunityRating(tr i18n.Localizer, community *models.CommunityRating) string {
+	if community == nil || community.Votes == 0 {
+		return ""
+	}
+	return tr.T("card.community", community.Average, community.Votes)
+}
+
+// FormatFriendRatings shows how followed users rated an anime
+func FormatFriendRatings(tr i18n.Localizer, friends []models.FriendRating) string {
+	if len(friends) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(friends))
+	for _, friend := range friends {
+		parts = append(parts, fmt.Sprintf("%s %d", EscapeMarkdownText(SanitizeUTF8(FriendName(friend.Username, friend.UserID))), friend.Score))
+	}
+	return tr.T("card.friends") + strings.Join(parts, ", ")
+}
+
+// FriendName falls back to the id for users who never had a username
+func FriendName(username string, userID int64) string {
+	if username == "" {
+		return fmt.Sprintf("id%d", userID)
+	}
+	return username
+}
+
+// FormatAlsoLiked lists titles that bot users who liked an anime also rated highly
+func FormatAlsoLiked(tr i18n.Localizer, neighbors []models.AnimeNeighbor) string {
+	titles := make([]string, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		if neighbor.Title == "" {
+			continue
+		}
+		titles = append(titles, "«"+EscapeMarkdownText(SanitizeUTF8(neighbor.Title))+"»")
+	}
+	if len(titles) == 0 {
+		return ""
+	}
+	return tr.T("card.also_liked") + strings.Join(titles, ", ")
+}
+
+func FormatAnimeMessageWithRating(tr i18n.Localizer, settings models.UserSettings, anime *models.Anime, isFav bool, userRating *models.Rating, note *models.Note, community *models.CommunityRating, alsoLiked []models.AnimeNeighbor, friends []models.FriendRating) string {
 	description := TruncateTextWithEllipsis(anime.Description, 750)
 	description = SanitizeUTF8(description)
 	description = EscapeMarkdown(description)
-	descText := description
-	if len(descText) == 0 {
-		descText = "нет"
-	}
 
-	name := EscapeMarkdown(anime.Name)
-	russian := EscapeMarkdown(anime.Russian)
 	kind := EscapeMarkdown(anime.Kind)
 	score := EscapeMarkdown(anime.Score)
 	status := EscapeMarkdown(anime.Status)
-	genres := EscapeMarkdown(FormatGenres(anime.Genres))
-
-	text := fmt.Sprintf(
-		"🎬 %s\n%s\n\n"+
-			"📺 Тип: %s\n"+
-			"🎭 Жанр: %s\n"+
-			"⭐ Общая оценка: %s\n"+
-			"📊 Статус: %s\n"+
-			"📺 Эпизодов: %d\n\n"+
-			"Описание: %s",
-		name,
-		russian,
-		kind,
-		genres,
-		score,
-		status,
-		anime.Episodes,
-		descText,
-	)
+	genres := EscapeMarkdown(FormatGenres(tr, anime.Genres))
 
+	socialLines := ""
+	if line := FormatCommunityRating(tr, community); line != "" {
+		socialLines = line + "\n"
+	}
+	if line := FormatFriendRatings(tr, friends); line != "" {
+		socialLines += line + "\n"
+	}
+
+	header := formatTitle(tr, settings, anime) + "\n" +
+		tr.T("card.type", kind) + "\n" +
+		tr.T("card.genres", genres) + "\n" +
+		tr.T("card.shikimori_score", score) + "\n" +
+		socialLines +
+		tr.T("card.status", status) + "\n" +
+		tr.T("card.episodes", anime.Episodes) + "\n\n" +
+		tr.T("card.description")
+
+	rating := ""
 	if userRating != nil {
-		text += fmt.Sprintf("\n\n⭐ Твоя оценка: %d", userRating.Score)
+		rating += "\n\n" + tr.T("card.your_rating", userRating.Score)
 	}
 
 	if isFav {
-		text += "\n💚 В избранном"
+		rating += "\n" + tr.T("card.in_favorites")
 	}
 
-	return text
+	noteLine := ""
+	if note != nil && note.Text != "" {
+		noteText := TruncateRunes(SanitizeUTF8(note.Text), noteDisplayLength)
+		if rating == "" {
+			noteLine += "\n"
+		}
+		noteLine += "\n" + tr.T("card.note") + EscapeMarkdownText(noteText)
+	}
+
+	alsoLikedLine := ""
+	if line := FormatAlsoLiked(tr, alsoLiked); line != "" {
+		alsoLikedLine = "\n\n" + line
+	}
+
+	// a long title and genre list can leave no room even for the placeholder
+	// description; the recommendations go first, then the note
+	fallback := UTF16Length(tr.T("card.spoiler_hidden"))
+	if n := UTF16Length(tr.T("card.none")); n > fallback {
+		fallback = n
+	}
+	fits := func() bool {
+		return UTF16Length(header)+UTF16Length(rating+noteLine+alsoLikedLine)+fallback <= CaptionMaxLength
+	}
+	if !fits() {
+		alsoLikedLine = ""
+	}
+	if !fits() {
+		noteLine = ""
+	}
+	footer := rating + noteLine + alsoLikedLine
+	if !fits() {
+		header = TruncateUTF16(header, CaptionMaxLength-UTF16Length(footer)-fallback)
+	}
+
+	// the description gets whatever is left of the caption limit
+	budget := CaptionMaxLength - UTF16Length(header) - UTF16Length(footer)
+	description = TruncateUTF16(description, budget)
+	if len(description) == 0 {
+		description = tr.T("card.none")
+	} else if settings.HideSpoilers {
+		description = tr.T("card.spoiler_hidden")
+	}
+
+	return header + description + footer
+}
+
+func FormatRatingHistory(tr i18n.Localizer, history []models.RatingChange) string {
+	if len(history) < 2 {
+		return ""
+	}
+
+	scores := make([]string, 0, len(history))
+	for _, change := range history {
+		scores = append(scores, fmt.Sprintf("%d", change.Score))
+	}
+
+	return tr.T("card.rating_history") + strings.Join(scores, " → ")
 }
@@ -3,10 +3,17 @@ package utils
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/wywyy3cee/tgbot-anime-tracker/internal/i18n"
 	"github.com/wywyy3cee/tgbot-anime-tracker/internal/models"
 )
 
+var (
+	ru       = i18n.For(i18n.Russian)
+	defaults = models.UserSettings{TitleDisplay: models.TitleDisplayAuto}
+)
+
 func TestTruncateTextWithEllipsis_LongText(t *testing.T) {
 	text := "This is a very long text that needs to be truncated because it exceeds the maximum length"
 	maxLength := 20
@@ -197,7 +204,7 @@ func TestFormatAnimeMessage_BasicAnime(t *testing.T) {
 		Description: "A psychological thriller about a notebook that can kill",
 	}
 
-	result := FormatAnimeMessage(anime, false)
+	result := FormatAnimeMessage(ru, defaults, anime, false)
 
 	if !strings.Contains(result, "Death Note") {
 		t.Error("expected anime name in result")
@@ -227,7 +234,7 @@ func TestFormatAnimeMessage_WithFavoriteFlag(t *testing.T) {
 		Episodes: 220,
 	}
 
-	result := FormatAnimeMessage(anime, true)
+	result := FormatAnimeMessage(ru, defaults, anime, true)
 
 	if !strings.Contains(result, "💚") {
 		t.Error("expected favorite indicator when isFav=true")
@@ -250,7 +257,7 @@ func TestFormatAnimeMessage_EmptyDescription(t *testing.T) {
 		Description: "",
 	}
 
-	result := FormatAnimeMessage(anime, false)
+	result := FormatAnimeMessage(ru, defaults, anime, false)
 
 	if !strings.Contains(result, "нет") {
 		t.Error("expected 'нет' as default description text")
@@ -274,7 +281,7 @@ func TestFormatAnimeMessage_LongDescription(t *testing.T) {
 		Description: longDesc,
 	}
 
-	result := FormatAnimeMessage(anime, false)
+	result := FormatAnimeMessage(ru, defaults, anime, false)
 
 	if !strings.Contains(result, "...") {
 		t.Logf("expected ellipsis in long description, got: %s...", result[len(result)-50:])
@@ -297,7 +304,7 @@ func TestFormatAnimeMessage_SpecialCharactersInFields(t *testing.T) {
 		Description: "Normal description",
 	}
 
-	result := FormatAnimeMessage(anime, false)
+	result := FormatAnimeMessage(ru, defaults, anime, false)
 
 	if !strings.Contains(result, "Test") {
 		t.Error("expected anime name to be processed")
@@ -316,7 +323,7 @@ func TestFormatAnimeMessage_AllFieldsPresent(t *testing.T) {
 		Description: "A complete description",
 	}
 
-	result := FormatAnimeMessage(anime, false)
+	result := FormatAnimeMessage(ru, defaults, anime, false)
 
 	if !strings.Contains(result, "Complete Anime") {
 		t.Error("expected anime name in result")
@@ -375,7 +382,7 @@ func TestEscapeMarkdownAndRemoval_Combined(t *testing.T) {
 
 func TestFormatGenres_Empty(t *testing.T) {
 	genres := []models.Genre{}
-	result := FormatGenres(genres)
+	result := FormatGenres(ru, genres)
 
 	if result != "нет" {
 		t.Errorf("expected 'нет' for empty genres, got '%s'", result)
@@ -388,7 +395,7 @@ func TestFormatGenres_LessThanMax(t *testing.T) {
 		{Russian: "Фэнтези", Name: "Fantasy"},
 	}
 
-	result := FormatGenres(genres)
+	result := FormatGenres(ru, genres)
 
 	expected := "Драма, Фэнтези"
 	if result != expected {
@@ -408,7 +415,7 @@ func TestFormatAnimeMessageWithRating_WithoutRating(t *testing.T) {
 		Episodes: 12,
 	}
 
-	result := FormatAnimeMessageWithRating(anime, false, nil)
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, false, nil, nil, nil, nil, nil)
 
 	if !strings.Contains(result, "🎬") {
 		t.Error("expected emoji in result")
@@ -418,3 +425,221 @@ func TestFormatAnimeMessageWithRating_WithoutRating(t *testing.T) {
 		t.Error("should not contain user rating when nil")
 	}
 }
+
+func TestFormatAnimeMessageWithRating_WithNote(t *testing.T) {
+	anime := &models.Anime{ID: 1, Name: "Test", Description: "desc"}
+	note := &models.Note{Text: "stopped at ep_5 *dub* is bad [ru]"}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, true, &models.Rating{Score: 7}, note, nil, nil, nil)
+
+	if !strings.Contains(result, "📝 Заметка: stopped at ep\\_5 \\*dub\\* is bad \\[ru]") {
+		t.Errorf("expected escaped note in result, got %q", result)
+	}
+}
+
+func TestFormatAnimeMessageWithRating_CaptionBudget(t *testing.T) {
+	anime := &models.Anime{
+		ID:          1,
+		Name:        "Test",
+		Description: strings.Repeat("описание ", 200),
+	}
+	note := &models.Note{Text: strings.Repeat("заметка ", 100)}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, true, &models.Rating{Score: 9}, note, nil, nil, nil)
+
+	if n := utf8.RuneCountInString(result); n > 1024 {
+		t.Errorf("expected caption to fit 1024 characters, got %d", n)
+	}
+	if !strings.Contains(result, "📝 Заметка:") || !strings.Contains(result, "💚 В избранном") {
+		t.Error("expected footer to survive truncation")
+	}
+}
+
+func TestFormatAnimeMessageWithRating_CaptionBudgetUTF16(t *testing.T) {
+	anime := &models.Anime{
+		ID:          1,
+		Name:        "Test",
+		Description: strings.Repeat("🌸", 700),
+	}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, true, &models.Rating{Score: 9}, nil, nil, nil, nil)
+	if n := UTF16Length(result); n > CaptionMaxLength {
+		t.Errorf("expected caption to fit %d UTF-16 units, got %d", CaptionMaxLength, n)
+	}
+}
+
+func TestFormatAnimeMessageWithRating_HeaderOverflow(t *testing.T) {
+	anime := &models.Anime{ID: 1, Name: "Test", Russian: strings.Repeat("очень длинное название ", 30), Description: "desc"}
+	note := &models.Note{Text: strings.Repeat("заметка ", 40)}
+	alsoLiked := []models.AnimeNeighbor{{NeighborID: 2, Title: "Другое"}}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, true, &models.Rating{Score: 9}, note, nil, alsoLiked, nil)
+	if n := UTF16Length(result); n > CaptionMaxLength {
+		t.Errorf("expected caption to fit %d UTF-16 units, got %d", CaptionMaxLength, n)
+	}
+	if !strings.Contains(result, "💚 В избранном") {
+		t.Error("expected the rating footer to survive")
+	}
+	if strings.Contains(result, "📝 Заметка:") {
+		t.Error("expected the note to make room for the header")
+	}
+}
+
+func TestTruncateUTF16(t *testing.T) {
+	if got := TruncateUTF16("🌸🌸", 4); got != "🌸🌸" {
+		t.Errorf("expected text that fits unchanged, got %q", got)
+	}
+	if got := TruncateUTF16("🌸🌸🌸🌸", 7); got != "🌸🌸..." {
+		t.Errorf("unexpected truncation: %q", got)
+	}
+	if got := TruncateUTF16("ab\\*cdef", 6); got != "ab..." {
+		t.Errorf("expected dangling escape to be dropped, got %q", got)
+	}
+	if got := UTF16Length("а🌸"); got != 3 {
+		t.Errorf("expected 3 units, got %d", got)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := TruncateRunes("привет", 10); got != "привет" {
+		t.Errorf("expected short text unchanged, got %q", got)
+	}
+	if got := TruncateRunes("приветствую", 7); got != "прив..." {
+		t.Errorf("unexpected truncation: %q", got)
+	}
+	if got := TruncateRunes("ab\\*cdef", 6); got != "ab..." {
+		t.Errorf("expected dangling escape to be dropped, got %q", got)
+	}
+}
+
+func TestFormatRatingHistory(t *testing.T) {
+	if got := FormatRatingHistory(ru, []models.RatingChange{{Score: 8}}); got != "" {
+		t.Errorf("expected no history line for a single rating, got %q", got)
+	}
+
+	history := []models.RatingChange{{Score: 7}, {Score: 8}, {Score: 10}}
+	if got := FormatRatingHistory(ru, history); got != "📈 История оценок: 7 → 8 → 10" {
+		t.Errorf("unexpected history line: %q", got)
+	}
+}
+
+func TestFormatAnimeMessageWithRating_Community(t *testing.T) {
+	anime := &models.Anime{ID: 1, Name: "Test", Score: "8.5"}
+	community := &models.CommunityRating{AnimeID: 1, Votes: 23, Average: 8.123}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, false, nil, nil, community, nil, nil)
+	if !strings.Contains(result, "⭐ Общая оценка: 8.5\n👥 Оценка пользователей бота: 8.1 (23)\n") {
+		t.Errorf("expected community line after the Shikimori score, got %q", result)
+	}
+
+	result = FormatAnimeMessageWithRating(ru, defaults, anime, false, nil, nil, &models.CommunityRating{}, nil, nil)
+	if strings.Contains(result, "пользователей бота") {
+		t.Error("community line should be hidden without votes")
+	}
+}
+
+func TestFormatAnimeMessageWithRating_AlsoLiked(t *testing.T) {
+	anime := &models.Anime{ID: 1, Name: "Test", Description: strings.Repeat("о", 2000)}
+	alsoLiked := []models.AnimeNeighbor{
+		{NeighborID: 2, Title: "Fate/Zero"},
+		{NeighborID: 3},
+		{NeighborID: 4, Title: "Re_Zero"},
+	}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, false, nil, nil, nil, alsoLiked, nil)
+	if !strings.HasSuffix(result, "\n\n👥 Кому понравилось это, также понравились: «Fate/Zero», «Re\\_Zero»") {
+		t.Errorf("expected also liked line at the end, got %q", result)
+	}
+	if utf8.RuneCountInString(result) > CaptionMaxLength {
+		t.Errorf("expected the description to make room, got %d runes", utf8.RuneCountInString(result))
+	}
+
+	if FormatAlsoLiked(ru, []models.AnimeNeighbor{{NeighborID: 3}}) != "" {
+		t.Error("expected no line without titles")
+	}
+}
+
+func TestFormatAnimeMessageWithRating_Friends(t *testing.T) {
+	anime := &models.Anime{ID: 1, Name: "Test", Score: "8.5"}
+	friends := []models.FriendRating{{UserID: 1, Username: "Alice", Score: 9}, {UserID: 2, Username: "bob_k", Score: 7}, {UserID: 3, Score: 5}}
+
+	result := FormatAnimeMessageWithRating(ru, defaults, anime, false, nil, nil, nil, nil, friends)
+	if !strings.Contains(result, "⭐ Общая оценка: 8.5\n👥 Друзья: Alice 9, bob\\_k 7, id3 5\n") {
+		t.Errorf("expected friends line after the score, got %q", result)
+	}
+
+	if FormatFriendRatings(ru, nil) != "" {
+		t.Error("expected no line without friends")
+	}
+}
+
+func TestFormatAnimeMessageWithRating_English(t *testing.T) {
+	en := i18n.For(i18n.English)
+	anime := &models.Anime{
+		Name:    "Mushishi",
+		Russian: "Мастер Муси",
+		Genres:  []models.Genre{{Name: "Mystery", Russian: "Мистика"}},
+	}
+
+	result := FormatAnimeMessageWithRating(en, defaults, anime, true, &models.Rating{Score: 9}, nil, nil, nil, nil)
+
+	for _, want := range []string{"🎬 Mushishi\n", "Genres: Mystery", "Your rating: 9", "In favorites", "Description: none"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in english card, got %q", want, result)
+		}
+	}
+	if strings.Contains(result, "Мастер Муси") || strings.Contains(result, "Мистика") {
+		t.Errorf("expected no russian titles in english card, got %q", result)
+	}
+}
+
+func TestAnimeTitle(t *testing.T) {
+	anime := &models.Anime{Name: "Mushishi", Russian: "Мастер Муси"}
+	if got := AnimeTitle(ru, defaults, anime); got != "Мастер Муси" {
+		t.Errorf("expected russian title, got %q", got)
+	}
+	if got := AnimeTitle(i18n.For(i18n.English), defaults, anime); got != "Mushishi" {
+		t.Errorf("expected romaji title, got %q", got)
+	}
+	if got := AnimeTitle(ru, defaults, &models.Anime{Name: "Mushishi"}); got != "Mushishi" {
+		t.Errorf("expected fallback to name, got %q", got)
+	}
+}
+
+func TestFormatAnimeMessageWithRating_TitleDisplay(t *testing.T) {
+	anime := &models.Anime{Name: "Mushishi", Russian: "Мастер Муси"}
+
+	cases := map[string]string{
+		models.TitleDisplayRomaji:  "🎬 Mushishi\n\n",
+		models.TitleDisplayRussian: "🎬 Мастер Муси\n\n",
+		models.TitleDisplayBoth:    "🎬 Mushishi\nМастер Муси\n\n",
+	}
+	for display, want := range cases {
+		settings := models.UserSettings{TitleDisplay: display}
+		result := FormatAnimeMessageWithRating(i18n.For(i18n.English), settings, anime, false, nil, nil, nil, nil, nil)
+		if !strings.HasPrefix(result, want) {
+			t.Errorf("%s: expected heading %q, got %q", display, want, result)
+		}
+	}
+
+	if got := AnimeTitle(i18n.For(i18n.English), models.UserSettings{TitleDisplay: models.TitleDisplayRussian}, anime); got != "Мастер Муси" {
+		t.Errorf("expected the russian title to override the language, got %q", got)
+	}
+}
+
+func TestFormatAnimeMessageWithRating_HideSpoilers(t *testing.T) {
+	anime := &models.Anime{Name: "Monster", Description: "The killer is Johan"}
+
+	result := FormatAnimeMessageWithRating(ru, models.UserSettings{HideSpoilers: true}, anime, false, nil, nil, nil, nil, nil)
+	if strings.Contains(result, "Johan") {
+		t.Errorf("expected the description hidden, got %q", result)
+	}
+	if !strings.Contains(result, "/settings") {
+		t.Errorf("expected a hint where to show it, got %q", result)
+	}
+
+	result = FormatAnimeMessageWithRating(ru, models.UserSettings{HideSpoilers: true}, &models.Anime{Name: "Monster"}, false, nil, nil, nil, nil, nil)
+	if !strings.Contains(result, "Описание: нет") {
+		t.Errorf("expected an empty description to stay empty, got %q", result)
+	}
+}
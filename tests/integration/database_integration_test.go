@@ -202,7 +202,7 @@ func (suite *DatabaseIntegrationSuite) TestRatingOperations() {
 	assert.NoError(suite.T(), err)
 
 	retrieved, err = suite.repository.GetRating(userID, animeID)
-	assert.NoError(suite.T(), err)
+	assert.ErrorIs(suite.T(), err, database.ErrNotFound)
 	assert.Nil(suite.T(), retrieved)
 }
 
@@ -22,7 +22,7 @@ func NewMockShikimoriClient() *MockShikimoriClient {
 	}
 }
 
-func (m *MockShikimoriClient) SearchAnime(query string, limit int) ([]models.Anime, error) {
+func (m *MockShikimoriClient) SearchAnime(query string, limit int, censored bool) ([]models.Anime, error) {
 	m.mu.Lock()
 	m.searchCallCount++
 	m.mu.Unlock()
@@ -56,6 +56,18 @@ func (m *MockShikimoriClient) GetAnimeById(id int) (*models.Anime, error) {
 	return nil, fmt.Errorf("anime with id %d not found", id)
 }
 
+func (m *MockShikimoriClient) GetSimilarAnime(id int) ([]models.Anime, error) {
+	return nil, nil
+}
+
+func (m *MockShikimoriClient) GetTopAnime(genreID int, limit int) ([]models.Anime, error) {
+	return nil, nil
+}
+
+func (m *MockShikimoriClient) FetchImage(path string) ([]byte, error) {
+	return nil, fmt.Errorf("image %s not found", path)
+}
+
 func (m *MockShikimoriClient) SetSearchResults(query string, animes []models.Anime) {
 	m.mu.Lock()
 	defer m.mu.Unlock()